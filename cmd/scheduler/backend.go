@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourname/go-dist-scheduler/internal/config"
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/memory"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/postgres"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/redis"
+	"github.com/yourname/go-dist-scheduler/internal/usecase"
+)
+
+// backend は、設定で選択したストレージに接続したリポジトリとリーダー選出ロックです。
+type backend struct {
+	taskRepo   domain.TaskRepository
+	jobRepo    domain.JobRepository
+	leaderLock domain.LeaderLock
+	// historyRepo は、終了したジョブの履歴を削除するリポジトリです。jobRepo と同じストレージを使います。
+	historyRepo domain.JobHistoryRepository
+	// healthChecks は、接続先の PostgreSQL や Redis に到達できるかを確認する、レディネスの判定に含める HealthCheck です。
+	healthChecks []usecase.HealthOption
+	// close は、接続を閉じます。
+	close func()
+}
+
+// openBackend は、cfg.Storage.Backend に応じてリポジトリとリーダー選出ロックを初期化します。
+//   - memory: すべてをインメモリで保持します。状態は再起動で失われ、単一インスタンスでのみ動作します。
+//   - postgres: タスク・ジョブ・リーダー選出ロックを PostgreSQL で管理します。
+//   - redis: ジョブを Redis で、タスクとリーダー選出ロックを PostgreSQL で管理します。
+func openBackend(ctx context.Context, cfg *config.Config) (*backend, error) {
+	visibilityTimeout := cfg.Executor.VisibilityTimeout
+
+	if cfg.Storage.Backend == config.BackendMemory {
+		// インメモリのロックは、選出を試みる間隔の3倍の間更新されなければ失効する
+		leaderTTL := 3 * cfg.Scheduler.ElectionInterval
+		jobRepo := memory.NewInMemoryJobRepository(memory.WithVisibilityTimeout(visibilityTimeout))
+		return &backend{
+			taskRepo:    memory.NewInMemoryTaskRepository(),
+			jobRepo:     jobRepo,
+			leaderLock:  memory.NewInMemoryLeaderLock(memory.NewLeaderLockStore(), uuid.New().String(), leaderTTL),
+			historyRepo: jobRepo,
+			close:       func() {},
+		}, nil
+	}
+
+	db, err := postgres.NewClient(cfg.Database.DSN())
+	if err != nil {
+		return nil, err
+	}
+	b := &backend{
+		taskRepo:     postgres.NewTaskRepository(db),
+		leaderLock:   postgres.NewLeaderLock(db, postgres.DefaultLeaderLockKey),
+		healthChecks: []usecase.HealthOption{usecase.WithHealthCheck("postgres", db.PingContext)},
+		close: func() {
+			_ = db.Close()
+		},
+	}
+
+	if cfg.Storage.Backend == config.BackendPostgres {
+		jobRepo := postgres.NewJobRepository(db, postgres.WithVisibilityTimeout(visibilityTimeout))
+		b.jobRepo = jobRepo
+		b.historyRepo = jobRepo
+		return b, nil
+	}
+
+	connectCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	redisClient, err := redis.NewClient(connectCtx, cfg.Redis.Addr())
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	jobRepo := redis.NewJobRepository(redisClient, redis.WithVisibilityTimeout(visibilityTimeout))
+	b.jobRepo = jobRepo
+	b.historyRepo = jobRepo
+	b.healthChecks = append(b.healthChecks, usecase.WithHealthCheck("redis", func(ctx context.Context) error {
+		return redisClient.Ping(ctx).Err()
+	}))
+	b.close = func() {
+		_ = db.Close()
+		_ = redisClient.Close()
+	}
+	return b, nil
+}
@@ -2,33 +2,130 @@ package main
 
 import (
 	"context"
-	"log"
+	"errors"
+	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // タスクのタイムゾーンを、zoneinfo のない環境でも解決できるようにする
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/yourname/go-dist-scheduler/internal/api"
+	"github.com/yourname/go-dist-scheduler/internal/config"
 	"github.com/yourname/go-dist-scheduler/internal/domain"
-	"github.com/yourname/go-dist-scheduler/internal/infrastructure/memory"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/metrics"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/tracing"
 	"github.com/yourname/go-dist-scheduler/internal/usecase"
+	"go.opentelemetry.io/otel"
 )
 
+// apiAddr は、管理用REST APIサーバーとメトリクスエンドポイント（/metrics）、
+// ヘルスチェックエンドポイント（/healthz・/readyz）がリッスンするアドレスです。
+const apiAddr = ":8080"
+
 func main() {
-	log.Println("Starting go-dist-scheduler...")
+	// `scheduler migrate ...` はスケジューラーを起動せず、マイグレーションだけを実行する
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:], os.Stdout))
+	}
+
+	// 設定の読み込み（CONFIG_FILE で指定したYAMLファイルを環境変数で上書きする）
+	cfg, err := config.Load()
+	if err != nil {
+		slog.Error("failed to load config", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	// ロガーの初期化（LOG_FORMAT で text / json を切り替える）。
+	// slog.SetDefault により、標準の log パッケージの出力も同じ形式になる
+	logger, err := cfg.Log.NewLogger(os.Stderr)
+	if err != nil {
+		slog.Error("failed to create logger", slog.Any("error", err))
+		os.Exit(1)
+	}
+	slog.SetDefault(logger)
+
+	logger.Info("starting go-dist-scheduler", slog.String("backend", cfg.Storage.Backend))
 
-	// インメモリリポジトリの初期化
-	taskRepo := memory.NewInMemoryTaskRepository()
-	jobRepo := memory.NewInMemoryJobRepository()
+	// SIGINT・SIGTERM を受け取るとキャンセルされ、グレースフルシャットダウンを開始する
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// トレースの初期化。TRACING_ENDPOINT を設定した場合は、ジョブごとのトレースを OTLP で送信する
+	// グローバルな TracerProvider を設定する。未設定の場合はスパンを記録しない
+	if cfg.Tracing.Endpoint != "" {
+		tp, err := tracing.NewTracerProvider(ctx, cfg.Tracing.Endpoint,
+			tracing.WithSampleRatio(cfg.Tracing.SampleRatio),
+			tracing.WithServiceName(cfg.Tracing.ServiceName),
+		)
+		if err != nil {
+			logger.Error("failed to create tracer provider", slog.Any("error", err))
+			os.Exit(1)
+		}
+		otel.SetTracerProvider(tp)
+		// 終了時に、送信していないスパンを送信する
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := tp.Shutdown(shutdownCtx); err != nil {
+				logger.Error("failed to shut down tracer provider", slog.Any("error", err))
+			}
+		}()
+		logger.Info("exporting traces", slog.String("endpoint", cfg.Tracing.Endpoint), slog.Float64("sample_ratio", cfg.Tracing.SampleRatio))
+	}
+
+	// 設定で選択したストレージに接続する
+	store, err := openBackend(ctx, cfg)
+	if err != nil {
+		logger.Error("failed to open backend", slog.String("backend", cfg.Storage.Backend), slog.Any("error", err))
+		os.Exit(1)
+	}
+	defer store.close()
+
+	// メトリクスの初期化
+	registry := prometheus.NewRegistry()
+	schedulerMetrics := metrics.New(registry)
+
+	// リポジトリをメトリクスを記録するデコレーターでラップする
+	taskRepo := schedulerMetrics.InstrumentTaskRepository(store.taskRepo)
+	jobRepo := schedulerMetrics.InstrumentJobRepository(store.jobRepo)
+	registry.MustRegister(metrics.NewQueueDepthCollector(jobRepo, metrics.WithQueueDepthLogger(logger)))
 
 	// ユースケースの初期化（DI）
-	scheduler := usecase.NewScheduler(taskRepo, jobRepo)
-	executor := usecase.NewExecutor(jobRepo)
+	scheduler := usecase.NewScheduler(taskRepo, jobRepo,
+		usecase.WithSchedulerObserver(schedulerMetrics),
+		usecase.WithSchedulerLogger(logger),
+	)
+	httpClient := &http.Client{Timeout: cfg.Executor.RequestTimeout}
+	executor := usecase.NewExecutor(taskRepo, jobRepo, httpClient,
+		usecase.WithHeartbeatInterval(cfg.Executor.HeartbeatInterval),
+		usecase.WithExecutorObserver(schedulerMetrics),
+		usecase.WithExecutorLogger(logger),
+	)
+	workerPool := usecase.NewWorkerPool(executor, cfg.Executor.Workers, cfg.Executor.PollInterval,
+		usecase.WithWorkerPoolLogger(logger),
+	)
+	reaper := usecase.NewReaper(taskRepo, jobRepo, usecase.WithReaperLogger(logger))
+	elector := usecase.NewLeaderElector(store.leaderLock, cfg.Scheduler.ElectionInterval,
+		usecase.WithLeaderElectorLogger(logger),
+	)
 
-	ctx := context.Background()
+	// ヘルスチェックの初期化
+	// 接続先の PostgreSQL・Redis に到達できるかと、スケジューリングループが停滞していないかをレディネスに含める
+	loopMonitor := usecase.NewLoopMonitor()
+	healthOpts := append([]usecase.HealthOption{
+		usecase.WithLoopMonitor(loopMonitor, cfg.Scheduler.StaleThreshold),
+		usecase.WithLeaderElector(elector),
+		usecase.WithWorkerPool(workerPool),
+	}, store.healthChecks...)
+	healthHandler := api.NewHealthHandler(usecase.NewHealth(healthOpts...), api.WithHealthHandlerLogger(logger))
 
 	// サンプルタスクの登録（1分ごとに実行）
-	// 注: このタスクはデモンストレーション用です。実際のHTTPリクエストは送信されません。
+	// 注: このタスクはデモンストレーション用です。状態が再起動で失われるインメモリのストレージでのみ登録します。
+	// 送信先のURLは適宜変更してください。
 	sampleTask := &domain.Task{
 		ID:             uuid.New().String(),
 		Name:           "Sample Task",
@@ -41,44 +138,74 @@ func main() {
 			},
 			Body: []byte(`{"message":"Hello from scheduler"}`),
 		},
-		Status:    domain.TaskStatusActive,
+		Status: domain.TaskStatusActive,
+		// 失敗時は指数バックオフで最大3回までリトライ
+		RetryPolicy: domain.RetryPolicy{
+			MaxRetries:      3,
+			Backoff:         domain.BackoffExponential,
+			InitialInterval: 5 * time.Second,
+			MaxInterval:     time.Minute,
+			Jitter:          0.2,
+		},
+		// 1回の実行は10秒まで。超えた場合はタイムアウトとしてリトライする
+		Timeout:   10 * time.Second,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
-	if err := taskRepo.Save(ctx, sampleTask); err != nil {
-		log.Fatalf("Failed to save sample task: %v", err)
+	if cfg.Storage.Backend == config.BackendMemory {
+		if err := taskRepo.Save(ctx, sampleTask); err != nil {
+			logger.Error("failed to save sample task", slog.Any("error", err))
+			os.Exit(1)
+		}
+		logger.Info("registered sample task", slog.String("task_id", sampleTask.ID), slog.String("name", sampleTask.Name))
 	}
-	log.Printf("Registered sample task: %s (ID: %s)", sampleTask.Name, sampleTask.ID)
-
-	// 1秒ごとにスケジューラーとエグゼキューターを実行
-	ticker := time.NewTicker(1 * time.Second)
-	defer ticker.Stop()
 
-	// シグナルハンドリングによるグレースフルシャットダウン
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	// 管理用REST APIサーバーとメトリクスエンドポイントをバックグラウンドで実行
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", metrics.Handler(registry))
+	mux.HandleFunc("GET /healthz", healthHandler.Healthz)
+	mux.HandleFunc("GET /readyz", healthHandler.Readyz)
+	mux.Handle("/", api.NewServer(taskRepo, jobRepo, api.WithServerLogger(logger)))
+	apiServer := &http.Server{
+		Addr:              apiAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		logger.Info("API server listening", slog.String("addr", apiAddr))
+		if err := apiServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("API server error", slog.Any("error", err))
+		}
+	}()
 
-	log.Println("Scheduler loop started. Checking every 1 second...")
-	log.Println("Press Ctrl+C to stop")
+	// スケジューリングループ・ワーカープール・リーダー選出を実行する。
+	// シグナルを受け取ると、ティッカーと新しいジョブの取り出しを停止して実行中のジョブの完了を猶予期間まで待ち、
+	// 終わらなかったジョブは Pending に戻して他のインスタンスに引き継ぐ
+	lifecycleOpts := []usecase.LifecycleOption{
+		usecase.WithGracePeriod(cfg.Scheduler.ShutdownGracePeriod),
+		usecase.WithLifecycleLoopMonitor(loopMonitor),
+		usecase.WithLifecycleLogger(logger),
+	}
 
-	for {
-		select {
-		case <-ticker.C:
-			now := time.Now()
+	// 保持期間・保持件数を超えた終了済みジョブの履歴を、リーダーが定期的に削除する
+	retention := domain.RetentionPolicy{
+		Completed: domain.RetentionRule{MaxAge: cfg.Retention.MaxAge, MaxJobs: cfg.Retention.MaxJobs},
+		Failed:    domain.RetentionRule{MaxAge: cfg.Retention.FailedMaxAge, MaxJobs: cfg.Retention.FailedMaxJobs},
+	}
+	pruner := usecase.NewPruner(taskRepo, schedulerMetrics.InstrumentJobHistoryRepository(store.historyRepo), retention,
+		usecase.WithPruneBatchSize(cfg.Retention.BatchSize),
+		usecase.WithPrunerObserver(schedulerMetrics),
+		usecase.WithPrunerLogger(logger),
+	)
+	lifecycleOpts = append(lifecycleOpts, usecase.WithPruner(pruner, cfg.Retention.Interval))
 
-			// スケジューラー: タスクをチェックしてジョブをエンキュー
-			if err := scheduler.CheckAndEnqueue(ctx, now); err != nil {
-				log.Printf("Error in CheckAndEnqueue: %v", err)
-			}
+	lifecycle := usecase.NewLifecycle(scheduler, reaper, elector, workerPool, cfg.Scheduler.TickInterval, lifecycleOpts...)
+	lifecycle.Run(ctx)
 
-			// エグゼキューター: ペンディング中のジョブを実行
-			if err := executor.RunPendingJob(ctx); err != nil {
-				log.Printf("Error in RunPendingJob: %v", err)
-			}
-		case sig := <-sigCh:
-			log.Printf("Received signal: %v. Shutting down gracefully...", sig)
-			return
-		}
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+	if err := apiServer.Shutdown(shutdownCtx); err != nil {
+		logger.Error("failed to shut down API server", slog.Any("error", err))
 	}
 }
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/yourname/go-dist-scheduler/db/migrations"
+	"github.com/yourname/go-dist-scheduler/internal/config"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/postgres"
+)
+
+const migrateUsage = "usage: scheduler migrate up|down|status"
+
+// runMigrate は、`scheduler migrate` サブコマンドを実行し、終了コードを返します。
+//   - up: 未適用のマイグレーションをすべて適用します。
+//   - down: 最後に適用したマイグレーションを1つロールバックします。
+//   - status: 各マイグレーションの適用状況を表示します。
+//
+// マイグレーションはバイナリに埋め込まれているため、外部ツールは不要です。
+func runMigrate(args []string, stdout io.Writer) int {
+	if len(args) != 1 || (args[0] != "up" && args[0] != "down" && args[0] != "status") {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		slog.Error("failed to load config", slog.Any("error", err))
+		return 1
+	}
+	if cfg.Storage.Backend == config.BackendMemory {
+		slog.Error("migrations require a PostgreSQL backend", slog.String("backend", cfg.Storage.Backend))
+		return 1
+	}
+
+	all, err := postgres.LoadMigrations(migrations.FS)
+	if err != nil {
+		slog.Error("failed to load migrations", slog.Any("error", err))
+		return 1
+	}
+
+	db, err := postgres.NewClient(cfg.Database.DSN())
+	if err != nil {
+		slog.Error("failed to connect to database", slog.Any("error", err))
+		return 1
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	// 他のインスタンスがマイグレーション中の場合はロックの解放を待つため、シグナルで中断できるようにする
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	migrator := postgres.NewMigrator(db, all)
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Fprintf(stdout, "applied %s\n", m)
+		}
+		if err != nil {
+			slog.Error("failed to apply migrations", slog.Any("error", err))
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(stdout, "no migrations to apply")
+		}
+	case "down":
+		rolledBack, err := migrator.Down(ctx)
+		if err != nil {
+			slog.Error("failed to roll back migration", slog.Any("error", err))
+			return 1
+		}
+		if rolledBack == nil {
+			fmt.Fprintln(stdout, "no migrations to roll back")
+		} else {
+			fmt.Fprintf(stdout, "rolled back %s\n", rolledBack)
+		}
+	default: // status
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			slog.Error("failed to get migration status", slog.Any("error", err))
+			return 1
+		}
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.IsApplied() {
+				appliedAt = "applied at " + s.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(stdout, "%s\t%s\n", s.Migration, appliedAt)
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,44 @@
+-- tasks table
+-- The payload column stores HTTPRequestInfo as JSON with the following structure:
+-- {
+--   "url": "string",
+--   "method": "string",
+--   "headers": {"key": "value", ...},
+--   "body": "base64-encoded string"
+-- }
+-- The retry_policy column stores RetryPolicy as JSON with the following structure:
+-- {
+--   "max_retries": 3,
+--   "backoff": 0,                  -- 0: fixed, 1: linear, 2: exponential
+--   "initial_interval_ms": 1000,
+--   "max_interval_ms": 60000,      -- 0 means no upper bound
+--   "jitter": 0.2
+-- }
+-- The misfire_policy column stores MisfirePolicy as JSON with the following structure:
+-- {
+--   "strategy": 0,                 -- 0: fire all, 1: fire latest, 2: skip, 3: fire limited
+--   "max_runs": 10,                -- 0 means no limit
+--   "max_age_ms": 3600000          -- 0 means no limit
+-- }
+CREATE TABLE IF NOT EXISTS tasks (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    cron_expression VARCHAR(255) NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT '', -- IANA time zone name; empty means UTC
+    payload JSONB NOT NULL,
+    status INTEGER NOT NULL DEFAULT 0,
+    retry_policy JSONB NOT NULL DEFAULT '{}',
+    misfire_policy JSONB NOT NULL DEFAULT '{}',
+    concurrency_policy INTEGER NOT NULL DEFAULT 0, -- 0: allow, 1: forbid, 2: replace
+    timeout_ms BIGINT NOT NULL DEFAULT 0, -- 0 means no timeout
+    status_history JSONB NOT NULL DEFAULT '[]', -- who paused or resumed the task and why, oldest first
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_checked_at TIMESTAMP NULL
+);
+
+-- Index for querying active tasks
+CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(status);
+
+-- Index for querying by created_at
+CREATE INDEX IF NOT EXISTS idx_tasks_created_at ON tasks(created_at);
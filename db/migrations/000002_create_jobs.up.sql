@@ -0,0 +1,30 @@
+-- jobs table
+-- Each row is a single execution of a task. Pending jobs form the queue that
+-- executors claim with SELECT ... FOR UPDATE SKIP LOCKED.
+CREATE TABLE IF NOT EXISTS jobs (
+    id UUID PRIMARY KEY,
+    task_id UUID NOT NULL,
+    scheduled_at TIMESTAMP NOT NULL,
+    started_at TIMESTAMP NULL,
+    finished_at TIMESTAMP NULL,
+    status INTEGER NOT NULL DEFAULT 0,
+    retry_count INTEGER NOT NULL DEFAULT 0,
+    available_at TIMESTAMP NULL,
+    -- 0 = created by the cron schedule, 1 = triggered manually.
+    trigger_source INTEGER NOT NULL DEFAULT 0,
+    -- W3C trace context (traceparent, tracestate) of the scheduling span.
+    trace_context JSONB NOT NULL DEFAULT '{}',
+    -- Running jobs whose lease is not extended before this time are reclaimed by the reaper.
+    lease_expires_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- Guarantees that a task is enqueued at most once per scheduled time.
+    -- The underlying index also serves queries by task_id.
+    CONSTRAINT uq_jobs_task_id_scheduled_at UNIQUE (task_id, scheduled_at)
+);
+
+-- Index for dequeuing pending jobs in scheduled order
+CREATE INDEX IF NOT EXISTS idx_jobs_status_scheduled_at ON jobs(status, scheduled_at);
+
+-- Index for finding running jobs with expired leases
+CREATE INDEX IF NOT EXISTS idx_jobs_status_lease_expires_at ON jobs(status, lease_expires_at);
@@ -0,0 +1,24 @@
+-- job_results table
+-- Each row is the outcome of a single attempt of a job, including retries.
+-- status_code is 0 when no response was received. response_headers stores the
+-- response headers as a JSON object and response_body is truncated to 64 KiB.
+CREATE TABLE IF NOT EXISTS job_results (
+    id BIGSERIAL PRIMARY KEY,
+    job_id UUID NOT NULL,
+    task_id UUID NOT NULL,
+    attempt INTEGER NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    response_headers JSONB NOT NULL DEFAULT '{}',
+    response_body BYTEA NULL,
+    body_truncated BOOLEAN NOT NULL DEFAULT FALSE,
+    error TEXT NOT NULL DEFAULT '',
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP NOT NULL,
+    duration_ms BIGINT NOT NULL DEFAULT 0
+);
+
+-- Index for fetching the results of a job
+CREATE INDEX IF NOT EXISTS idx_job_results_job_id ON job_results(job_id, attempt);
+
+-- Index for listing the recent results of a task
+CREATE INDEX IF NOT EXISTS idx_job_results_task_id_finished_at ON job_results(task_id, finished_at DESC);
@@ -0,0 +1,7 @@
+-- The retention_policy column stores RetentionPolicy as JSON with the following structure:
+-- {
+--   "completed": {"max_age_ms": 86400000, "max_jobs": 100},  -- succeeded or cancelled jobs
+--   "failed": {"max_age_ms": 604800000, "max_jobs": 1000}    -- failed or timed out jobs
+-- }
+-- 0 means no limit. When both rules are 0 (or the object is empty), the global retention settings apply.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS retention_policy JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,3 @@
+-- version is incremented on every save of a task and is used to detect conflicting updates (optimistic locking).
+-- Updating last_checked_at alone does not change it.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,9 @@
+-- The retry_policy column stores RetryPolicy as JSON with the following structure:
+-- {
+--   "max_retries": 3,
+--   "backoff": 0,                  -- 0: fixed, 1: linear, 2: exponential
+--   "initial_interval_ms": 1000,
+--   "max_interval_ms": 60000,      -- 0 means no upper bound
+--   "jitter": 0.2
+-- }
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS retry_policy JSONB NOT NULL DEFAULT '{}';
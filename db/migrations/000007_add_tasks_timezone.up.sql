@@ -0,0 +1,2 @@
+-- IANA time zone name in which the cron expression is evaluated; empty means UTC.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+-- The misfire_policy column stores MisfirePolicy as JSON with the following structure:
+-- {
+--   "strategy": 0,                 -- 0: fire all, 1: fire latest, 2: skip, 3: fire limited
+--   "max_runs": 10,                -- 0 means no limit
+--   "max_age_ms": 3600000          -- 0 means no limit
+-- }
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS misfire_policy JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,2 @@
+-- 0: allow, 1: forbid, 2: replace
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS concurrency_policy INTEGER NOT NULL DEFAULT 0;
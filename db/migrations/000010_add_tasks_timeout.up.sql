@@ -0,0 +1,2 @@
+-- Execution timeout of each job of the task; 0 means no timeout.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS timeout_ms BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- Who paused or resumed the task and why, oldest first.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS status_history JSONB NOT NULL DEFAULT '[]';
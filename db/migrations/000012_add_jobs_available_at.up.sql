@@ -0,0 +1,2 @@
+-- Jobs waiting for a retry with backoff are not dequeued before available_at; NULL means immediately.
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS available_at TIMESTAMP NULL;
@@ -0,0 +1 @@
+ALTER TABLE jobs DROP CONSTRAINT IF EXISTS uq_jobs_task_id_scheduled_at;
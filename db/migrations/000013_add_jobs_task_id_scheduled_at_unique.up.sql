@@ -0,0 +1,9 @@
+-- Guarantees that a task is enqueued at most once per scheduled time.
+-- The underlying index also serves queries by task_id.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'uq_jobs_task_id_scheduled_at') THEN
+        ALTER TABLE jobs ADD CONSTRAINT uq_jobs_task_id_scheduled_at UNIQUE (task_id, scheduled_at);
+    END IF;
+END
+$$;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_jobs_status_lease_expires_at;
+ALTER TABLE jobs DROP COLUMN IF EXISTS lease_expires_at;
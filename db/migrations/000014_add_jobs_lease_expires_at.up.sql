@@ -0,0 +1,5 @@
+-- Running jobs whose lease is not extended before this time are reclaimed by the reaper.
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS lease_expires_at TIMESTAMP NULL;
+
+-- Index for finding running jobs with expired leases
+CREATE INDEX IF NOT EXISTS idx_jobs_status_lease_expires_at ON jobs(status, lease_expires_at);
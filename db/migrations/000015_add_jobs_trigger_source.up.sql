@@ -0,0 +1,2 @@
+-- 0 = created by the cron schedule, 1 = triggered manually.
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS trigger_source INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- W3C trace context (traceparent, tracestate) of the scheduling span.
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS trace_context JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,13 @@
+// Package migrations embeds the versioned PostgreSQL schema migrations.
+//
+// Each migration consists of a pair of files named <version>_<name>.up.sql and
+// <version>_<name>.down.sql. Versions are applied in ascending order and must
+// never be edited once released; add a new version instead.
+package migrations
+
+import "embed"
+
+// FS contains the migration files.
+//
+//go:embed *.sql
+var FS embed.FS
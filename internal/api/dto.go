@@ -0,0 +1,475 @@
+package api
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+)
+
+// taskRequest は、タスクの作成・更新リクエストのボディです。
+type taskRequest struct {
+	Name              string               `json:"name"`
+	CronExpression    string               `json:"cron_expression"`
+	Timezone          string               `json:"timezone,omitempty"`
+	Payload           payloadJSON          `json:"payload"`
+	RetryPolicy       *retryPolicyJSON     `json:"retry_policy,omitempty"`
+	MisfirePolicy     *misfirePolicyJSON   `json:"misfire_policy,omitempty"`
+	ConcurrencyPolicy string               `json:"concurrency_policy,omitempty"`
+	Timeout           string               `json:"timeout,omitempty"`
+	RetentionPolicy   *retentionPolicyJSON `json:"retention_policy,omitempty"`
+}
+
+// taskResponse は、タスクのレスポンスボディです。
+type taskResponse struct {
+	ID                string               `json:"id"`
+	Name              string               `json:"name"`
+	CronExpression    string               `json:"cron_expression"`
+	Timezone          string               `json:"timezone,omitempty"`
+	Payload           payloadJSON          `json:"payload"`
+	Status            string               `json:"status"`
+	RetryPolicy       retryPolicyJSON      `json:"retry_policy"`
+	MisfirePolicy     misfirePolicyJSON    `json:"misfire_policy"`
+	ConcurrencyPolicy string               `json:"concurrency_policy"`
+	Timeout           string               `json:"timeout,omitempty"`
+	RetentionPolicy   *retentionPolicyJSON `json:"retention_policy,omitempty"`
+	CreatedAt         time.Time            `json:"created_at"`
+	UpdatedAt         time.Time            `json:"updated_at"`
+	LastCheckedAt     *time.Time           `json:"last_checked_at,omitempty"`
+	StatusHistory     []statusChangeJSON   `json:"status_history,omitempty"`
+}
+
+// pauseRequest は、タスクの一時停止リクエストのボディです。
+type pauseRequest struct {
+	Actor  string `json:"actor"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// resumeRequest は、タスクの再開リクエストのボディです。
+// mode には、一時停止中の実行時刻を読み飛ばす "from_now" か、エンキューする "backfill" を指定します。
+type resumeRequest struct {
+	Actor  string `json:"actor"`
+	Reason string `json:"reason,omitempty"`
+	Mode   string `json:"mode"`
+}
+
+// statusChangeJSON は、タスクのステータス変更履歴の1件のJSON表現です。
+type statusChangeJSON struct {
+	Status    string    `json:"status"`
+	Actor     string    `json:"actor"`
+	Reason    string    `json:"reason,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// payloadJSON は、タスクが送信するHTTPリクエストのJSON表現です。ボディは文字列として扱います。
+type payloadJSON struct {
+	URL     string            `json:"url"`
+	Method  string            `json:"method,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+	Signing *signingJSON      `json:"signing,omitempty"`
+}
+
+// signingJSON は、リクエストへの HMAC-SHA256 署名の設定のJSON表現です。
+// 秘密鍵はレスポンスに含めず、代わりに登録されている鍵の数を secret_count で返します。secret_count は読み取り専用です。
+// タスクの更新で secrets を省略した場合は登録済みの秘密鍵を維持し、署名をやめる場合は disabled を指定します。
+type signingJSON struct {
+	Secrets         []string `json:"secrets,omitempty"`
+	SecretCount     int      `json:"secret_count,omitempty"`
+	SignatureHeader string   `json:"signature_header,omitempty"`
+	TimestampHeader string   `json:"timestamp_header,omitempty"`
+	Disabled        bool     `json:"disabled,omitempty"`
+}
+
+// retryPolicyJSON は、リトライポリシーのJSON表現です。待機時間は "5s" のような Go の期間表記で指定します。
+type retryPolicyJSON struct {
+	MaxRetries      int     `json:"max_retries"`
+	Backoff         string  `json:"backoff"`
+	InitialInterval string  `json:"initial_interval,omitempty"`
+	MaxInterval     string  `json:"max_interval,omitempty"`
+	Jitter          float64 `json:"jitter,omitempty"`
+}
+
+// misfirePolicyJSON は、ミスファイアポリシーのJSON表現です。max_age は "1h" のような Go の期間表記で指定します。
+type misfirePolicyJSON struct {
+	Strategy string `json:"strategy"`
+	MaxRuns  int    `json:"max_runs,omitempty"`
+	MaxAge   string `json:"max_age,omitempty"`
+}
+
+// retentionPolicyJSON は、ジョブの履歴の保持ポリシーのJSON表現です。
+// 省略した場合は、スケジューラー全体の設定に従います。
+type retentionPolicyJSON struct {
+	Completed retentionRuleJSON `json:"completed"`
+	Failed    retentionRuleJSON `json:"failed"`
+}
+
+// retentionRuleJSON は、ジョブの履歴の保持ルールのJSON表現です。max_age は "720h" のような Go の期間表記で指定します。
+type retentionRuleJSON struct {
+	MaxAge  string `json:"max_age,omitempty"`
+	MaxJobs int    `json:"max_jobs,omitempty"`
+}
+
+// jobResponse は、ジョブのレスポンスボディです。
+type jobResponse struct {
+	ID             string               `json:"id"`
+	TaskID         string               `json:"task_id"`
+	ScheduledAt    time.Time            `json:"scheduled_at"`
+	Status         string               `json:"status"`
+	RetryCount     int                  `json:"retry_count"`
+	TriggerSource  string               `json:"trigger_source"`
+	AvailableAt    *time.Time           `json:"available_at,omitempty"`
+	StartedAt      *time.Time           `json:"started_at,omitempty"`
+	FinishedAt     *time.Time           `json:"finished_at,omitempty"`
+	LeaseExpiresAt *time.Time           `json:"lease_expires_at,omitempty"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+	Results        []*jobResultResponse `json:"results,omitempty"`
+}
+
+// jobResultResponse は、ジョブの1回の試行の実行結果のレスポンスボディです。
+type jobResultResponse struct {
+	Attempt         int               `json:"attempt"`
+	StatusCode      int               `json:"status_code"`
+	ResponseHeaders map[string]string `json:"response_headers,omitempty"`
+	ResponseBody    string            `json:"response_body,omitempty"`
+	BodyTruncated   bool              `json:"body_truncated"`
+	Error           string            `json:"error,omitempty"`
+	StartedAt       time.Time         `json:"started_at"`
+	FinishedAt      time.Time         `json:"finished_at"`
+	DurationMS      int64             `json:"duration_ms"`
+}
+
+var (
+	taskStatusNames = map[domain.TaskStatus]string{
+		domain.TaskStatusActive: "active",
+		domain.TaskStatusPaused: "paused",
+	}
+	jobStatusNames = map[domain.JobStatus]string{
+		domain.JobStatusPending:   "pending",
+		domain.JobStatusRunning:   "running",
+		domain.JobStatusSuccess:   "success",
+		domain.JobStatusFailed:    "failed",
+		domain.JobStatusCancelled: "cancelled",
+		domain.JobStatusTimedOut:  "timed_out",
+	}
+	resumeModeNames = map[domain.ResumeMode]string{
+		domain.ResumeFromNow:  "from_now",
+		domain.ResumeBackfill: "backfill",
+	}
+	triggerSourceNames = map[domain.TriggerSource]string{
+		domain.TriggerSourceCron:   "cron",
+		domain.TriggerSourceManual: "manual",
+	}
+	backoffNames = map[domain.BackoffStrategy]string{
+		domain.BackoffFixed:       "fixed",
+		domain.BackoffLinear:      "linear",
+		domain.BackoffExponential: "exponential",
+	}
+	misfireStrategyNames = map[domain.MisfireStrategy]string{
+		domain.MisfireFireAll:     "fire_all",
+		domain.MisfireFireLatest:  "fire_latest",
+		domain.MisfireSkip:        "skip",
+		domain.MisfireFireLimited: "fire_limited",
+	}
+	concurrencyPolicyNames = map[domain.ConcurrencyPolicy]string{
+		domain.ConcurrencyAllow:   "allow",
+		domain.ConcurrencyForbid:  "forbid",
+		domain.ConcurrencyReplace: "replace",
+	}
+)
+
+// apply は、リクエストの内容を task に反映します。ID・ステータス・タイムスタンプは変更しません。
+// 秘密鍵はレスポンスに含まれないため、署名の設定は applySigning の規則に従って既存の設定を引き継ぎます。
+func (req *taskRequest) apply(task *domain.Task) error {
+	signing, err := req.Payload.Signing.applySigning(task.Payload.Signing)
+	if err != nil {
+		return err
+	}
+
+	task.Name = req.Name
+	task.CronExpression = req.CronExpression
+	task.Timezone = req.Timezone
+	task.Payload = domain.HTTPRequestInfo{
+		URL:     req.Payload.URL,
+		Method:  req.Payload.Method,
+		Headers: req.Payload.Headers,
+		Signing: signing,
+	}
+	if req.Payload.Body != "" {
+		task.Payload.Body = []byte(req.Payload.Body)
+	}
+
+	task.RetryPolicy = domain.RetryPolicy{}
+	if req.RetryPolicy != nil {
+		policy, err := req.RetryPolicy.toDomain()
+		if err != nil {
+			return err
+		}
+		task.RetryPolicy = policy
+	}
+
+	task.MisfirePolicy = domain.MisfirePolicy{}
+	if req.MisfirePolicy != nil {
+		policy, err := req.MisfirePolicy.toDomain()
+		if err != nil {
+			return err
+		}
+		task.MisfirePolicy = policy
+	}
+
+	concurrencyPolicy, ok := lookup(concurrencyPolicyNames, req.ConcurrencyPolicy)
+	if req.ConcurrencyPolicy != "" && !ok {
+		return fmt.Errorf("%w: unknown concurrency policy %q", domain.ErrInvalidArgument, req.ConcurrencyPolicy)
+	}
+	task.ConcurrencyPolicy = concurrencyPolicy
+
+	timeout, err := parseDuration("timeout", req.Timeout)
+	if err != nil {
+		return err
+	}
+	task.Timeout = timeout
+
+	task.RetentionPolicy = domain.RetentionPolicy{}
+	if req.RetentionPolicy != nil {
+		policy, err := req.RetentionPolicy.toDomain()
+		if err != nil {
+			return err
+		}
+		task.RetentionPolicy = policy
+	}
+
+	return nil
+}
+
+// applySigning は、署名の設定のJSON表現を current に反映した SigningPolicy を返します。
+//   - 省略した場合（nil）は、current をそのまま維持します。
+//   - disabled の場合は、署名をやめます。
+//   - それ以外の場合はヘッダー名を置き換え、secrets を指定した場合のみ秘密鍵を置き換えます。
+//
+// secret_count は読み取り専用のため、指定された場合は ErrInvalidArgument をラップしたエラーを返します。
+func (s *signingJSON) applySigning(current domain.SigningPolicy) (domain.SigningPolicy, error) {
+	if s == nil {
+		return current, nil
+	}
+	if s.SecretCount != 0 {
+		return domain.SigningPolicy{}, fmt.Errorf("%w: signing.secret_count is read-only", domain.ErrInvalidArgument)
+	}
+	if s.Disabled {
+		if len(s.Secrets) > 0 || s.SignatureHeader != "" || s.TimestampHeader != "" {
+			return domain.SigningPolicy{}, fmt.Errorf("%w: disabled signing must not have other settings", domain.ErrInvalidArgument)
+		}
+		return domain.SigningPolicy{}, nil
+	}
+
+	policy := domain.SigningPolicy{
+		Secrets:         current.Secrets,
+		SignatureHeader: s.SignatureHeader,
+		TimestampHeader: s.TimestampHeader,
+	}
+	if len(s.Secrets) > 0 {
+		policy.Secrets = s.Secrets
+	}
+	return policy, nil
+}
+
+// toDomain は、リトライポリシーのJSON表現をドメインの RetryPolicy に変換します。
+func (p *retryPolicyJSON) toDomain() (domain.RetryPolicy, error) {
+	policy := domain.RetryPolicy{
+		MaxRetries: p.MaxRetries,
+		Jitter:     p.Jitter,
+	}
+
+	backoff, ok := lookup(backoffNames, p.Backoff)
+	if p.Backoff != "" && !ok {
+		return domain.RetryPolicy{}, fmt.Errorf("%w: unknown backoff %q", domain.ErrInvalidArgument, p.Backoff)
+	}
+	policy.Backoff = backoff
+
+	var err error
+	if policy.InitialInterval, err = parseDuration("initial_interval", p.InitialInterval); err != nil {
+		return domain.RetryPolicy{}, err
+	}
+	if policy.MaxInterval, err = parseDuration("max_interval", p.MaxInterval); err != nil {
+		return domain.RetryPolicy{}, err
+	}
+
+	return policy, nil
+}
+
+// toDomain は、ミスファイアポリシーのJSON表現をドメインの MisfirePolicy に変換します。
+func (p *misfirePolicyJSON) toDomain() (domain.MisfirePolicy, error) {
+	policy := domain.MisfirePolicy{MaxRuns: p.MaxRuns}
+
+	strategy, ok := lookup(misfireStrategyNames, p.Strategy)
+	if p.Strategy != "" && !ok {
+		return domain.MisfirePolicy{}, fmt.Errorf("%w: unknown misfire strategy %q", domain.ErrInvalidArgument, p.Strategy)
+	}
+	policy.Strategy = strategy
+
+	var err error
+	if policy.MaxAge, err = parseDuration("max_age", p.MaxAge); err != nil {
+		return domain.MisfirePolicy{}, err
+	}
+
+	return policy, nil
+}
+
+// toDomain は、保持ポリシーのJSON表現をドメインの RetentionPolicy に変換します。
+func (p *retentionPolicyJSON) toDomain() (domain.RetentionPolicy, error) {
+	completed, err := p.Completed.toDomain("completed")
+	if err != nil {
+		return domain.RetentionPolicy{}, err
+	}
+	failed, err := p.Failed.toDomain("failed")
+	if err != nil {
+		return domain.RetentionPolicy{}, err
+	}
+	return domain.RetentionPolicy{Completed: completed, Failed: failed}, nil
+}
+
+// toDomain は、保持ルールのJSON表現をドメインの RetentionRule に変換します。name はエラーメッセージに使用するルールの名前です。
+func (r *retentionRuleJSON) toDomain(name string) (domain.RetentionRule, error) {
+	maxAge, err := parseDuration(name+".max_age", r.MaxAge)
+	if err != nil {
+		return domain.RetentionRule{}, err
+	}
+	return domain.RetentionRule{MaxAge: maxAge, MaxJobs: r.MaxJobs}, nil
+}
+
+// newRetentionRuleJSON は、ドメインの RetentionRule をJSON表現に変換します。
+func newRetentionRuleJSON(rule domain.RetentionRule) retentionRuleJSON {
+	r := retentionRuleJSON{MaxJobs: rule.MaxJobs}
+	if rule.MaxAge > 0 {
+		r.MaxAge = rule.MaxAge.String()
+	}
+	return r
+}
+
+// parseDuration は、期間表記の文字列を解析します。空文字列は0として扱います。
+func parseDuration(field, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid %s %q", domain.ErrInvalidArgument, field, value)
+	}
+	return d, nil
+}
+
+// lookup は、名前から対応する値を逆引きします。
+func lookup[K comparable](names map[K]string, name string) (K, bool) {
+	for value, n := range names {
+		if n == name {
+			return value, true
+		}
+	}
+	var zero K
+	return zero, false
+}
+
+// newTaskResponse は、ドメインの Task をレスポンスボディに変換します。
+func newTaskResponse(task *domain.Task) *taskResponse {
+	resp := &taskResponse{
+		ID:             task.ID,
+		Name:           task.Name,
+		CronExpression: task.CronExpression,
+		Timezone:       task.Timezone,
+		Payload: payloadJSON{
+			URL:     task.Payload.URL,
+			Method:  task.Payload.Method,
+			Headers: task.Payload.Headers,
+			Body:    string(task.Payload.Body),
+		},
+		Status: taskStatusNames[task.Status],
+		RetryPolicy: retryPolicyJSON{
+			MaxRetries: task.RetryPolicy.MaxRetries,
+			Backoff:    backoffNames[task.RetryPolicy.Backoff],
+			Jitter:     task.RetryPolicy.Jitter,
+		},
+		MisfirePolicy: misfirePolicyJSON{
+			Strategy: misfireStrategyNames[task.MisfirePolicy.Strategy],
+			MaxRuns:  task.MisfirePolicy.MaxRuns,
+		},
+		ConcurrencyPolicy: concurrencyPolicyNames[task.ConcurrencyPolicy],
+		CreatedAt:         task.CreatedAt,
+		UpdatedAt:         task.UpdatedAt,
+		LastCheckedAt:     timePtr(task.LastCheckedAt),
+	}
+	if task.RetryPolicy.InitialInterval > 0 {
+		resp.RetryPolicy.InitialInterval = task.RetryPolicy.InitialInterval.String()
+	}
+	if task.RetryPolicy.MaxInterval > 0 {
+		resp.RetryPolicy.MaxInterval = task.RetryPolicy.MaxInterval.String()
+	}
+	if task.Timeout > 0 {
+		resp.Timeout = task.Timeout.String()
+	}
+	if task.MisfirePolicy.MaxAge > 0 {
+		resp.MisfirePolicy.MaxAge = task.MisfirePolicy.MaxAge.String()
+	}
+	if task.Payload.Signing.Enabled() {
+		resp.Payload.Signing = &signingJSON{
+			SecretCount:     len(task.Payload.Signing.Secrets),
+			SignatureHeader: task.Payload.Signing.SignatureHeaderName(),
+			TimestampHeader: task.Payload.Signing.TimestampHeaderName(),
+		}
+	}
+	if !task.RetentionPolicy.IsZero() {
+		resp.RetentionPolicy = &retentionPolicyJSON{
+			Completed: newRetentionRuleJSON(task.RetentionPolicy.Completed),
+			Failed:    newRetentionRuleJSON(task.RetentionPolicy.Failed),
+		}
+	}
+	for _, change := range task.StatusHistory {
+		resp.StatusHistory = append(resp.StatusHistory, statusChangeJSON{
+			Status:    taskStatusNames[change.Status],
+			Actor:     change.Actor,
+			Reason:    change.Reason,
+			ChangedAt: change.ChangedAt,
+		})
+	}
+	return resp
+}
+
+// newJobResponse は、ドメインの Job をレスポンスボディに変換します。
+func newJobResponse(job *domain.Job) *jobResponse {
+	return &jobResponse{
+		ID:             job.ID,
+		TaskID:         job.TaskID,
+		ScheduledAt:    job.ScheduledAt,
+		Status:         jobStatusNames[job.Status],
+		RetryCount:     job.RetryCount,
+		TriggerSource:  triggerSourceNames[job.TriggerSource],
+		AvailableAt:    timePtr(job.AvailableAt),
+		StartedAt:      timePtr(job.StartedAt),
+		FinishedAt:     timePtr(job.FinishedAt),
+		LeaseExpiresAt: timePtr(job.LeaseExpiresAt),
+		CreatedAt:      job.CreatedAt,
+		UpdatedAt:      job.UpdatedAt,
+	}
+}
+
+// newJobResultResponse は、ドメインの JobResult をレスポンスボディに変換します。
+func newJobResultResponse(result *domain.JobResult) *jobResultResponse {
+	return &jobResultResponse{
+		Attempt:         result.Attempt,
+		StatusCode:      result.StatusCode,
+		ResponseHeaders: result.ResponseHeaders,
+		ResponseBody:    string(result.ResponseBody),
+		BodyTruncated:   result.BodyTruncated,
+		Error:           result.Error,
+		StartedAt:       result.StartedAt,
+		FinishedAt:      result.FinishedAt,
+		DurationMS:      result.Duration.Milliseconds(),
+	}
+}
+
+// timePtr は、ゼロ値の時刻をJSONで省略できるよう nil に変換します。
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
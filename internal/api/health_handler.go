@@ -0,0 +1,96 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/yourname/go-dist-scheduler/internal/usecase"
+)
+
+// HealthHandler は、オーケストレーターから呼び出されるライブネス（/healthz）と
+// レディネス（/readyz）のエンドポイントです。
+type HealthHandler struct {
+	health *usecase.Health
+	logger *slog.Logger
+}
+
+// HealthHandlerOption は、HealthHandler の設定を変更するオプションです。
+type HealthHandlerOption func(*HealthHandler)
+
+// WithHealthHandlerLogger は、HealthHandler がログを出力するロガーを設定します。
+func WithHealthHandlerLogger(logger *slog.Logger) HealthHandlerOption {
+	return func(h *HealthHandler) {
+		h.logger = logger
+	}
+}
+
+// NewHealthHandler は新しいHealthHandlerインスタンスを生成します。
+// ログはデフォルトで slog.Default() に出力します。
+func NewHealthHandler(health *usecase.Health, opts ...HealthHandlerOption) *HealthHandler {
+	h := &HealthHandler{health: health, logger: slog.Default()}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// livenessResponse は、ライブネスのレスポンスボディです。
+type livenessResponse struct {
+	Status string `json:"status"`
+}
+
+// readinessResponse は、レディネスのレスポンスボディです。
+type readinessResponse struct {
+	Status     string                   `json:"status"`
+	Checks     map[string]checkResponse `json:"checks"`
+	LastTickAt *time.Time               `json:"last_tick_at,omitempty"`
+	Leader     bool                     `json:"leader"`
+	Workers    workersResponse          `json:"workers"`
+}
+
+type checkResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type workersResponse struct {
+	Busy  int `json:"busy"`
+	Total int `json:"total"`
+}
+
+// Healthz は、プロセスが応答できることを返します。依存コンポーネントの状態は確認しません。
+func (h *HealthHandler) Healthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(h.logger, w, http.StatusOK, livenessResponse{Status: "ok"})
+}
+
+// Readyz は、レディネスを判定し、すべての確認が正常であれば 200、そうでなければ 503 を返します。
+// いずれの場合も、各確認の結果とリーダーであるか、ワーカーの稼働状況をボディに含めます。
+func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	report := h.health.Readiness(r.Context(), time.Now())
+
+	resp := readinessResponse{
+		Status:     "ready",
+		Checks:     make(map[string]checkResponse, len(report.Checks)),
+		LastTickAt: timePtr(report.LastTickAt),
+		Leader:     report.Leader,
+		Workers: workersResponse{
+			Busy:  report.BusyWorkers,
+			Total: report.Workers,
+		},
+	}
+	for _, c := range report.Checks {
+		check := checkResponse{Status: "ok"}
+		if c.Err != nil {
+			check = checkResponse{Status: "error", Error: c.Err.Error()}
+		}
+		resp.Checks[c.Name] = check
+	}
+
+	status := http.StatusOK
+	if !report.Ready {
+		resp.Status = "not_ready"
+		status = http.StatusServiceUnavailable
+	}
+	writeJSON(h.logger, w, status, resp)
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+)
+
+const (
+	// defaultJobListLimit は、limit が指定されなかった場合に返すジョブの件数です。
+	defaultJobListLimit = 50
+	// maxJobListLimit は、1回のリクエストで返すジョブの最大件数です。
+	maxJobListLimit = 500
+)
+
+// listTaskJobs は、タスクのジョブをスケジュール時刻の新しい順に返します。
+// 返す件数はクエリパラメーター limit で指定できます。
+func (s *Server) listTaskJobs(w http.ResponseWriter, r *http.Request) {
+	limit, err := parseLimit(r.URL.Query().Get("limit"))
+	if err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+
+	task, ok := s.findTask(w, r)
+	if !ok {
+		return
+	}
+
+	jobs, err := s.jobRepo.FindByTaskID(r.Context(), task.ID, limit)
+	if err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+
+	resp := make([]*jobResponse, 0, len(jobs))
+	for _, job := range jobs {
+		resp = append(resp, newJobResponse(job))
+	}
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+// getJob は、ジョブを各試行の実行結果とともに1件返します。
+func (s *Server) getJob(w http.ResponseWriter, r *http.Request) {
+	job, err := s.jobRepo.FindByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+	if job == nil {
+		s.writeNotFound(w, "job not found")
+		return
+	}
+
+	results, err := s.jobRepo.FindResultsByJobID(r.Context(), job.ID)
+	if err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+
+	resp := newJobResponse(job)
+	for _, result := range results {
+		resp.Results = append(resp.Results, newJobResultResponse(result))
+	}
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+// parseLimit は、クエリパラメーター limit を解析します。空の場合は defaultJobListLimit を返します。
+func parseLimit(value string) (int, error) {
+	if value == "" {
+		return defaultJobListLimit, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > maxJobListLimit {
+		return 0, fmt.Errorf("%w: limit must be an integer between 1 and %d", domain.ErrInvalidArgument, maxJobListLimit)
+	}
+	return limit, nil
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+	"github.com/yourname/go-dist-scheduler/internal/usecase"
+)
+
+// maxRequestBodySize は、リクエストボディの最大バイト数です。
+const maxRequestBodySize = 1 << 20
+
+// Server は、タスクとジョブを管理するREST APIのHTTPハンドラーです。
+type Server struct {
+	taskRepo domain.TaskRepository
+	jobRepo  domain.JobRepository
+	trigger  *usecase.Trigger
+	pauser   *usecase.Pauser
+	mux      *http.ServeMux
+	logger   *slog.Logger
+}
+
+// ServerOption は、Server の設定を変更するオプションです。
+type ServerOption func(*Server)
+
+// WithServerLogger は、Server がログを出力するロガーを設定します。
+func WithServerLogger(logger *slog.Logger) ServerOption {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+// NewServer は新しいServerインスタンスを生成し、エンドポイントを登録します。
+// ログはデフォルトで slog.Default() に出力します。
+func NewServer(taskRepo domain.TaskRepository, jobRepo domain.JobRepository, opts ...ServerOption) *Server {
+	s := &Server{
+		taskRepo: taskRepo,
+		jobRepo:  jobRepo,
+		trigger:  usecase.NewTrigger(taskRepo, jobRepo),
+		pauser:   usecase.NewPauser(taskRepo),
+		mux:      http.NewServeMux(),
+		logger:   slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.mux.HandleFunc("POST /tasks", s.createTask)
+	s.mux.HandleFunc("GET /tasks", s.listTasks)
+	s.mux.HandleFunc("GET /tasks/{id}", s.getTask)
+	s.mux.HandleFunc("PUT /tasks/{id}", s.updateTask)
+	s.mux.HandleFunc("DELETE /tasks/{id}", s.deleteTask)
+	s.mux.HandleFunc("POST /tasks/{id}/pause", s.pauseTask)
+	s.mux.HandleFunc("POST /tasks/{id}/resume", s.resumeTask)
+	s.mux.HandleFunc("POST /tasks/{id}/run", s.runTask)
+	s.mux.HandleFunc("GET /tasks/{id}/jobs", s.listTaskJobs)
+	s.mux.HandleFunc("GET /jobs/{id}", s.getJob)
+
+	return s
+}
+
+// ServeHTTP は、リクエストを登録済みのエンドポイントへ振り分けます。
+// 該当するエンドポイントがない場合（404や405）も、エラーをJSONで返します。
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h, pattern := s.mux.Handler(r)
+	if pattern != "" {
+		// パスパラメーターを設定させるため、ServeMux 経由で呼び出す
+		s.mux.ServeHTTP(w, r)
+		return
+	}
+
+	// ServeMux が返すステータスコードと Allow ヘッダーはそのままに、本文だけをJSONに置き換える
+	rec := &statusRecorder{header: w.Header(), status: http.StatusNotFound}
+	h.ServeHTTP(rec, r)
+	s.writeError(w, rec.status, errorCode(rec.status), http.StatusText(rec.status))
+}
+
+// statusRecorder は、ステータスコードのみを記録し、本文を破棄する http.ResponseWriter です。
+type statusRecorder struct {
+	header http.Header
+	status int
+}
+
+func (r *statusRecorder) Header() http.Header         { return r.header }
+func (r *statusRecorder) Write(b []byte) (int, error) { return len(b), nil }
+func (r *statusRecorder) WriteHeader(status int)      { r.status = status }
+
+// errorResponse は、エラー時のレスポンスボディです。
+type errorResponse struct {
+	Error errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// errorCode は、HTTPステータスコードに対応するエラーコードを返します。
+func errorCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "invalid_argument"
+	case http.StatusNotFound:
+		return "not_found"
+	case http.StatusMethodNotAllowed:
+		return "method_not_allowed"
+	case http.StatusConflict:
+		return "conflict"
+	default:
+		return "internal"
+	}
+}
+
+// writeJSON は、v をJSONとしてレスポンスに書き込みます。書き込みに失敗した場合は logger に記録します。
+func writeJSON(logger *slog.Logger, w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logger.Error("failed to write response", slog.Any("error", err))
+	}
+}
+
+// writeJSON は、v をJSONとしてレスポンスに書き込みます。
+func (s *Server) writeJSON(w http.ResponseWriter, status int, v any) {
+	writeJSON(s.logger, w, status, v)
+}
+
+// writeError は、エラーをJSONとしてレスポンスに書き込みます。
+func (s *Server) writeError(w http.ResponseWriter, status int, code, message string) {
+	s.writeJSON(w, status, errorResponse{Error: errorBody{Code: code, Message: message}})
+}
+
+// writeDomainError は、リポジトリやドメインのエラーを対応するHTTPステータスコードで書き込みます。
+// 内部エラーの詳細はログにのみ記録し、クライアントには返しません。
+func (s *Server) writeDomainError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidArgument):
+		s.writeError(w, http.StatusBadRequest, errorCode(http.StatusBadRequest), err.Error())
+	case errors.Is(err, domain.ErrNotFound):
+		s.writeNotFound(w, err.Error())
+	case errors.Is(err, domain.ErrConflict), errors.Is(err, domain.ErrConstraintViolation):
+		s.writeError(w, http.StatusConflict, errorCode(http.StatusConflict), err.Error())
+	default:
+		s.logger.Error("internal error", slog.Any("error", err))
+		s.writeError(w, http.StatusInternalServerError, errorCode(http.StatusInternalServerError), "internal server error")
+	}
+}
+
+// writeNotFound は、リソースが存在しないことを表すエラーを書き込みます。
+func (s *Server) writeNotFound(w http.ResponseWriter, message string) {
+	s.writeError(w, http.StatusNotFound, errorCode(http.StatusNotFound), message)
+}
+
+// decodeJSON は、リクエストボディを v にデコードします。
+// 未知のフィールドや不正なJSONは domain.ErrInvalidArgument をラップしたエラーになります。
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("%w: invalid request body: %v", domain.ErrInvalidArgument, err)
+	}
+	return nil
+}
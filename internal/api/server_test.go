@@ -0,0 +1,681 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/memory"
+	"github.com/yourname/go-dist-scheduler/internal/usecase"
+)
+
+const validTaskJSON = `{
+	"name": "Sample Task",
+	"cron_expression": "*/5 * * * *",
+	"payload": {
+		"url": "https://example.com/webhook",
+		"method": "POST",
+		"headers": {"Content-Type": "application/json"},
+		"body": "{\"message\":\"hello\"}"
+	},
+	"retry_policy": {
+		"max_retries": 3,
+		"backoff": "exponential",
+		"initial_interval": "5s",
+		"max_interval": "1m",
+		"jitter": 0.2
+	}
+}`
+
+// testServer は、インメモリリポジトリを使用するAPIサーバーとそのリポジトリです。
+type testServer struct {
+	*httptest.Server
+	taskRepo *memory.InMemoryTaskRepository
+	jobRepo  *memory.InMemoryJobRepository
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := memory.NewInMemoryJobRepository()
+	server := httptest.NewServer(NewServer(taskRepo, jobRepo))
+	t.Cleanup(server.Close)
+
+	return &testServer{Server: server, taskRepo: taskRepo, jobRepo: jobRepo}
+}
+
+// do は、リクエストを送信し、レスポンスのステータスコードとボディを返します。
+func (s *testServer) do(t *testing.T, method, path, body string) (*http.Response, []byte) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, s.URL+path, bytes.NewBufferString(body))
+	require.NoError(t, err)
+	resp, err := s.Client().Do(req)
+	require.NoError(t, err)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(resp.Body)
+	require.NoError(t, err)
+	return resp, buf.Bytes()
+}
+
+// createTask は、validTaskJSON のタスクを作成して返します。
+func (s *testServer) createTask(t *testing.T) *taskResponse {
+	t.Helper()
+
+	resp, body := s.do(t, http.MethodPost, "/tasks", validTaskJSON)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+	var task taskResponse
+	require.NoError(t, json.Unmarshal(body, &task))
+	return &task
+}
+
+// assertError は、レスポンスが指定したステータスコードとエラーコードのJSONエラーであることを検証します。
+func assertError(t *testing.T, resp *http.Response, body []byte, status int, code string) {
+	t.Helper()
+
+	assert.Equal(t, status, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	var errResp errorResponse
+	require.NoError(t, json.Unmarshal(body, &errResp), string(body))
+	assert.Equal(t, code, errResp.Error.Code)
+	assert.NotEmpty(t, errResp.Error.Message)
+}
+
+func TestServer_CreateTask(t *testing.T) {
+	server := newTestServer(t)
+
+	resp, body := server.do(t, http.MethodPost, "/tasks", validTaskJSON)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+	var created taskResponse
+	require.NoError(t, json.Unmarshal(body, &created))
+	assert.NotEmpty(t, created.ID)
+	assert.Equal(t, "/tasks/"+created.ID, resp.Header.Get("Location"))
+	assert.Equal(t, "Sample Task", created.Name)
+	assert.Equal(t, "active", created.Status)
+	assert.Equal(t, "exponential", created.RetryPolicy.Backoff)
+	assert.Equal(t, "5s", created.RetryPolicy.InitialInterval)
+	assert.Equal(t, "1m0s", created.RetryPolicy.MaxInterval)
+
+	// The task is stored in the repository
+	task, err := server.taskRepo.FindByID(context.Background(), created.ID)
+	require.NoError(t, err)
+	require.NotNil(t, task)
+	assert.Equal(t, "*/5 * * * *", task.CronExpression)
+	assert.Equal(t, []byte(`{"message":"hello"}`), task.Payload.Body)
+	assert.Equal(t, domain.RetryPolicy{
+		MaxRetries:      3,
+		Backoff:         domain.BackoffExponential,
+		InitialInterval: 5 * time.Second,
+		MaxInterval:     time.Minute,
+		Jitter:          0.2,
+	}, task.RetryPolicy)
+}
+
+func TestServer_CreateTask_InvalidInput(t *testing.T) {
+	testCases := []struct {
+		name string
+		body string
+	}{
+		{name: "malformed json", body: `{"name":`},
+		{name: "unknown field", body: `{"name":"task","unknown":true}`},
+		{name: "missing name", body: `{"cron_expression":"* * * * *","payload":{"url":"https://example.com"}}`},
+		{name: "invalid cron expression", body: `{"name":"task","cron_expression":"every minute","payload":{"url":"https://example.com"}}`},
+		{name: "unknown timezone", body: `{"name":"task","cron_expression":"* * * * *","timezone":"Mars/Olympus_Mons","payload":{"url":"https://example.com"}}`},
+		{name: "invalid url", body: `{"name":"task","cron_expression":"* * * * *","payload":{"url":"example.com"}}`},
+		{name: "unknown backoff", body: `{"name":"task","cron_expression":"* * * * *","payload":{"url":"https://example.com"},"retry_policy":{"backoff":"random"}}`},
+		{name: "unknown misfire strategy", body: `{"name":"task","cron_expression":"* * * * *","payload":{"url":"https://example.com"},"misfire_policy":{"strategy":"later"}}`},
+		{name: "limited misfire strategy without limits", body: `{"name":"task","cron_expression":"* * * * *","payload":{"url":"https://example.com"},"misfire_policy":{"strategy":"fire_limited"}}`},
+		{name: "unknown concurrency policy", body: `{"name":"task","cron_expression":"* * * * *","payload":{"url":"https://example.com"},"concurrency_policy":"queue"}`},
+		{name: "invalid timeout", body: `{"name":"task","cron_expression":"* * * * *","payload":{"url":"https://example.com"},"timeout":"-1s"}`},
+		{name: "invalid interval", body: `{"name":"task","cron_expression":"* * * * *","payload":{"url":"https://example.com"},"retry_policy":{"initial_interval":"5"}}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newTestServer(t)
+
+			resp, body := server.do(t, http.MethodPost, "/tasks", tc.body)
+			assertError(t, resp, body, http.StatusBadRequest, "invalid_argument")
+
+			tasks, err := server.taskRepo.FindAll(context.Background())
+			require.NoError(t, err)
+			assert.Empty(t, tasks)
+		})
+	}
+}
+
+func TestServer_CreateTask_MisfirePolicy(t *testing.T) {
+	server := newTestServer(t)
+
+	resp, body := server.do(t, http.MethodPost, "/tasks", `{"name":"task","cron_expression":"* * * * *","payload":{"url":"https://example.com"},"misfire_policy":{"strategy":"fire_limited","max_runs":10,"max_age":"1h"}}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+	var created taskResponse
+	require.NoError(t, json.Unmarshal(body, &created))
+	assert.Equal(t, misfirePolicyJSON{Strategy: "fire_limited", MaxRuns: 10, MaxAge: "1h0m0s"}, created.MisfirePolicy)
+
+	task, err := server.taskRepo.FindByID(context.Background(), created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.MisfirePolicy{Strategy: domain.MisfireFireLimited, MaxRuns: 10, MaxAge: time.Hour}, task.MisfirePolicy)
+
+	// The default policy fires all missed runs
+	created = *server.createTask(t)
+	assert.Equal(t, "fire_all", created.MisfirePolicy.Strategy)
+}
+
+func TestServer_CreateTask_ConcurrencyPolicy(t *testing.T) {
+	server := newTestServer(t)
+
+	resp, body := server.do(t, http.MethodPost, "/tasks", `{"name":"task","cron_expression":"* * * * *","payload":{"url":"https://example.com"},"concurrency_policy":"forbid"}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+	var created taskResponse
+	require.NoError(t, json.Unmarshal(body, &created))
+	assert.Equal(t, "forbid", created.ConcurrencyPolicy)
+	assert.Empty(t, created.Timeout)
+
+	task, err := server.taskRepo.FindByID(context.Background(), created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ConcurrencyForbid, task.ConcurrencyPolicy)
+
+	// The default policy allows overlapping runs
+	created = *server.createTask(t)
+	assert.Equal(t, "allow", created.ConcurrencyPolicy)
+}
+
+func TestServer_CreateTask_Timeout(t *testing.T) {
+	server := newTestServer(t)
+
+	resp, body := server.do(t, http.MethodPost, "/tasks", `{"name":"task","cron_expression":"* * * * *","payload":{"url":"https://example.com"},"timeout":"30s"}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+	var created taskResponse
+	require.NoError(t, json.Unmarshal(body, &created))
+	assert.Equal(t, "30s", created.Timeout)
+
+	task, err := server.taskRepo.FindByID(context.Background(), created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Second, task.Timeout)
+}
+
+func TestServer_CreateTask_RetentionPolicy(t *testing.T) {
+	server := newTestServer(t)
+
+	resp, body := server.do(t, http.MethodPost, "/tasks", `{"name":"task","cron_expression":"* * * * *","payload":{"url":"https://example.com"},"retention_policy":{"completed":{"max_age":"24h"},"failed":{"max_age":"168h","max_jobs":100}}}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+	var created taskResponse
+	require.NoError(t, json.Unmarshal(body, &created))
+	assert.Equal(t, &retentionPolicyJSON{
+		Completed: retentionRuleJSON{MaxAge: "24h0m0s"},
+		Failed:    retentionRuleJSON{MaxAge: "168h0m0s", MaxJobs: 100},
+	}, created.RetentionPolicy)
+
+	task, err := server.taskRepo.FindByID(context.Background(), created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.RetentionPolicy{
+		Completed: domain.RetentionRule{MaxAge: 24 * time.Hour},
+		Failed:    domain.RetentionRule{MaxAge: 168 * time.Hour, MaxJobs: 100},
+	}, task.RetentionPolicy)
+
+	// Without a policy, the task follows the global retention settings
+	created = *server.createTask(t)
+	assert.Nil(t, created.RetentionPolicy)
+
+	resp, body = server.do(t, http.MethodPost, "/tasks", `{"name":"task","cron_expression":"* * * * *","payload":{"url":"https://example.com"},"retention_policy":{"failed":{"max_jobs":-1}}}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, string(body))
+}
+
+func TestServer_CreateTask_Signing(t *testing.T) {
+	server := newTestServer(t)
+
+	resp, body := server.do(t, http.MethodPost, "/tasks", `{"name":"task","cron_expression":"* * * * *","payload":{"url":"https://example.com","signing":{"secrets":["old-secret","new-secret"],"signature_header":"X-Signature"}}}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+	// The secrets are never returned
+	assert.NotContains(t, string(body), "old-secret")
+	assert.NotContains(t, string(body), "new-secret")
+	var created taskResponse
+	require.NoError(t, json.Unmarshal(body, &created))
+	assert.Equal(t, &signingJSON{
+		SecretCount:     2,
+		SignatureHeader: "X-Signature",
+		TimestampHeader: "X-Scheduler-Timestamp",
+	}, created.Payload.Signing)
+
+	task, err := server.taskRepo.FindByID(context.Background(), created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.SigningPolicy{
+		Secrets:         []string{"old-secret", "new-secret"},
+		SignatureHeader: "X-Signature",
+	}, task.Payload.Signing)
+
+	// Without signing settings, requests are sent unsigned
+	created = *server.createTask(t)
+	assert.Nil(t, created.Payload.Signing)
+
+	resp, body = server.do(t, http.MethodPost, "/tasks", `{"name":"task","cron_expression":"* * * * *","payload":{"url":"https://example.com","signing":{"secrets":[""]}}}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode, string(body))
+}
+
+func TestServer_CreateTask_Timezone(t *testing.T) {
+	server := newTestServer(t)
+
+	resp, body := server.do(t, http.MethodPost, "/tasks", `{"name":"task","cron_expression":"0 9 * * *","timezone":"Asia/Tokyo","payload":{"url":"https://example.com"}}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+
+	var created taskResponse
+	require.NoError(t, json.Unmarshal(body, &created))
+	assert.Equal(t, "Asia/Tokyo", created.Timezone)
+
+	task, err := server.taskRepo.FindByID(context.Background(), created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Tokyo", task.Timezone)
+}
+
+func TestServer_GetAndListTasks(t *testing.T) {
+	server := newTestServer(t)
+	created := server.createTask(t)
+
+	resp, body := server.do(t, http.MethodGet, "/tasks/"+created.ID, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var task taskResponse
+	require.NoError(t, json.Unmarshal(body, &task))
+	assert.Equal(t, created.ID, task.ID)
+
+	resp, body = server.do(t, http.MethodGet, "/tasks", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var tasks []taskResponse
+	require.NoError(t, json.Unmarshal(body, &tasks))
+	require.Len(t, tasks, 1)
+	assert.Equal(t, created.ID, tasks[0].ID)
+
+	resp, body = server.do(t, http.MethodGet, "/tasks/nonexistent", "")
+	assertError(t, resp, body, http.StatusNotFound, "not_found")
+}
+
+func TestServer_UpdateTask(t *testing.T) {
+	server := newTestServer(t)
+	created := server.createTask(t)
+
+	update := `{"name":"Renamed","cron_expression":"0 * * * *","payload":{"url":"https://example.com/other"}}`
+	resp, body := server.do(t, http.MethodPut, "/tasks/"+created.ID, update)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+	task, err := server.taskRepo.FindByID(context.Background(), created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", task.Name)
+	assert.Equal(t, "0 * * * *", task.CronExpression)
+	assert.Equal(t, "https://example.com/other", task.Payload.URL)
+	assert.Equal(t, domain.RetryPolicy{}, task.RetryPolicy, "omitted retry policy disables retries")
+	assert.Equal(t, domain.TaskStatusActive, task.Status)
+	assert.True(t, created.CreatedAt.Equal(task.CreatedAt))
+
+	// Invalid input does not modify the task
+	resp, body = server.do(t, http.MethodPut, "/tasks/"+created.ID, `{"name":"Renamed","cron_expression":"61 * * * *","payload":{"url":"https://example.com"}}`)
+	assertError(t, resp, body, http.StatusBadRequest, "invalid_argument")
+	task, err = server.taskRepo.FindByID(context.Background(), created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "0 * * * *", task.CronExpression)
+
+	resp, body = server.do(t, http.MethodPut, "/tasks/nonexistent", update)
+	assertError(t, resp, body, http.StatusNotFound, "not_found")
+}
+
+func TestServer_UpdateTask_KeepsSigningSecrets(t *testing.T) {
+	server := newTestServer(t)
+	ctx := context.Background()
+
+	resp, body := server.do(t, http.MethodPost, "/tasks", `{"name":"task","cron_expression":"* * * * *","payload":{"url":"https://example.com","signing":{"secrets":["old-secret","new-secret"],"signature_header":"X-Signature"}}}`)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+	var created taskResponse
+	require.NoError(t, json.Unmarshal(body, &created))
+	path := "/tasks/" + created.ID
+
+	// GET, edit and PUT the task back; the response carries no secrets, so the stored ones are kept
+	resp, body = server.do(t, http.MethodGet, path, "")
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+	var fetched taskResponse
+	require.NoError(t, json.Unmarshal(body, &fetched))
+	req := taskRequest{Name: "Renamed", CronExpression: fetched.CronExpression, Payload: fetched.Payload}
+	req.Payload.Signing.SecretCount = 0
+	update, err := json.Marshal(req)
+	require.NoError(t, err)
+	resp, body = server.do(t, http.MethodPut, path, string(update))
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+
+	task, err := server.taskRepo.FindByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", task.Name)
+	assert.Equal(t, domain.SigningPolicy{
+		Secrets:         []string{"old-secret", "new-secret"},
+		SignatureHeader: "X-Signature",
+		TimestampHeader: "X-Scheduler-Timestamp",
+	}, task.Payload.Signing)
+
+	// Omitting signing also keeps it
+	resp, body = server.do(t, http.MethodPut, path, `{"name":"task","cron_expression":"* * * * *","payload":{"url":"https://example.com"}}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+	var updated taskResponse
+	require.NoError(t, json.Unmarshal(body, &updated))
+	require.NotNil(t, updated.Payload.Signing)
+	assert.Equal(t, 2, updated.Payload.Signing.SecretCount)
+
+	// secret_count is read-only
+	resp, body = server.do(t, http.MethodPut, path, `{"name":"task","cron_expression":"* * * * *","payload":{"url":"https://example.com","signing":{"secret_count":1}}}`)
+	assertError(t, resp, body, http.StatusBadRequest, "invalid_argument")
+
+	// New secrets replace the stored ones
+	resp, body = server.do(t, http.MethodPut, path, `{"name":"task","cron_expression":"* * * * *","payload":{"url":"https://example.com","signing":{"secrets":["rotated"]}}}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+	task, err = server.taskRepo.FindByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.SigningPolicy{Secrets: []string{"rotated"}}, task.Payload.Signing)
+
+	// Signing is turned off only explicitly
+	resp, body = server.do(t, http.MethodPut, path, `{"name":"task","cron_expression":"* * * * *","payload":{"url":"https://example.com","signing":{"disabled":true}}}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+	var disabled taskResponse
+	require.NoError(t, json.Unmarshal(body, &disabled))
+	assert.Nil(t, disabled.Payload.Signing)
+}
+
+func TestServer_PauseAndResumeTask(t *testing.T) {
+	server := newTestServer(t)
+	created := server.createTask(t)
+
+	resp, body := server.do(t, http.MethodPost, "/tasks/"+created.ID+"/pause", `{"actor": "alice", "reason": "incident"}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+	var task taskResponse
+	require.NoError(t, json.Unmarshal(body, &task))
+	assert.Equal(t, "paused", task.Status)
+
+	active, err := server.taskRepo.FindAllActive(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, active, "paused task must not be scheduled")
+
+	resp, body = server.do(t, http.MethodPost, "/tasks/"+created.ID+"/resume", `{"actor": "bob", "mode": "from_now"}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+	require.NoError(t, json.Unmarshal(body, &task))
+	assert.Equal(t, "active", task.Status)
+	assert.NotNil(t, task.LastCheckedAt, "resuming from now must skip the runs missed while paused")
+	if assert.Len(t, task.StatusHistory, 2) {
+		assert.Equal(t, statusChangeJSON{Status: "paused", Actor: "alice", Reason: "incident", ChangedAt: task.StatusHistory[0].ChangedAt}, task.StatusHistory[0])
+		assert.Equal(t, "active", task.StatusHistory[1].Status)
+		assert.Equal(t, "bob", task.StatusHistory[1].Actor)
+	}
+
+	resp, body = server.do(t, http.MethodPost, "/tasks/nonexistent/pause", `{"actor": "alice"}`)
+	assertError(t, resp, body, http.StatusNotFound, "not_found")
+}
+
+func TestServer_PauseAndResumeTask_InvalidInput(t *testing.T) {
+	server := newTestServer(t)
+	created := server.createTask(t)
+
+	testCases := []struct {
+		name string
+		path string
+		body string
+	}{
+		{name: "pause without body", path: "/pause", body: ""},
+		{name: "pause without actor", path: "/pause", body: `{"reason": "incident"}`},
+		{name: "resume without mode", path: "/resume", body: `{"actor": "alice"}`},
+		{name: "resume with unknown mode", path: "/resume", body: `{"actor": "alice", "mode": "later"}`},
+		{name: "resume without actor", path: "/resume", body: `{"mode": "backfill"}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, body := server.do(t, http.MethodPost, "/tasks/"+created.ID+tc.path, tc.body)
+			assertError(t, resp, body, http.StatusBadRequest, "invalid_argument")
+		})
+	}
+}
+
+func TestServer_RunTask(t *testing.T) {
+	ctx := context.Background()
+	server := newTestServer(t)
+	created := server.createTask(t)
+
+	resp, body := server.do(t, http.MethodPost, "/tasks/"+created.ID+"/run", "")
+	require.Equal(t, http.StatusCreated, resp.StatusCode, string(body))
+	var job jobResponse
+	require.NoError(t, json.Unmarshal(body, &job))
+	assert.Equal(t, "/jobs/"+job.ID, resp.Header.Get("Location"))
+	assert.Equal(t, created.ID, job.TaskID)
+	assert.Equal(t, "pending", job.Status)
+	assert.Equal(t, "manual", job.TriggerSource)
+	assert.WithinDuration(t, time.Now(), job.ScheduledAt, 5*time.Second)
+
+	dequeued, err := server.jobRepo.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, dequeued)
+	assert.Equal(t, job.ID, dequeued.ID)
+
+	task, err := server.taskRepo.FindByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.True(t, task.LastCheckedAt.IsZero(), "a manual run must not change the regular schedule")
+
+	resp, body = server.do(t, http.MethodPost, "/tasks/nonexistent/run", "")
+	assertError(t, resp, body, http.StatusNotFound, "not_found")
+}
+
+func TestServer_DeleteTask(t *testing.T) {
+	ctx := context.Background()
+	server := newTestServer(t)
+	created := server.createTask(t)
+
+	// One job is running and one is waiting in the queue
+	now := time.Now()
+	for _, scheduledAt := range []time.Time{now.Add(-time.Minute), now} {
+		require.NoError(t, server.jobRepo.Enqueue(ctx, &domain.Job{
+			ID:          domain.NewJobID(created.ID, scheduledAt),
+			TaskID:      created.ID,
+			ScheduledAt: scheduledAt,
+			Status:      domain.JobStatusPending,
+		}))
+	}
+	running, err := server.jobRepo.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, running)
+
+	resp, body := server.do(t, http.MethodDelete, "/tasks/"+created.ID, "")
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Empty(t, body)
+
+	// The jobs of the deleted task are cancelled, so workers no longer run them
+	active, err := server.jobRepo.FindActiveByTaskID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Empty(t, active)
+	jobs, err := server.jobRepo.FindByTaskID(ctx, created.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	for _, job := range jobs {
+		assert.Equal(t, domain.JobStatusCancelled, job.Status)
+	}
+	dequeued, err := server.jobRepo.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, dequeued)
+
+	resp, body = server.do(t, http.MethodGet, "/tasks/"+created.ID, "")
+	assertError(t, resp, body, http.StatusNotFound, "not_found")
+
+	resp, body = server.do(t, http.MethodDelete, "/tasks/"+created.ID, "")
+	assertError(t, resp, body, http.StatusNotFound, "not_found")
+}
+
+func TestServer_ListTaskJobs(t *testing.T) {
+	ctx := context.Background()
+	server := newTestServer(t)
+	created := server.createTask(t)
+
+	now := time.Now().UTC()
+	for i := 0; i < 3; i++ {
+		scheduledAt := now.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, server.jobRepo.Enqueue(ctx, &domain.Job{
+			ID:          domain.NewJobID(created.ID, scheduledAt),
+			TaskID:      created.ID,
+			ScheduledAt: scheduledAt,
+			Status:      domain.JobStatusPending,
+		}))
+	}
+
+	resp, body := server.do(t, http.MethodGet, "/tasks/"+created.ID+"/jobs?limit=2", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+	var jobs []jobResponse
+	require.NoError(t, json.Unmarshal(body, &jobs))
+	require.Len(t, jobs, 2)
+	assert.True(t, jobs[0].ScheduledAt.Equal(now.Add(2*time.Minute)), "most recently scheduled first")
+	assert.Equal(t, "pending", jobs[0].Status)
+
+	resp, body = server.do(t, http.MethodGet, "/tasks/"+created.ID+"/jobs", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.NoError(t, json.Unmarshal(body, &jobs))
+	assert.Len(t, jobs, 3)
+
+	resp, body = server.do(t, http.MethodGet, "/tasks/"+created.ID+"/jobs?limit=0", "")
+	assertError(t, resp, body, http.StatusBadRequest, "invalid_argument")
+
+	resp, body = server.do(t, http.MethodGet, "/tasks/nonexistent/jobs", "")
+	assertError(t, resp, body, http.StatusNotFound, "not_found")
+}
+
+func TestServer_GetJob(t *testing.T) {
+	ctx := context.Background()
+	server := newTestServer(t)
+
+	job := &domain.Job{ID: "job1", TaskID: "task1", ScheduledAt: time.Now(), Status: domain.JobStatusPending}
+	require.NoError(t, server.jobRepo.Enqueue(ctx, job))
+	require.NoError(t, server.jobRepo.UpdateStatus(ctx, job.ID, domain.JobStatusFailed))
+	require.NoError(t, server.jobRepo.SaveResult(ctx, &domain.JobResult{
+		JobID:        job.ID,
+		TaskID:       job.TaskID,
+		Attempt:      1,
+		StatusCode:   http.StatusInternalServerError,
+		ResponseBody: []byte("boom"),
+		Error:        "unexpected status code: 500",
+		Duration:     1500 * time.Millisecond,
+	}))
+
+	resp, body := server.do(t, http.MethodGet, "/jobs/job1", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode, string(body))
+	var got jobResponse
+	require.NoError(t, json.Unmarshal(body, &got))
+	assert.Equal(t, "job1", got.ID)
+	assert.Equal(t, "failed", got.Status)
+	assert.NotNil(t, got.FinishedAt)
+	require.Len(t, got.Results, 1)
+	assert.Equal(t, http.StatusInternalServerError, got.Results[0].StatusCode)
+	assert.Equal(t, "boom", got.Results[0].ResponseBody)
+	assert.Equal(t, int64(1500), got.Results[0].DurationMS)
+
+	resp, body = server.do(t, http.MethodGet, "/jobs/nonexistent", "")
+	assertError(t, resp, body, http.StatusNotFound, "not_found")
+}
+
+func TestServer_UnknownRoute(t *testing.T) {
+	server := newTestServer(t)
+
+	resp, body := server.do(t, http.MethodGet, "/unknown", "")
+	assertError(t, resp, body, http.StatusNotFound, "not_found")
+
+	resp, body = server.do(t, http.MethodPatch, "/tasks", "")
+	assertError(t, resp, body, http.StatusMethodNotAllowed, "method_not_allowed")
+	assert.Contains(t, resp.Header.Get("Allow"), http.MethodPost)
+}
+
+// failingTaskRepository は、FindAll が常に失敗する TaskRepository です。
+type failingTaskRepository struct {
+	domain.TaskRepository
+}
+
+func (r *failingTaskRepository) FindAll(ctx context.Context) ([]*domain.Task, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestServer_InternalError(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	taskRepo := &failingTaskRepository{TaskRepository: memory.NewInMemoryTaskRepository()}
+	server := httptest.NewServer(NewServer(taskRepo, memory.NewInMemoryJobRepository(), WithServerLogger(logger)))
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL + "/tasks")
+	require.NoError(t, err)
+	var errResp errorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&errResp))
+	require.NoError(t, resp.Body.Close())
+
+	// The details are logged but not returned to the client
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, "internal server error", errResp.Error.Message)
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "internal error", line["msg"])
+	assert.Contains(t, line["error"], "connection refused")
+}
+
+func TestHealthHandler(t *testing.T) {
+	var redisErr error
+	monitor := usecase.NewLoopMonitor()
+	health := usecase.NewHealth(
+		usecase.WithHealthCheck("postgres", func(ctx context.Context) error { return nil }),
+		usecase.WithHealthCheck("redis", func(ctx context.Context) error { return redisErr }),
+		usecase.WithLoopMonitor(monitor, time.Minute),
+	)
+	handler := NewHealthHandler(health)
+
+	get := func(h http.HandlerFunc) (*httptest.ResponseRecorder, map[string]any) {
+		rec := httptest.NewRecorder()
+		h(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		return rec, body
+	}
+
+	rec, body := get(handler.Healthz)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "ok", body["status"])
+
+	// The scheduling loop has not completed a tick yet
+	rec, body = get(handler.Readyz)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "not_ready", body["status"])
+	assert.Equal(t, "error", body["checks"].(map[string]any)["scheduler_loop"].(map[string]any)["status"])
+
+	monitor.Tick(time.Now())
+	rec, body = get(handler.Readyz)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "ready", body["status"])
+	assert.NotEmpty(t, body["last_tick_at"])
+	assert.Equal(t, false, body["leader"])
+	assert.Equal(t, map[string]any{"busy": float64(0), "total": float64(0)}, body["workers"])
+	assert.Equal(t, map[string]any{"status": "ok"}, body["checks"].(map[string]any)["postgres"])
+
+	redisErr = errors.New("dial tcp: connection refused")
+	rec, body = get(handler.Readyz)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, map[string]any{"status": "error", "error": "dial tcp: connection refused"}, body["checks"].(map[string]any)["redis"])
+
+	// Liveness does not depend on the components
+	rec, _ = get(handler.Healthz)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+	"github.com/yourname/go-dist-scheduler/internal/usecase"
+)
+
+// createTask は、新しいタスクを作成します。作成されたタスクは有効な状態で開始されます。
+func (s *Server) createTask(w http.ResponseWriter, r *http.Request) {
+	var req taskRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+
+	now := time.Now()
+	task := &domain.Task{
+		ID:        uuid.NewString(),
+		Status:    domain.TaskStatusActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := req.apply(task); err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+	if err := task.Validate(); err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+
+	if err := s.taskRepo.Save(r.Context(), task); err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/tasks/"+task.ID)
+	s.writeJSON(w, http.StatusCreated, newTaskResponse(task))
+}
+
+// listTasks は、すべてのタスクを返します。
+func (s *Server) listTasks(w http.ResponseWriter, r *http.Request) {
+	tasks, err := s.taskRepo.FindAll(r.Context())
+	if err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+
+	resp := make([]*taskResponse, 0, len(tasks))
+	for _, task := range tasks {
+		resp = append(resp, newTaskResponse(task))
+	}
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+// getTask は、タスクを1件返します。
+func (s *Server) getTask(w http.ResponseWriter, r *http.Request) {
+	task, ok := s.findTask(w, r)
+	if !ok {
+		return
+	}
+	s.writeJSON(w, http.StatusOK, newTaskResponse(task))
+}
+
+// updateTask は、タスクの内容を置き換えます。ステータスは pause・resume でのみ変更できます。
+// 同時に保存された一時停止などの更新とは、タスクを読み直して変更をやり直すことで競合を解決します。
+func (s *Server) updateTask(w http.ResponseWriter, r *http.Request) {
+	var req taskRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+
+	task, err := usecase.ModifyTask(r.Context(), s.taskRepo, r.PathValue("id"), func(task *domain.Task) (bool, error) {
+		if err := req.apply(task); err != nil {
+			return false, err
+		}
+		if err := task.Validate(); err != nil {
+			return false, err
+		}
+		task.UpdatedAt = time.Now()
+		return true, nil
+	})
+	if err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, newTaskResponse(task))
+}
+
+// deleteTask は、タスクを削除し、未終了（Pending または Running）のジョブをキャンセルします。
+// 削除したタスクのジョブが後からデキューされて失敗しないようにするためです。
+// 実行済みのジョブとその実行結果は削除しません。
+func (s *Server) deleteTask(w http.ResponseWriter, r *http.Request) {
+	task, ok := s.findTask(w, r)
+	if !ok {
+		return
+	}
+
+	// 削除の後にキャンセルすることで、削除までにスケジューラーがエンキューしたジョブもキャンセルする
+	if err := s.taskRepo.Delete(r.Context(), task.ID); err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+	active, err := s.jobRepo.FindActiveByTaskID(r.Context(), task.ID)
+	if err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+	for _, job := range active {
+		if err := s.jobRepo.Cancel(r.Context(), job.ID); err != nil {
+			s.writeDomainError(w, err)
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// pauseTask は、タスクを一時停止し、誰がなぜ一時停止したかを履歴に記録します。既に一時停止中の場合は何もしません。
+func (s *Server) pauseTask(w http.ResponseWriter, r *http.Request) {
+	var req pauseRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+
+	task, err := s.pauser.PauseTask(r.Context(), r.PathValue("id"), req.Actor, req.Reason, time.Now())
+	if err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, newTaskResponse(task))
+}
+
+// resumeTask は、一時停止中のタスクを再開し、誰がなぜ再開したかを履歴に記録します。既に有効な場合は何もしません。
+// 一時停止中の実行時刻の扱いは、リクエストの mode で明示的に指定する必要があります。
+func (s *Server) resumeTask(w http.ResponseWriter, r *http.Request) {
+	var req resumeRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+	mode, ok := lookup(resumeModeNames, req.Mode)
+	if !ok {
+		s.writeDomainError(w, fmt.Errorf("%w: mode must be \"from_now\" or \"backfill\"", domain.ErrInvalidArgument))
+		return
+	}
+
+	task, err := s.pauser.ResumeTask(r.Context(), r.PathValue("id"), mode, req.Actor, req.Reason, time.Now())
+	if err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+	s.writeJSON(w, http.StatusOK, newTaskResponse(task))
+}
+
+// runTask は、タスクのジョブをスケジュール外で即時実行するためにエンキューし、作成したジョブを返します。
+// タスクの通常のスケジュールには影響しません。
+func (s *Server) runTask(w http.ResponseWriter, r *http.Request) {
+	job, err := s.trigger.RunNow(r.Context(), r.PathValue("id"), time.Now())
+	if err != nil {
+		s.writeDomainError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/jobs/"+job.ID)
+	s.writeJSON(w, http.StatusCreated, newJobResponse(job))
+}
+
+// findTask は、パスの {id} で指定されたタスクを取得します。
+// 取得できなかった場合はエラーレスポンスを書き込み、false を返します。
+func (s *Server) findTask(w http.ResponseWriter, r *http.Request) (*domain.Task, bool) {
+	task, err := s.taskRepo.FindByID(r.Context(), r.PathValue("id"))
+	if err != nil {
+		s.writeDomainError(w, err)
+		return nil, false
+	}
+	if task == nil {
+		s.writeNotFound(w, "task not found")
+		return nil, false
+	}
+	return task, true
+}
@@ -1,46 +1,287 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"time"
 
 	"github.com/kelseyhightower/envconfig"
+	"gopkg.in/yaml.v3"
 )
 
+// FileEnv is the environment variable that names an optional YAML configuration file.
+const FileEnv = "CONFIG_FILE"
+
 // Config represents the application configuration.
 type Config struct {
-	Database DatabaseConfig
-	Redis    RedisConfig
+	Storage   StorageConfig   `yaml:"storage"`
+	Database  DatabaseConfig  `yaml:"database"`
+	Redis     RedisConfig     `yaml:"redis"`
+	Log       LogConfig       `yaml:"log"`
+	Scheduler SchedulerConfig `yaml:"scheduler"`
+	Executor  ExecutorConfig  `yaml:"executor"`
+	Retention RetentionConfig `yaml:"retention"`
+	Tracing   TracingConfig   `yaml:"tracing"`
+}
+
+// Storage backends supported by StorageConfig.
+const (
+	BackendMemory   = "memory"
+	BackendPostgres = "postgres"
+	BackendRedis    = "redis"
+)
+
+// StorageConfig selects where tasks and jobs are stored.
+type StorageConfig struct {
+	// Backend is one of "memory", "postgres" or "redis".
+	// "memory" keeps everything in the process and supports a single instance only.
+	// "redis" stores jobs in Redis, and tasks and the leader lock in PostgreSQL.
+	Backend string `envconfig:"BACKEND" yaml:"backend"`
 }
 
 // DatabaseConfig represents database connection configuration.
+// The password is required unless the memory backend is selected.
 type DatabaseConfig struct {
-	Host     string `envconfig:"DB_HOST" default:"localhost"`
-	Port     int    `envconfig:"DB_PORT" default:"5432"`
-	User     string `envconfig:"DB_USER" default:"scheduler"`
-	Password string `envconfig:"DB_PASSWORD" required:"true"`
-	Name     string `envconfig:"DB_NAME" default:"scheduler"`
-	SSLMode  string `envconfig:"DB_SSLMODE" default:"disable"`
+	Host     string `envconfig:"DB_HOST" yaml:"host"`
+	Port     int    `envconfig:"DB_PORT" yaml:"port"`
+	User     string `envconfig:"DB_USER" yaml:"user"`
+	Password string `envconfig:"DB_PASSWORD" yaml:"password"`
+	Name     string `envconfig:"DB_NAME" yaml:"name"`
+	SSLMode  string `envconfig:"DB_SSLMODE" yaml:"sslmode"`
 }
 
 // RedisConfig represents Redis connection configuration.
 type RedisConfig struct {
-	Host string `envconfig:"REDIS_HOST" default:"localhost"`
-	Port int    `envconfig:"REDIS_PORT" default:"6379"`
+	Host string `envconfig:"REDIS_HOST" yaml:"host"`
+	Port int    `envconfig:"REDIS_PORT" yaml:"port"`
+}
+
+// Log output formats supported by LogConfig.
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// LogConfig represents logging configuration.
+type LogConfig struct {
+	// Format is either "text" or "json".
+	Format string `envconfig:"LOG_FORMAT" yaml:"format"`
+	// Level is one of "debug", "info", "warn" or "error".
+	Level string `envconfig:"LOG_LEVEL" yaml:"level"`
+}
+
+// SchedulerConfig represents the configuration of the scheduling loop and leader election.
+type SchedulerConfig struct {
+	// TickInterval is how often the leader checks tasks for due runs and reaps expired leases.
+	TickInterval time.Duration `envconfig:"SCHEDULER_TICK_INTERVAL" yaml:"tick_interval"`
+	// StaleThreshold is how long the scheduling loop may go without completing a tick
+	// before the instance is reported as not ready.
+	StaleThreshold time.Duration `envconfig:"SCHEDULER_STALE_THRESHOLD" yaml:"stale_threshold"`
+	// ElectionInterval is how often leadership is acquired or renewed.
+	ElectionInterval time.Duration `envconfig:"SCHEDULER_ELECTION_INTERVAL" yaml:"election_interval"`
+	// ShutdownGracePeriod is how long running jobs may take to finish on shutdown
+	// before they are aborted and returned to pending.
+	ShutdownGracePeriod time.Duration `envconfig:"SCHEDULER_SHUTDOWN_GRACE_PERIOD" yaml:"shutdown_grace_period"`
+}
+
+// ExecutorConfig represents the configuration of the workers that execute jobs.
+type ExecutorConfig struct {
+	// Workers is the number of jobs executed concurrently.
+	Workers int `envconfig:"EXECUTOR_WORKERS" yaml:"workers"`
+	// PollInterval is how long an idle worker waits before checking the queue again.
+	PollInterval time.Duration `envconfig:"EXECUTOR_POLL_INTERVAL" yaml:"poll_interval"`
+	// RequestTimeout limits each HTTP request sent for a job, independently of the task's own timeout.
+	// Zero means no limit.
+	RequestTimeout time.Duration `envconfig:"EXECUTOR_REQUEST_TIMEOUT" yaml:"request_timeout"`
+	// VisibilityTimeout is how long a dequeued job is leased before it is considered abandoned.
+	VisibilityTimeout time.Duration `envconfig:"EXECUTOR_VISIBILITY_TIMEOUT" yaml:"visibility_timeout"`
+	// HeartbeatInterval is how often the lease of a running job is extended.
+	// It must be shorter than VisibilityTimeout.
+	HeartbeatInterval time.Duration `envconfig:"EXECUTOR_HEARTBEAT_INTERVAL" yaml:"heartbeat_interval"`
+}
+
+// RetentionConfig represents how long finished jobs are kept before they are
+// deleted. The limits apply to tasks without their own retention policy, and
+// zero means no limit, so by default the job history is kept forever.
+type RetentionConfig struct {
+	// Interval is how often the leader deletes the finished jobs beyond their retention.
+	Interval time.Duration `envconfig:"RETENTION_INTERVAL" yaml:"interval"`
+	// BatchSize is the maximum number of jobs deleted at once.
+	BatchSize int `envconfig:"RETENTION_BATCH_SIZE" yaml:"batch_size"`
+	// MaxAge is how long succeeded and cancelled jobs are kept after they finished.
+	MaxAge time.Duration `envconfig:"RETENTION_MAX_AGE" yaml:"max_age"`
+	// MaxJobs is how many succeeded and cancelled jobs are kept per task.
+	MaxJobs int `envconfig:"RETENTION_MAX_JOBS" yaml:"max_jobs"`
+	// FailedMaxAge is how long failed and timed out jobs are kept after they finished.
+	FailedMaxAge time.Duration `envconfig:"RETENTION_FAILED_MAX_AGE" yaml:"failed_max_age"`
+	// FailedMaxJobs is how many failed and timed out jobs are kept per task.
+	FailedMaxJobs int `envconfig:"RETENTION_FAILED_MAX_JOBS" yaml:"failed_max_jobs"`
+}
+
+// TracingConfig represents where the spans of every job are exported with OpenTelemetry.
+type TracingConfig struct {
+	// Endpoint is the URL of the OTLP/HTTP collector, e.g. "http://localhost:4318".
+	// Spans are sent to its /v1/traces path unless the URL has a path, and without
+	// TLS for an http:// URL. Empty disables exporting spans.
+	Endpoint string `envconfig:"TRACING_ENDPOINT" yaml:"endpoint"`
+	// SampleRatio is the fraction of new traces that are sampled, from 0 to 1.
+	SampleRatio float64 `envconfig:"TRACING_SAMPLE_RATIO" yaml:"sample_ratio"`
+	// ServiceName is reported as the service.name resource attribute of the spans.
+	ServiceName string `envconfig:"TRACING_SERVICE_NAME" yaml:"service_name"`
+}
+
+// Default returns the configuration used when neither a configuration file nor
+// environment variables override a setting.
+func Default() *Config {
+	return &Config{
+		Storage: StorageConfig{
+			Backend: BackendPostgres,
+		},
+		Database: DatabaseConfig{
+			Host:    "localhost",
+			Port:    5432,
+			User:    "scheduler",
+			Name:    "scheduler",
+			SSLMode: "disable",
+		},
+		Redis: RedisConfig{
+			Host: "localhost",
+			Port: 6379,
+		},
+		Log: defaultLogConfig(),
+		Scheduler: SchedulerConfig{
+			TickInterval:        time.Second,
+			StaleThreshold:      10 * time.Second,
+			ElectionInterval:    5 * time.Second,
+			ShutdownGracePeriod: 30 * time.Second,
+		},
+		Executor: ExecutorConfig{
+			Workers:           4,
+			PollInterval:      time.Second,
+			RequestTimeout:    30 * time.Second,
+			VisibilityTimeout: 30 * time.Second,
+			HeartbeatInterval: 10 * time.Second,
+		},
+		Retention: RetentionConfig{
+			Interval:  time.Hour,
+			BatchSize: 1000,
+		},
+		Tracing: TracingConfig{
+			SampleRatio: 1,
+			ServiceName: "go-dist-scheduler",
+		},
+	}
 }
 
-// Load reads configuration from environment variables.
+func defaultLogConfig() LogConfig {
+	return LogConfig{
+		Format: LogFormatText,
+		Level:  "info",
+	}
+}
+
+// Load reads configuration from the YAML file named by CONFIG_FILE, if set,
+// and then from environment variables, which take precedence over the file.
 func Load() (*Config, error) {
-	var cfg Config
+	return LoadFile(os.Getenv(FileEnv))
+}
+
+// LoadFile reads configuration from the YAML file at path and then from
+// environment variables, which take precedence over the file. Settings found
+// in neither keep their default values. An empty path skips the file.
+func LoadFile(path string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		if err := cfg.readFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	sections := []struct {
+		name string
+		spec any
+	}{
+		{"storage", &cfg.Storage},
+		{"database", &cfg.Database},
+		{"redis", &cfg.Redis},
+		{"log", &cfg.Log},
+		{"scheduler", &cfg.Scheduler},
+		{"executor", &cfg.Executor},
+		{"retention", &cfg.Retention},
+		{"tracing", &cfg.Tracing},
+	}
+	for _, s := range sections {
+		if err := envconfig.Process("", s.spec); err != nil {
+			return nil, fmt.Errorf("failed to load %s config: %w", s.name, err)
+		}
+	}
 
-	if err := envconfig.Process("", &cfg.Database); err != nil {
-		return nil, fmt.Errorf("failed to load database config: %w", err)
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
 
-	if err := envconfig.Process("", &cfg.Redis); err != nil {
-		return nil, fmt.Errorf("failed to load redis config: %w", err)
+	return cfg, nil
+}
+
+// readFile overrides the configuration with the settings in the YAML file at path.
+// Unknown keys are rejected so that typos do not go unnoticed.
+func (c *Config) readFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open config file: %w", err)
 	}
+	defer func() {
+		_ = f.Close()
+	}()
 
-	return &cfg, nil
+	dec := yaml.NewDecoder(f)
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Validate reports the first invalid setting in the configuration.
+func (c *Config) Validate() error {
+	switch c.Storage.Backend {
+	case BackendMemory, BackendPostgres, BackendRedis:
+	default:
+		return fmt.Errorf("failed to load storage config: invalid backend %q: must be %q, %q or %q",
+			c.Storage.Backend, BackendMemory, BackendPostgres, BackendRedis)
+	}
+
+	if c.Storage.Backend != BackendMemory && c.Database.Password == "" {
+		return errors.New("failed to load database config: required key DB_PASSWORD missing value")
+	}
+
+	if _, err := c.Log.NewLogger(io.Discard); err != nil {
+		return fmt.Errorf("failed to load log config: %w", err)
+	}
+
+	if err := c.Scheduler.validate(); err != nil {
+		return fmt.Errorf("failed to load scheduler config: %w", err)
+	}
+
+	if err := c.Executor.validate(); err != nil {
+		return fmt.Errorf("failed to load executor config: %w", err)
+	}
+
+	if err := c.Retention.validate(); err != nil {
+		return fmt.Errorf("failed to load retention config: %w", err)
+	}
+
+	if err := c.Tracing.validate(); err != nil {
+		return fmt.Errorf("failed to load tracing config: %w", err)
+	}
+
+	return nil
 }
 
 // DSN returns the database connection string.
@@ -53,3 +294,89 @@ func (d *DatabaseConfig) DSN() string {
 func (r *RedisConfig) Addr() string {
 	return fmt.Sprintf("%s:%d", r.Host, r.Port)
 }
+
+// NewLogger returns a logger that writes to w in the configured format and level.
+func (l *LogConfig) NewLogger(w io.Writer) (*slog.Logger, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(l.Level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q", l.Level)
+	}
+	opts := &slog.HandlerOptions{Level: level}
+
+	switch l.Format {
+	case LogFormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case LogFormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q: must be %q or %q", l.Format, LogFormatText, LogFormatJSON)
+	}
+}
+
+func (s *SchedulerConfig) validate() error {
+	if s.TickInterval <= 0 {
+		return fmt.Errorf("tick interval must be positive, got %s", s.TickInterval)
+	}
+	if s.StaleThreshold <= 0 {
+		return fmt.Errorf("stale threshold must be positive, got %s", s.StaleThreshold)
+	}
+	if s.ElectionInterval <= 0 {
+		return fmt.Errorf("election interval must be positive, got %s", s.ElectionInterval)
+	}
+	if s.ShutdownGracePeriod < 0 {
+		return fmt.Errorf("shutdown grace period must not be negative, got %s", s.ShutdownGracePeriod)
+	}
+	return nil
+}
+
+func (e *ExecutorConfig) validate() error {
+	if e.Workers < 1 {
+		return fmt.Errorf("workers must be at least 1, got %d", e.Workers)
+	}
+	if e.PollInterval <= 0 {
+		return fmt.Errorf("poll interval must be positive, got %s", e.PollInterval)
+	}
+	if e.RequestTimeout < 0 {
+		return fmt.Errorf("request timeout must not be negative, got %s", e.RequestTimeout)
+	}
+	if e.VisibilityTimeout <= 0 {
+		return fmt.Errorf("visibility timeout must be positive, got %s", e.VisibilityTimeout)
+	}
+	if e.HeartbeatInterval <= 0 || e.HeartbeatInterval >= e.VisibilityTimeout {
+		return fmt.Errorf("heartbeat interval must be positive and shorter than the visibility timeout %s, got %s",
+			e.VisibilityTimeout, e.HeartbeatInterval)
+	}
+	return nil
+}
+
+func (r *RetentionConfig) validate() error {
+	if r.Interval <= 0 {
+		return fmt.Errorf("interval must be positive, got %s", r.Interval)
+	}
+	if r.BatchSize < 1 {
+		return fmt.Errorf("batch size must be at least 1, got %d", r.BatchSize)
+	}
+	if r.MaxAge < 0 || r.FailedMaxAge < 0 {
+		return fmt.Errorf("max age must not be negative, got %s and %s for failed jobs", r.MaxAge, r.FailedMaxAge)
+	}
+	if r.MaxJobs < 0 || r.FailedMaxJobs < 0 {
+		return fmt.Errorf("max jobs must not be negative, got %d and %d for failed jobs", r.MaxJobs, r.FailedMaxJobs)
+	}
+	return nil
+}
+
+func (t *TracingConfig) validate() error {
+	if t.Endpoint != "" {
+		u, err := url.Parse(t.Endpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("endpoint must be an http or https URL, got %q", t.Endpoint)
+		}
+	}
+	if t.SampleRatio < 0 || t.SampleRatio > 1 {
+		return fmt.Errorf("sample ratio must be between 0 and 1, got %g", t.SampleRatio)
+	}
+	if t.ServiceName == "" {
+		return errors.New("service name must not be empty")
+	}
+	return nil
+}
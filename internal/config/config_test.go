@@ -1,8 +1,12 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -170,6 +174,269 @@ func TestLoad_InvalidRedisPortValue(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to load redis config")
 }
 
+func TestLoad_InvalidLogFormat(t *testing.T) {
+	setEnv(t, map[string]string{
+		"DB_PASSWORD": "testpass",
+		"LOG_FORMAT":  "xml",
+	})
+	defer clearEnv(t)
+
+	_, err := Load()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load log config")
+}
+
+func TestLogConfig_NewLogger(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := (&LogConfig{Format: LogFormatJSON, Level: "info"}).NewLogger(&buf)
+		require.NoError(t, err)
+
+		logger.Debug("dropped")
+		logger.Info("hello", "job_id", "job-1")
+
+		var line map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &line), "only the info line must be written")
+		assert.Equal(t, "hello", line["msg"])
+		assert.Equal(t, "job-1", line["job_id"])
+	})
+
+	t.Run("text", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := (&LogConfig{Format: LogFormatText, Level: "warn"}).NewLogger(&buf)
+		require.NoError(t, err)
+
+		logger.Info("dropped")
+		logger.Warn("hello", "job_id", "job-1")
+		assert.Contains(t, buf.String(), "msg=hello job_id=job-1")
+		assert.NotContains(t, buf.String(), "dropped")
+	})
+
+	t.Run("invalid level", func(t *testing.T) {
+		_, err := (&LogConfig{Format: LogFormatText, Level: "verbose"}).NewLogger(&bytes.Buffer{})
+		assert.ErrorContains(t, err, "invalid log level")
+	})
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	setEnv(t, map[string]string{"DB_PASSWORD": "testpass"})
+	defer clearEnv(t)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, BackendPostgres, cfg.Storage.Backend)
+	assert.Equal(t, time.Second, cfg.Scheduler.TickInterval)
+	assert.Equal(t, 10*time.Second, cfg.Scheduler.StaleThreshold)
+	assert.Equal(t, 5*time.Second, cfg.Scheduler.ElectionInterval)
+	assert.Equal(t, 30*time.Second, cfg.Scheduler.ShutdownGracePeriod)
+	assert.Equal(t, 4, cfg.Executor.Workers)
+	assert.Equal(t, time.Second, cfg.Executor.PollInterval)
+	assert.Equal(t, 30*time.Second, cfg.Executor.RequestTimeout)
+	assert.Equal(t, 30*time.Second, cfg.Executor.VisibilityTimeout)
+	assert.Equal(t, 10*time.Second, cfg.Executor.HeartbeatInterval)
+	assert.Equal(t, time.Hour, cfg.Retention.Interval)
+	assert.Equal(t, 1000, cfg.Retention.BatchSize)
+	assert.Zero(t, cfg.Retention.MaxAge, "job history is kept forever by default")
+	assert.Zero(t, cfg.Retention.FailedMaxAge)
+	assert.Empty(t, cfg.Tracing.Endpoint, "spans are not exported by default")
+	assert.Equal(t, 1.0, cfg.Tracing.SampleRatio)
+	assert.Equal(t, "go-dist-scheduler", cfg.Tracing.ServiceName)
+}
+
+func TestLoad_TracingFromEnvironmentVariables(t *testing.T) {
+	setEnv(t, map[string]string{
+		"BACKEND":              "memory",
+		"TRACING_ENDPOINT":     "http://otel-collector:4318",
+		"TRACING_SAMPLE_RATIO": "0.25",
+		"TRACING_SERVICE_NAME": "scheduler-eu",
+	})
+	defer clearEnv(t)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "http://otel-collector:4318", cfg.Tracing.Endpoint)
+	assert.Equal(t, 0.25, cfg.Tracing.SampleRatio)
+	assert.Equal(t, "scheduler-eu", cfg.Tracing.ServiceName)
+}
+
+func TestLoad_RetentionFromEnvironmentVariables(t *testing.T) {
+	setEnv(t, map[string]string{
+		"BACKEND":                   "memory",
+		"RETENTION_MAX_AGE":         "720h",
+		"RETENTION_FAILED_MAX_AGE":  "2160h",
+		"RETENTION_FAILED_MAX_JOBS": "500",
+	})
+	defer clearEnv(t)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, 720*time.Hour, cfg.Retention.MaxAge)
+	assert.Zero(t, cfg.Retention.MaxJobs)
+	assert.Equal(t, 2160*time.Hour, cfg.Retention.FailedMaxAge)
+	assert.Equal(t, 500, cfg.Retention.FailedMaxJobs)
+}
+
+func TestLoad_MemoryBackendDoesNotRequirePassword(t *testing.T) {
+	setEnv(t, map[string]string{"BACKEND": "memory"})
+	defer clearEnv(t)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, BackendMemory, cfg.Storage.Backend)
+}
+
+func TestLoad_SchedulerAndExecutorFromEnvironmentVariables(t *testing.T) {
+	setEnv(t, map[string]string{
+		"BACKEND":                         "redis",
+		"DB_PASSWORD":                     "testpass",
+		"SCHEDULER_TICK_INTERVAL":         "500ms",
+		"SCHEDULER_SHUTDOWN_GRACE_PERIOD": "1m",
+		"EXECUTOR_WORKERS":                "16",
+		"EXECUTOR_REQUEST_TIMEOUT":        "0s",
+	})
+	defer clearEnv(t)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, BackendRedis, cfg.Storage.Backend)
+	assert.Equal(t, 500*time.Millisecond, cfg.Scheduler.TickInterval)
+	assert.Equal(t, time.Minute, cfg.Scheduler.ShutdownGracePeriod)
+	assert.Equal(t, 16, cfg.Executor.Workers)
+	assert.Zero(t, cfg.Executor.RequestTimeout)
+}
+
+func TestLoad_InvalidSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected string
+	}{
+		{
+			name:     "unknown backend",
+			env:      map[string]string{"BACKEND": "mysql"},
+			expected: "failed to load storage config: invalid backend \"mysql\"",
+		},
+		{
+			name:     "no workers",
+			env:      map[string]string{"BACKEND": "memory", "EXECUTOR_WORKERS": "0"},
+			expected: "failed to load executor config: workers must be at least 1",
+		},
+		{
+			name:     "heartbeat longer than the visibility timeout",
+			env:      map[string]string{"BACKEND": "memory", "EXECUTOR_HEARTBEAT_INTERVAL": "1m"},
+			expected: "failed to load executor config: heartbeat interval must be positive and shorter than the visibility timeout",
+		},
+		{
+			name:     "zero tick interval",
+			env:      map[string]string{"BACKEND": "memory", "SCHEDULER_TICK_INTERVAL": "0s"},
+			expected: "failed to load scheduler config: tick interval must be positive",
+		},
+		{
+			name:     "negative retention",
+			env:      map[string]string{"BACKEND": "memory", "RETENTION_MAX_JOBS": "-1"},
+			expected: "failed to load retention config: max jobs must not be negative",
+		},
+		{
+			name:     "tracing endpoint without a scheme",
+			env:      map[string]string{"BACKEND": "memory", "TRACING_ENDPOINT": "localhost:4318"},
+			expected: "failed to load tracing config: endpoint must be an http or https URL",
+		},
+		{
+			name:     "sample ratio above 1",
+			env:      map[string]string{"BACKEND": "memory", "TRACING_SAMPLE_RATIO": "1.5"},
+			expected: "failed to load tracing config: sample ratio must be between 0 and 1",
+		},
+		{
+			name:     "unparsable duration",
+			env:      map[string]string{"BACKEND": "memory", "EXECUTOR_POLL_INTERVAL": "soon"},
+			expected: "failed to load executor config",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, tt.env)
+			defer clearEnv(t)
+
+			_, err := Load()
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
+
+// Helper function to write a YAML config file to a temporary directory
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadFile_EnvironmentVariablesOverrideFile(t *testing.T) {
+	path := writeConfigFile(t, `
+storage:
+  backend: redis
+database:
+  host: db.internal
+  password: filepass
+redis:
+  host: redis.internal
+scheduler:
+  tick_interval: 2s
+executor:
+  workers: 8
+  poll_interval: 250ms
+`)
+	setEnv(t, map[string]string{
+		"DB_PASSWORD":      "envpass",
+		"EXECUTOR_WORKERS": "2",
+	})
+	defer clearEnv(t)
+
+	cfg, err := LoadFile(path)
+	require.NoError(t, err)
+
+	// Values from the file
+	assert.Equal(t, BackendRedis, cfg.Storage.Backend)
+	assert.Equal(t, "db.internal", cfg.Database.Host)
+	assert.Equal(t, "redis.internal", cfg.Redis.Host)
+	assert.Equal(t, 2*time.Second, cfg.Scheduler.TickInterval)
+	assert.Equal(t, 250*time.Millisecond, cfg.Executor.PollInterval)
+
+	// Values overridden by environment variables
+	assert.Equal(t, "envpass", cfg.Database.Password)
+	assert.Equal(t, 2, cfg.Executor.Workers)
+
+	// Values in neither keep their defaults
+	assert.Equal(t, 5432, cfg.Database.Port)
+	assert.Equal(t, 30*time.Second, cfg.Scheduler.ShutdownGracePeriod)
+}
+
+func TestLoad_ReadsFileFromEnvironmentVariable(t *testing.T) {
+	path := writeConfigFile(t, "storage:\n  backend: memory\n")
+	setEnv(t, map[string]string{"CONFIG_FILE": path})
+	defer clearEnv(t)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, BackendMemory, cfg.Storage.Backend)
+}
+
+func TestLoadFile_Errors(t *testing.T) {
+	clearEnv(t)
+
+	_, err := LoadFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, err, "failed to open config file")
+
+	_, err = LoadFile(writeConfigFile(t, "storage:\n  backnd: memory\n"))
+	assert.ErrorContains(t, err, "failed to parse config file")
+
+	cfg, err := LoadFile(writeConfigFile(t, ""))
+	require.Error(t, err, "an empty file keeps the defaults, which require DB_PASSWORD")
+	assert.Nil(t, cfg)
+}
+
 // Helper function to set environment variables for testing
 func setEnv(t *testing.T, vars map[string]string) {
 	t.Helper()
@@ -185,6 +452,12 @@ func clearEnv(t *testing.T) {
 	envVars := []string{
 		"DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD", "DB_NAME", "DB_SSLMODE",
 		"REDIS_HOST", "REDIS_PORT",
+		"LOG_FORMAT", "LOG_LEVEL",
+		"BACKEND", "CONFIG_FILE",
+		"SCHEDULER_TICK_INTERVAL", "SCHEDULER_STALE_THRESHOLD", "SCHEDULER_ELECTION_INTERVAL", "SCHEDULER_SHUTDOWN_GRACE_PERIOD",
+		"EXECUTOR_WORKERS", "EXECUTOR_POLL_INTERVAL", "EXECUTOR_REQUEST_TIMEOUT", "EXECUTOR_VISIBILITY_TIMEOUT", "EXECUTOR_HEARTBEAT_INTERVAL",
+		"RETENTION_INTERVAL", "RETENTION_BATCH_SIZE", "RETENTION_MAX_AGE", "RETENTION_MAX_JOBS", "RETENTION_FAILED_MAX_AGE", "RETENTION_FAILED_MAX_JOBS",
+		"TRACING_ENDPOINT", "TRACING_SAMPLE_RATIO", "TRACING_SERVICE_NAME",
 	}
 	for _, key := range envVars {
 		err := os.Unsetenv(key)
@@ -0,0 +1,25 @@
+package domain
+
+import "fmt"
+
+// ConcurrencyPolicy は、同じタスクの実行が重なる場合の扱い方を表します（Kubernetes の CronJob の concurrencyPolicy に相当）。
+// タスクの実行中とは、そのタスクのジョブが Pending（リトライ待ちを含む）または Running であることを指します。
+type ConcurrencyPolicy int
+
+const (
+	// ConcurrencyAllow は、前回の実行が終わっていなくても新しい実行を開始します。
+	ConcurrencyAllow ConcurrencyPolicy = iota
+	// ConcurrencyForbid は、前回の実行が終わっていない場合、新しい実行を読み飛ばします。
+	ConcurrencyForbid
+	// ConcurrencyReplace は、前回の実行が終わっていない場合、それをキャンセルして新しい実行に置き換えます。
+	ConcurrencyReplace
+)
+
+// Validate は、ConcurrencyPolicy が定義済みの値であるかを検証します。
+// 無効な場合は ErrInvalidArgument をラップしたエラーを返します。
+func (p ConcurrencyPolicy) Validate() error {
+	if p < ConcurrencyAllow || p > ConcurrencyReplace {
+		return fmt.Errorf("%w: unknown concurrency policy %d", ErrInvalidArgument, p)
+	}
+	return nil
+}
@@ -7,3 +7,21 @@ var ErrConflict = errors.New("conflict")
 
 // ErrConstraintViolation is returned when a database constraint is violated (e.g., unique constraint).
 var ErrConstraintViolation = errors.New("constraint violation")
+
+// ErrInvalidArgument is returned when an entity fails validation.
+var ErrInvalidArgument = errors.New("invalid argument")
+
+// ErrJobTimedOut is wrapped by the error of a job execution that did not finish
+// within the timeout of its task.
+var ErrJobTimedOut = errors.New("job timed out")
+
+// ErrJobCancelled is used as the cancellation cause of a running job that was cancelled
+// because a newer run of its task replaced it.
+var ErrJobCancelled = errors.New("job cancelled")
+
+// ErrJobInterrupted is used as the cancellation cause of a running job that was aborted
+// because the scheduler shut down before the job finished within the grace period.
+var ErrJobInterrupted = errors.New("job interrupted by shutdown")
+
+// ErrNotFound is returned by use cases when the entity they operate on does not exist.
+var ErrNotFound = errors.New("not found")
@@ -1,6 +1,10 @@
 package domain
 
-import "time"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type JobStatus int
 
@@ -9,8 +13,39 @@ const (
 	JobStatusRunning
 	JobStatusSuccess
 	JobStatusFailed
+	// JobStatusCancelled は、タスクの ConcurrencyReplace により新しい実行に置き換えられたジョブの状態です。
+	// 終了状態の一つで、キャンセルされたジョブのステータスはそれ以降変更されません。
+	JobStatusCancelled
+	// JobStatusTimedOut は、タスクの Timeout までに実行が終わらず、リトライも残っていないジョブの状態です。
+	// 終了状態の一つで、通常の失敗（JobStatusFailed）と区別するために使用します。
+	JobStatusTimedOut
+)
+
+// TriggerSource は、ジョブが作成された契機を表します。
+type TriggerSource int
+
+const (
+	// TriggerSourceCron は、タスクのcron式のスケジュールに従ってスケジューラーが作成したジョブです。
+	TriggerSourceCron TriggerSource = iota
+	// TriggerSourceManual は、オペレーターがスケジュール外で即時実行を指示して作成したジョブです。
+	TriggerSourceManual
 )
 
+// DefaultVisibilityTimeout は、デキューされたジョブのリース期間のデフォルト値です。
+// リースが延長されないまま期限切れになったジョブは、実行中のプロセスが停止したものとみなされ回収されます。
+const DefaultVisibilityTimeout = 30 * time.Second
+
+// jobIDNamespace は、NewJobID で決定的なUUIDを生成する際の名前空間です。
+var jobIDNamespace = uuid.MustParse("8d5b8f0e-4f7c-4b7e-9a35-0f7c3c1f2a6d")
+
+// NewJobID は、タスクIDとスケジュール時刻から決定的なジョブIDを生成します。
+// 同じタスク・同じスケジュール時刻（タイムゾーンによらず同一の瞬間）に対しては常に同じIDを返すため、
+// 重複したエンキューをリポジトリで検出できます。
+func NewJobID(taskID string, scheduledAt time.Time) string {
+	name := taskID + "@" + scheduledAt.UTC().Format(time.RFC3339Nano)
+	return uuid.NewSHA1(jobIDNamespace, []byte(name)).String()
+}
+
 type Job struct {
 	ID          string
 	TaskID      string
@@ -19,8 +54,18 @@ type Job struct {
 	FinishedAt  time.Time
 	Status      JobStatus
 	RetryCount  int
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	// AvailableAt は、ジョブをデキューできるようになる時刻です。ゼロ値の場合は即座にデキューできます。
+	AvailableAt time.Time
+	// TriggerSource は、ジョブが作成された契機です。ゼロ値は TriggerSourceCron です。
+	TriggerSource TriggerSource
+	// TraceContext は、ジョブをスケジュールしたときのトレースコンテキストを、W3C Trace Context の
+	// ヘッダー名（traceparent・tracestate）をキーとして保持します。ジョブを別のプロセスで実行しても、
+	// 同じトレースに属するスパンとして記録するために使用します。
+	TraceContext map[string]string
+	// LeaseExpiresAt は、ジョブを実行中のプロセスが保持するリースの期限です。ゼロ値の場合はリースされていません。
+	LeaseExpiresAt time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
 func (j *Job) MarkAsRunning() {
@@ -40,3 +85,103 @@ func (j *Job) MarkAsFailed() {
 	j.FinishedAt = time.Now()
 	j.UpdatedAt = time.Now()
 }
+
+// MarkAsTimedOut は、ジョブをタイムアウトにより終了した状態にします。
+func (j *Job) MarkAsTimedOut() {
+	j.Status = JobStatusTimedOut
+	j.FinishedAt = time.Now()
+	j.UpdatedAt = time.Now()
+}
+
+// MarkAsCancelled は、ジョブをキャンセル済みにします。
+func (j *Job) MarkAsCancelled() {
+	j.Status = JobStatusCancelled
+	j.FinishedAt = time.Now()
+	j.LeaseExpiresAt = time.Time{}
+	j.UpdatedAt = time.Now()
+}
+
+// ScheduleRetry は、失敗したジョブを availableAt 以降に再実行されるPendingへ戻し、リトライ回数を加算します。
+func (j *Job) ScheduleRetry(availableAt time.Time) {
+	j.Status = JobStatusPending
+	j.RetryCount++
+	j.AvailableAt = availableAt
+	j.StartedAt = time.Time{}
+	j.FinishedAt = time.Time{}
+	j.LeaseExpiresAt = time.Time{}
+	j.UpdatedAt = time.Now()
+}
+
+// Release は、シャットダウンにより実行を中断したジョブを、リトライ回数を加算せずに Pending へ戻します。
+// 中断は実行の失敗ではないため、次にデキューされたときは同じ試行として実行されます。
+func (j *Job) Release() {
+	j.Status = JobStatusPending
+	j.StartedAt = time.Time{}
+	j.FinishedAt = time.Time{}
+	j.LeaseExpiresAt = time.Time{}
+	j.UpdatedAt = time.Now()
+}
+
+// IsAvailable は、ジョブが now の時点でデキュー可能かを返します。
+func (j *Job) IsAvailable(now time.Time) bool {
+	return !j.AvailableAt.After(now)
+}
+
+// IsFinished は、ジョブが終了状態（Success・Failed・Cancelled・TimedOut）であるかを返します。
+func (j *Job) IsFinished() bool {
+	switch j.Status {
+	case JobStatusSuccess, JobStatusFailed, JobStatusCancelled, JobStatusTimedOut:
+		return true
+	}
+	return false
+}
+
+// Attempt は、ジョブの現在の試行回数を1から数えて返します。
+func (j *Job) Attempt() int {
+	return j.RetryCount + 1
+}
+
+// IsLeaseExpired は、ジョブがリースされたまま now の時点で期限切れになっているかを返します。
+func (j *Job) IsLeaseExpired(now time.Time) bool {
+	return !j.IsFinished() && !j.LeaseExpiresAt.IsZero() && j.LeaseExpiresAt.Before(now)
+}
+
+// MaxResponseBodySize は、JobResult に保存するレスポンスボディの最大バイト数です。
+// これを超える部分は切り捨てられます。
+const MaxResponseBodySize = 64 * 1024
+
+// JobResult は、ジョブの1回の試行（リトライを含む）の実行結果です。
+type JobResult struct {
+	JobID   string
+	TaskID  string
+	Attempt int
+	// StatusCode は、レスポンスのHTTPステータスコードです。レスポンスを受け取れなかった場合は0です。
+	StatusCode      int
+	ResponseHeaders map[string]string
+	// ResponseBody は、MaxResponseBodySize までに切り詰められたレスポンスボディです。
+	ResponseBody []byte
+	// BodyTruncated は、ResponseBody が切り詰められたかを表します。
+	BodyTruncated bool
+	// Error は、試行が失敗した場合のエラーメッセージです。成功した場合は空です。
+	Error      string
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Duration   time.Duration
+}
+
+// NewJobResult は、ジョブの現在の試行に対する JobResult を生成します。
+func NewJobResult(job *Job, startedAt, finishedAt time.Time) *JobResult {
+	return &JobResult{
+		JobID:      job.ID,
+		TaskID:     job.TaskID,
+		Attempt:    job.Attempt(),
+		StartedAt:  startedAt,
+		FinishedAt: finishedAt,
+		Duration:   finishedAt.Sub(startedAt),
+	}
+}
+
+// IsSuccess は、試行が成功したかを返します。
+func (r *JobResult) IsSuccess() bool {
+	return r.Error == ""
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -32,3 +33,73 @@ func TestJob_MarkAsFailed(t *testing.T) {
 	assert.NotZero(t, job.FinishedAt)
 	assert.NotZero(t, job.UpdatedAt)
 }
+
+func TestJob_ScheduleRetry(t *testing.T) {
+	availableAt := time.Now().Add(time.Minute)
+	job := &Job{Status: JobStatusRunning, RetryCount: 1, LeaseExpiresAt: time.Now()}
+	job.MarkAsFailed()
+	job.ScheduleRetry(availableAt)
+
+	assert.Equal(t, JobStatusPending, job.Status)
+	assert.Equal(t, 2, job.RetryCount)
+	assert.Equal(t, availableAt, job.AvailableAt)
+	assert.Zero(t, job.FinishedAt)
+	assert.Zero(t, job.LeaseExpiresAt)
+	assert.NotZero(t, job.UpdatedAt)
+}
+
+func TestJob_Release(t *testing.T) {
+	job := &Job{Status: JobStatusRunning, RetryCount: 1, StartedAt: time.Now(), LeaseExpiresAt: time.Now()}
+	job.Release()
+
+	assert.Equal(t, JobStatusPending, job.Status)
+	assert.Equal(t, 1, job.RetryCount, "an interrupted run must not count as a retry")
+	assert.Zero(t, job.StartedAt)
+	assert.Zero(t, job.LeaseExpiresAt)
+	assert.NotZero(t, job.UpdatedAt)
+}
+
+func TestJob_IsAvailable(t *testing.T) {
+	now := time.Now()
+
+	assert.True(t, (&Job{}).IsAvailable(now))
+	assert.True(t, (&Job{AvailableAt: now}).IsAvailable(now))
+	assert.False(t, (&Job{AvailableAt: now.Add(time.Second)}).IsAvailable(now))
+}
+
+func TestNewJobID(t *testing.T) {
+	scheduledAt := time.Date(2024, time.April, 1, 9, 0, 0, 0, time.UTC)
+	jst := time.FixedZone("JST", 9*60*60)
+
+	id := NewJobID("task1", scheduledAt)
+	assert.Equal(t, id, NewJobID("task1", scheduledAt), "same input must produce the same ID")
+	assert.Equal(t, id, NewJobID("task1", scheduledAt.In(jst)), "the same instant in another zone must produce the same ID")
+	assert.NotEqual(t, id, NewJobID("task2", scheduledAt))
+	assert.NotEqual(t, id, NewJobID("task1", scheduledAt.Add(time.Minute)))
+}
+
+func TestJob_IsLeaseExpired(t *testing.T) {
+	now := time.Now()
+
+	assert.False(t, (&Job{Status: JobStatusRunning}).IsLeaseExpired(now), "job without lease")
+	assert.False(t, (&Job{Status: JobStatusRunning, LeaseExpiresAt: now.Add(time.Second)}).IsLeaseExpired(now), "lease not expired yet")
+	assert.True(t, (&Job{Status: JobStatusRunning, LeaseExpiresAt: now.Add(-time.Second)}).IsLeaseExpired(now))
+	assert.True(t, (&Job{Status: JobStatusPending, LeaseExpiresAt: now.Add(-time.Second)}).IsLeaseExpired(now), "dequeued but never started")
+	assert.False(t, (&Job{Status: JobStatusSuccess, LeaseExpiresAt: now.Add(-time.Second)}).IsLeaseExpired(now), "finished job")
+}
+
+func TestNewJobResult(t *testing.T) {
+	startedAt := time.Now()
+	finishedAt := startedAt.Add(1500 * time.Millisecond)
+	job := &Job{ID: "job1", TaskID: "task1", RetryCount: 2}
+
+	result := NewJobResult(job, startedAt, finishedAt)
+	assert.Equal(t, "job1", result.JobID)
+	assert.Equal(t, "task1", result.TaskID)
+	assert.Equal(t, 3, result.Attempt, "attempt is counted from 1")
+	assert.Equal(t, 1500*time.Millisecond, result.Duration)
+	assert.True(t, result.IsSuccess())
+
+	result.Error = "unexpected status code: 500"
+	assert.False(t, result.IsSuccess())
+}
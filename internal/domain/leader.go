@@ -0,0 +1,12 @@
+package domain
+
+import "context"
+
+// LeaderLock は、複数のスケジューラーインスタンスの中から1つのリーダーを選出するための分散ロックです。
+// 実装は、リーダーのプロセスが停止した場合に一定時間内にロックが解放されることを保証する必要があります。
+type LeaderLock interface {
+	// TryAcquire は、リーダーシップの獲得または維持を試み、このインスタンスが現在リーダーであるかを返します。
+	TryAcquire(ctx context.Context) (bool, error)
+	// Release は、保持しているリーダーシップを手放します。保持していない場合は何もしません。
+	Release(ctx context.Context) error
+}
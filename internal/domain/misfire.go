@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultMisfireThreshold は、実行時刻を過ぎてからどれだけ経過すると実行漏れ（ミスファイア）とみなすかの既定値です。
+// スケジューラーのチェック間隔による通常の遅れがミスファイアとして扱われないよう、チェック間隔より十分長くします。
+const DefaultMisfireThreshold = time.Minute
+
+// MisfireStrategy は、スケジューラーのダウンタイムなどで実行されなかった実行時刻の扱い方を表します。
+type MisfireStrategy int
+
+const (
+	// MisfireFireAll は、実行されなかった実行時刻のジョブをすべてエンキューします。
+	MisfireFireAll MisfireStrategy = iota
+	// MisfireFireLatest は、実行されなかった実行時刻のうち最新の1件のみエンキューします。
+	// ミスファイアではない実行時刻がある場合は、そちらが最新のためミスファイアはすべて読み飛ばします。
+	MisfireFireLatest
+	// MisfireSkip は、実行されなかった実行時刻をすべて読み飛ばします。
+	MisfireSkip
+	// MisfireFireLimited は、実行されなかった実行時刻のうち MaxRuns・MaxAge の範囲内のものをエンキューします。
+	MisfireFireLimited
+)
+
+// MisfirePolicy は、タスクのミスファイアの扱い方を表します。
+// ゼロ値は実行されなかった実行時刻をすべてエンキューすることを意味します。
+type MisfirePolicy struct {
+	// Strategy は、ミスファイアの扱い方です。
+	Strategy MisfireStrategy
+	// MaxRuns は、MisfireFireLimited でエンキューするミスファイアの最大件数です。新しいものから数えます。
+	// 0の場合は件数で制限しません。
+	MaxRuns int
+	// MaxAge は、MisfireFireLimited でエンキューするミスファイアの実行時刻の古さの上限です。
+	// 0の場合は古さで制限しません。
+	MaxAge time.Duration
+}
+
+// Validate は、ミスファイアポリシーの各値が有効な範囲にあるかを検証します。
+// 無効な場合は ErrInvalidArgument をラップしたエラーを返します。
+func (p MisfirePolicy) Validate() error {
+	switch {
+	case p.Strategy < MisfireFireAll || p.Strategy > MisfireFireLimited:
+		return fmt.Errorf("%w: unknown misfire strategy %d", ErrInvalidArgument, p.Strategy)
+	case p.MaxRuns < 0:
+		return fmt.Errorf("%w: misfire max runs must not be negative", ErrInvalidArgument)
+	case p.MaxAge < 0:
+		return fmt.Errorf("%w: misfire max age must not be negative", ErrInvalidArgument)
+	case p.Strategy == MisfireFireLimited && p.MaxRuns == 0 && p.MaxAge == 0:
+		return fmt.Errorf("%w: misfire max runs or max age is required for the limited strategy", ErrInvalidArgument)
+	}
+	return nil
+}
+
+// Apply は、時刻順に並んだ実行時刻 runTimes から、now の時点でエンキューすべきものを返します。
+// now より threshold 以上前の実行時刻をミスファイアとみなし、ポリシーに従って間引きます。
+// ミスファイアではない実行時刻は常にそのまま返します。
+func (p MisfirePolicy) Apply(runTimes []time.Time, now time.Time, threshold time.Duration) []time.Time {
+	// runTimes は時刻順のため、先頭から misfired 件がミスファイアになる
+	misfired := 0
+	for misfired < len(runTimes) && now.Sub(runTimes[misfired]) >= threshold {
+		misfired++
+	}
+	missed, onTime := runTimes[:misfired], runTimes[misfired:]
+
+	switch p.Strategy {
+	case MisfireFireLatest:
+		if len(onTime) > 0 || len(missed) == 0 {
+			return onTime
+		}
+		return missed[len(missed)-1:]
+	case MisfireSkip:
+		return onTime
+	case MisfireFireLimited:
+		if p.MaxAge > 0 {
+			for len(missed) > 0 && now.Sub(missed[0]) > p.MaxAge {
+				missed = missed[1:]
+			}
+		}
+		if p.MaxRuns > 0 && len(missed) > p.MaxRuns {
+			missed = missed[len(missed)-p.MaxRuns:]
+		}
+		return append(append([]time.Time(nil), missed...), onTime...)
+	default:
+		return runTimes
+	}
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMisfirePolicy_Validate(t *testing.T) {
+	assert.NoError(t, MisfirePolicy{}.Validate())
+	assert.NoError(t, MisfirePolicy{Strategy: MisfireFireLimited, MaxRuns: 3}.Validate())
+	assert.NoError(t, MisfirePolicy{Strategy: MisfireFireLimited, MaxAge: time.Hour}.Validate())
+
+	invalid := []MisfirePolicy{
+		{Strategy: MisfireStrategy(99)},
+		{Strategy: MisfireFireLimited},
+		{Strategy: MisfireFireLimited, MaxRuns: -1},
+		{Strategy: MisfireFireLimited, MaxAge: -time.Second},
+	}
+	for _, policy := range invalid {
+		assert.ErrorIs(t, policy.Validate(), ErrInvalidArgument, "%+v", policy)
+	}
+}
+
+func TestMisfirePolicy_Apply(t *testing.T) {
+	now := time.Date(2024, time.January, 8, 12, 0, 30, 0, time.UTC)
+	// A per-minute task that was not checked for 10 minutes: 11:51 ... 12:00
+	var runTimes []time.Time
+	for i := 9; i >= 0; i-- {
+		runTimes = append(runTimes, time.Date(2024, time.January, 8, 12, 0, 0, 0, time.UTC).Add(-time.Duration(i)*time.Minute))
+	}
+	onTime := runTimes[9:]
+
+	testCases := []struct {
+		name     string
+		policy   MisfirePolicy
+		runTimes []time.Time
+		expected []time.Time
+	}{
+		{
+			name:     "fire all",
+			policy:   MisfirePolicy{Strategy: MisfireFireAll},
+			runTimes: runTimes,
+			expected: runTimes,
+		},
+		{
+			name:     "fire latest drops misfires when an on-time run exists",
+			policy:   MisfirePolicy{Strategy: MisfireFireLatest},
+			runTimes: runTimes,
+			expected: onTime,
+		},
+		{
+			name:     "fire latest keeps the latest misfire",
+			policy:   MisfirePolicy{Strategy: MisfireFireLatest},
+			runTimes: runTimes[:9],
+			expected: runTimes[8:9],
+		},
+		{
+			name:     "skip",
+			policy:   MisfirePolicy{Strategy: MisfireSkip},
+			runTimes: runTimes,
+			expected: onTime,
+		},
+		{
+			name:     "skip without misfires",
+			policy:   MisfirePolicy{Strategy: MisfireSkip},
+			runTimes: onTime,
+			expected: onTime,
+		},
+		{
+			name:     "up to N",
+			policy:   MisfirePolicy{Strategy: MisfireFireLimited, MaxRuns: 2},
+			runTimes: runTimes,
+			expected: runTimes[7:],
+		},
+		{
+			name:     "within max age",
+			policy:   MisfirePolicy{Strategy: MisfireFireLimited, MaxAge: 3 * time.Minute},
+			runTimes: runTimes,
+			expected: runTimes[7:], // 11:58 and 11:59 are within 3 minutes of 12:00:30
+		},
+		{
+			name:     "up to N within max age",
+			policy:   MisfirePolicy{Strategy: MisfireFireLimited, MaxRuns: 5, MaxAge: 3 * time.Minute},
+			runTimes: runTimes,
+			expected: runTimes[7:],
+		},
+		{
+			name:     "no run times",
+			policy:   MisfirePolicy{Strategy: MisfireFireLatest},
+			runTimes: nil,
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.policy.Apply(tc.runTimes, now, DefaultMisfireThreshold)
+			assert.Equal(t, len(tc.expected), len(got))
+			for i := range tc.expected {
+				assert.True(t, tc.expected[i].Equal(got[i]), "run time %d: expected %v, got %v", i, tc.expected[i], got[i])
+			}
+		})
+	}
+}
@@ -1,15 +1,72 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type TaskRepository interface {
+	// Save は、タスクを保存し、成功すると task.Version を1つ進めます。
+	// 既存のタスクのバージョンが task.Version と異なる場合（読み込んだ後に他の更新が保存された場合）は ErrConflict を返します。
+	// LastCheckedAt は UpdateLastCheckedAt で更新されるため、保存されている時刻より前の値では上書きしません。
 	Save(ctx context.Context, task *Task) error
+	// UpdateLastCheckedAt は、タスクの LastCheckedAt だけを checkedAt に更新します。
+	// 他のフィールドとバージョンは変更しないため、同時に保存された一時停止や内容の変更を上書きしません。
+	// checkedAt が保存されている時刻より前の場合や、タスクが存在しない場合は何もしません。
+	UpdateLastCheckedAt(ctx context.Context, taskID string, checkedAt time.Time) error
 	FindByID(ctx context.Context, id string) (*Task, error)
 	FindAllActive(ctx context.Context) ([]*Task, error)
+	// FindAll は、ステータスによらずすべてのタスクを作成日時の昇順で返します。
+	FindAll(ctx context.Context) ([]*Task, error)
+	// Delete は、タスクを削除します。存在しないタスクの削除はエラーになりません。
+	Delete(ctx context.Context, id string) error
 }
 
 type JobRepository interface {
+	// Enqueue は、ジョブをキューに追加します。同じIDのジョブ、または同じタスク・同じスケジュール時刻の
+	// ジョブが既に存在する場合は ErrConstraintViolation を返します。
 	Enqueue(ctx context.Context, job *Job) error
+	// Dequeue は、デキュー可能なジョブを1つ取り出して Running にし、リポジトリに設定された可視性タイムアウトの間リースします。
+	// リースの期限までに終了状態にならなかったジョブは FindExpiredLeases で取得できます。
 	Dequeue(ctx context.Context) (*Job, error)
+	// UpdateStatus は、ジョブのステータスを更新します。キャンセル済みのジョブのステータスは変更しません。
 	UpdateStatus(ctx context.Context, jobID string, status JobStatus) error
+	// FindByID は、ジョブを取得します。存在しない場合は nil を返します。
+	FindByID(ctx context.Context, jobID string) (*Job, error)
+	// FindByTaskID は、タスクのジョブをスケジュール時刻の降順で最大 limit 件返します。
+	FindByTaskID(ctx context.Context, taskID string, limit int) ([]*Job, error)
+	// FindActiveByTaskID は、タスクの未終了（Pending または Running）のジョブをスケジュール時刻の昇順で返します。
+	// リトライ待ちのジョブも含みます。
+	FindActiveByTaskID(ctx context.Context, taskID string) ([]*Job, error)
+	// CountPending は、デキューを待っている Pending のジョブ（リトライ待ちを含む）の数を返します。
+	CountPending(ctx context.Context) (int, error)
+	// Cancel は、未終了のジョブをキャンセル済みにし、キューから取り除きます。
+	// 終了済みまたは存在しないジョブのキャンセルはエラーになりません。
+	// 実行中のジョブを実行しているプロセスは、ステータスの変化を検知して実行を中断します。
+	Cancel(ctx context.Context, jobID string) error
+	// Requeue は、ScheduleRetry で更新されたジョブを保存し、AvailableAt 以降にデキューされるようキューへ戻します。
+	// キャンセル済みのジョブはキューへ戻しません。
+	Requeue(ctx context.Context, job *Job) error
+	// ExtendLease は、実行中のジョブのリースを現在時刻から可視性タイムアウトの分だけ延長します（ハートビート）。
+	ExtendLease(ctx context.Context, jobID string) error
+	// FindExpiredLeases は、now の時点でリースが期限切れになっている未終了のジョブを最大 limit 件返します。
+	FindExpiredLeases(ctx context.Context, now time.Time, limit int) ([]*Job, error)
+	// ReclaimExpired は、リースの期限が切れたジョブを ScheduleRetry または MarkAsFailed で更新した状態で保存し、
+	// Pending に戻したジョブは AvailableAt 以降にデキューされるようキューへ戻します。
+	// 保存されているジョブが Running で、リースが now の時点で期限切れのままの場合にのみ保存し、保存したかを返します。
+	// FindExpiredLeases の後にジョブが終了した場合やリースが延長された場合は、何もせずに false を返します。
+	ReclaimExpired(ctx context.Context, job *Job, now time.Time) (bool, error)
+	// SaveResult は、ジョブの1回の試行の実行結果を保存します。
+	SaveResult(ctx context.Context, result *JobResult) error
+	// FindResultsByJobID は、ジョブのすべての試行の実行結果を試行回数の昇順で返します。
+	FindResultsByJobID(ctx context.Context, jobID string) ([]*JobResult, error)
+	// FindRecentResultsByTaskID は、タスクの直近の実行結果を終了時刻の降順で最大 limit 件返します。
+	FindRecentResultsByTaskID(ctx context.Context, taskID string, limit int) ([]*JobResult, error)
+}
+
+// JobHistoryRepository は、終了したジョブの履歴を削除するリポジトリです。
+type JobHistoryRepository interface {
+	// DeleteFinished は、filter に該当する終了済みのジョブをその実行結果とともに最大 limit 件削除し、
+	// 削除したジョブの数を返します。未終了のジョブは削除しません。
+	DeleteFinished(ctx context.Context, filter JobPruneFilter, limit int) (int, error)
 }
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// RetentionRule は、終了したジョブの履歴を保持する期間と件数の上限です。
+// 両方を指定した場合は、いずれかの上限を超えたジョブが削除対象になります。
+// ゼロ値は無期限に保持することを意味します。
+type RetentionRule struct {
+	// MaxAge は、ジョブが終了してから履歴を保持する期間です。0の場合は期間で制限しません。
+	MaxAge time.Duration
+	// MaxJobs は、タスクごとに保持するジョブの件数です。スケジュール時刻が新しいものから数えます。
+	// 0の場合は件数で制限しません。
+	MaxJobs int
+}
+
+// Validate は、保持ルールの各値が有効な範囲にあるかを検証します。
+// 無効な場合は ErrInvalidArgument をラップしたエラーを返します。
+func (r RetentionRule) Validate() error {
+	switch {
+	case r.MaxAge < 0:
+		return fmt.Errorf("%w: retention max age must not be negative", ErrInvalidArgument)
+	case r.MaxJobs < 0:
+		return fmt.Errorf("%w: retention max jobs must not be negative", ErrInvalidArgument)
+	}
+	return nil
+}
+
+// IsUnlimited は、保持ルールが期間・件数のいずれも制限しないかを返します。
+func (r RetentionRule) IsUnlimited() bool {
+	return r.MaxAge == 0 && r.MaxJobs == 0
+}
+
+// RetentionPolicy は、終了したジョブの履歴をどれだけ保持するかを、失敗したジョブとそれ以外とで分けて表します。
+// タスクに設定する場合、ゼロ値はスケジューラー全体の設定に従うことを意味します。
+type RetentionPolicy struct {
+	// Completed は、成功（Success）またはキャンセル（Cancelled）で終了したジョブの保持ルールです。
+	Completed RetentionRule
+	// Failed は、失敗（Failed）またはタイムアウト（TimedOut）で終了したジョブの保持ルールです。
+	// 調査のために、成功したジョブより長く残すことを想定しています。
+	Failed RetentionRule
+}
+
+// Validate は、保持ポリシーの各ルールが有効であるかを検証します。
+// 無効な場合は ErrInvalidArgument をラップしたエラーを返します。
+func (p RetentionPolicy) Validate() error {
+	if err := p.Completed.Validate(); err != nil {
+		return err
+	}
+	return p.Failed.Validate()
+}
+
+// IsZero は、保持ポリシーがゼロ値であるかを返します。
+func (p RetentionPolicy) IsZero() bool {
+	return p == RetentionPolicy{}
+}
+
+// CompletedJobStatuses は、RetentionPolicy.Completed を適用するジョブの終了状態です。
+var CompletedJobStatuses = []JobStatus{JobStatusSuccess, JobStatusCancelled}
+
+// FailedJobStatuses は、RetentionPolicy.Failed を適用するジョブの終了状態です。
+var FailedJobStatuses = []JobStatus{JobStatusFailed, JobStatusTimedOut}
+
+// JobPruneFilter は、JobHistoryRepository.DeleteFinished で削除する終了済みジョブの条件です。
+// 対象のジョブのうち、FinishedBefore より前に終了したものと、タスクごとに新しい KeepLatest 件に
+// 含まれないものを削除します。
+type JobPruneFilter struct {
+	// TaskID は、対象とするタスクのIDです。空の場合はすべてのタスク（削除済みのタスクを含む）のジョブが対象です。
+	TaskID string
+	// ExcludeTaskIDs は、対象から除くタスクのIDです。TaskID で指定したタスクも、ここに含まれる場合は対象外です。
+	ExcludeTaskIDs []string
+	// Statuses は、対象とするジョブの終了状態です。KeepLatest の件数もこの状態のジョブの中で数えます。
+	Statuses []JobStatus
+	// FinishedBefore は、削除するジョブの終了時刻の上限です。ゼロ値の場合は終了時刻で削除しません。
+	FinishedBefore time.Time
+	// KeepLatest は、タスクごとにスケジュール時刻が新しいものから残すジョブの件数です。0の場合は件数で削除しません。
+	KeepLatest int
+}
+
+// NewJobPruneFilter は、保持ルール rule を statuses のジョブに now の時点で適用する JobPruneFilter を返します。
+// 対象のタスクは、返された値の TaskID・ExcludeTaskIDs で指定します。
+func NewJobPruneFilter(rule RetentionRule, statuses []JobStatus, now time.Time) JobPruneFilter {
+	filter := JobPruneFilter{
+		Statuses:   statuses,
+		KeepLatest: rule.MaxJobs,
+	}
+	if rule.MaxAge > 0 {
+		filter.FinishedBefore = now.Add(-rule.MaxAge)
+	}
+	return filter
+}
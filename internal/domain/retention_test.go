@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetentionPolicy_Validate(t *testing.T) {
+	assert.NoError(t, RetentionPolicy{}.Validate())
+	assert.NoError(t, RetentionPolicy{
+		Completed: RetentionRule{MaxAge: 24 * time.Hour},
+		Failed:    RetentionRule{MaxAge: 7 * 24 * time.Hour, MaxJobs: 100},
+	}.Validate())
+
+	invalid := []RetentionPolicy{
+		{Completed: RetentionRule{MaxAge: -time.Second}},
+		{Completed: RetentionRule{MaxJobs: -1}},
+		{Failed: RetentionRule{MaxAge: -time.Second}},
+		{Failed: RetentionRule{MaxJobs: -1}},
+	}
+	for _, policy := range invalid {
+		assert.ErrorIs(t, policy.Validate(), ErrInvalidArgument, "%+v", policy)
+	}
+}
+
+func TestRetentionPolicy_IsZero(t *testing.T) {
+	assert.True(t, RetentionPolicy{}.IsZero())
+	assert.False(t, RetentionPolicy{Failed: RetentionRule{MaxJobs: 10}}.IsZero())
+}
+
+func TestNewJobPruneFilter(t *testing.T) {
+	now := time.Date(2024, time.January, 8, 12, 0, 0, 0, time.UTC)
+
+	filter := NewJobPruneFilter(RetentionRule{MaxAge: 24 * time.Hour, MaxJobs: 10}, FailedJobStatuses, now)
+	assert.Equal(t, JobPruneFilter{
+		Statuses:       FailedJobStatuses,
+		FinishedBefore: now.Add(-24 * time.Hour),
+		KeepLatest:     10,
+	}, filter)
+
+	// An unlimited age does not set a cut-off time
+	filter = NewJobPruneFilter(RetentionRule{MaxJobs: 5}, CompletedJobStatuses, now)
+	assert.True(t, filter.FinishedBefore.IsZero())
+	assert.Equal(t, 5, filter.KeepLatest)
+}
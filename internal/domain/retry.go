@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// BackoffStrategy は、リトライ間隔の計算方法を表します。
+type BackoffStrategy int
+
+const (
+	// BackoffFixed は、毎回 InitialInterval だけ待機します。
+	BackoffFixed BackoffStrategy = iota
+	// BackoffLinear は、InitialInterval × リトライ回数 だけ待機します。
+	BackoffLinear
+	// BackoffExponential は、InitialInterval × 2^(リトライ回数-1) だけ待機します。
+	BackoffExponential
+)
+
+// MaxRetriesLimit は、RetryPolicy の MaxRetries に指定できる最大値です。
+const MaxRetriesLimit = 100
+
+// RetryPolicy は、ジョブの実行が失敗した際のリトライ方針を表します。
+// ゼロ値はリトライしないことを意味します。
+type RetryPolicy struct {
+	// MaxRetries は、最初の実行に加えて再試行する最大回数です。MaxRetriesLimit を超えることはできません。
+	MaxRetries int
+	// Backoff は、リトライ間隔の計算方法です。
+	Backoff BackoffStrategy
+	// InitialInterval は、1回目のリトライまでの待機時間です。
+	InitialInterval time.Duration
+	// MaxInterval は、待機時間の上限です。0の場合は上限を設けません。
+	MaxInterval time.Duration
+	// Jitter は、待機時間に加えるランダムな揺らぎの割合（0.0〜1.0）です。
+	// 例えば0.2の場合、待機時間は計算値の±20%の範囲でばらつきます。
+	Jitter float64
+}
+
+// Validate は、リトライポリシーの各値が有効な範囲にあるかを検証します。
+// 無効な場合は ErrInvalidArgument をラップしたエラーを返します。
+func (p RetryPolicy) Validate() error {
+	switch {
+	case p.MaxRetries < 0:
+		return fmt.Errorf("%w: max retries must not be negative", ErrInvalidArgument)
+	case p.MaxRetries > MaxRetriesLimit:
+		return fmt.Errorf("%w: max retries must not exceed %d", ErrInvalidArgument, MaxRetriesLimit)
+	case p.Backoff < BackoffFixed || p.Backoff > BackoffExponential:
+		return fmt.Errorf("%w: unknown backoff strategy %d", ErrInvalidArgument, p.Backoff)
+	case p.InitialInterval < 0:
+		return fmt.Errorf("%w: initial interval must not be negative", ErrInvalidArgument)
+	case p.MaxInterval < 0:
+		return fmt.Errorf("%w: max interval must not be negative", ErrInvalidArgument)
+	case p.Jitter < 0 || p.Jitter > 1:
+		return fmt.Errorf("%w: jitter must be between 0 and 1", ErrInvalidArgument)
+	}
+	return nil
+}
+
+// ShouldRetry は、retryCount 回リトライ済みのジョブをさらにリトライすべきかを返します。
+func (p RetryPolicy) ShouldRetry(retryCount int) bool {
+	return retryCount < p.MaxRetries
+}
+
+// NextDelay は、retryCount 回リトライ済みのジョブを次にリトライするまでの待機時間を返します。
+// random は [0.0, 1.0) の乱数を返す関数で、ジッターの計算に使用されます。
+// 計算値が time.Duration で表せる範囲を超える場合は、表せる最大の待機時間を返します。
+func (p RetryPolicy) NextDelay(retryCount int, random func() float64) time.Duration {
+	attempt := float64(retryCount + 1)
+	base := float64(p.InitialInterval)
+
+	var delay float64
+	switch p.Backoff {
+	case BackoffLinear:
+		delay = base * attempt
+	case BackoffExponential:
+		delay = base * math.Pow(2, attempt-1)
+	default:
+		delay = base
+	}
+
+	if p.MaxInterval > 0 && delay > float64(p.MaxInterval) {
+		delay = float64(p.MaxInterval)
+	}
+	// 指数関数的に増えた値は無限大にもなり得るため、ジッターを加える前に有限の上限で丸める
+	delay = math.Min(delay, math.MaxInt64)
+
+	if p.Jitter > 0 && random != nil {
+		delay += delay * p.Jitter * (2*random() - 1)
+	}
+
+	if delay < 0 {
+		return 0
+	}
+	// float64 から time.Duration への変換はオーバーフローすると負の値になり得るため、変換前に上限で丸める
+	if delay >= math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(delay)
+}
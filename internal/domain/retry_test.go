@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_ShouldRetry(t *testing.T) {
+	policy := RetryPolicy{MaxRetries: 2}
+
+	assert.True(t, policy.ShouldRetry(0))
+	assert.True(t, policy.ShouldRetry(1))
+	assert.False(t, policy.ShouldRetry(2))
+
+	// The zero value never retries
+	assert.False(t, RetryPolicy{}.ShouldRetry(0))
+}
+
+func TestRetryPolicy_NextDelay(t *testing.T) {
+	testCases := []struct {
+		name       string
+		policy     RetryPolicy
+		retryCount int
+		expected   time.Duration
+	}{
+		{
+			name:       "fixed",
+			policy:     RetryPolicy{Backoff: BackoffFixed, InitialInterval: time.Second},
+			retryCount: 3,
+			expected:   time.Second,
+		},
+		{
+			name:       "linear first retry",
+			policy:     RetryPolicy{Backoff: BackoffLinear, InitialInterval: time.Second},
+			retryCount: 0,
+			expected:   time.Second,
+		},
+		{
+			name:       "linear third retry",
+			policy:     RetryPolicy{Backoff: BackoffLinear, InitialInterval: time.Second},
+			retryCount: 2,
+			expected:   3 * time.Second,
+		},
+		{
+			name:       "exponential first retry",
+			policy:     RetryPolicy{Backoff: BackoffExponential, InitialInterval: time.Second},
+			retryCount: 0,
+			expected:   time.Second,
+		},
+		{
+			name:       "exponential fourth retry",
+			policy:     RetryPolicy{Backoff: BackoffExponential, InitialInterval: time.Second},
+			retryCount: 3,
+			expected:   8 * time.Second,
+		},
+		{
+			name:       "capped by max interval",
+			policy:     RetryPolicy{Backoff: BackoffExponential, InitialInterval: time.Second, MaxInterval: 5 * time.Second},
+			retryCount: 10,
+			expected:   5 * time.Second,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.policy.NextDelay(tc.retryCount, nil))
+		})
+	}
+}
+
+func TestRetryPolicy_NextDelay_Overflow(t *testing.T) {
+	// Without a max interval, late exponential retries exceed the range of time.Duration
+	policy := RetryPolicy{Backoff: BackoffExponential, InitialInterval: time.Second, Jitter: 0.5}
+	for _, retryCount := range []int{40, 63, 64, MaxRetriesLimit, 1000} {
+		assert.Equal(t, time.Duration(math.MaxInt64), policy.NextDelay(retryCount, nil), "retry count %d", retryCount)
+		assert.Positive(t, policy.NextDelay(retryCount, func() float64 { return 0 }), "retry count %d", retryCount)
+		assert.Positive(t, policy.NextDelay(retryCount, func() float64 { return 0.99 }), "retry count %d", retryCount)
+	}
+}
+
+func TestRetryPolicy_NextDelay_Jitter(t *testing.T) {
+	policy := RetryPolicy{Backoff: BackoffFixed, InitialInterval: 10 * time.Second, Jitter: 0.5}
+
+	assert.Equal(t, 5*time.Second, policy.NextDelay(0, func() float64 { return 0 }))
+	assert.Equal(t, 10*time.Second, policy.NextDelay(0, func() float64 { return 0.5 }))
+	assert.Equal(t, 15*time.Second, policy.NextDelay(0, func() float64 { return 1 }))
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// zonedSchedule は、Cron式をタイムゾーン loc の壁時計時刻（ローカル時刻）で評価するスケジュールです。
+//
+// 夏時間（DST）の切り替えは次のように扱います。
+//   - 時計が進むことで存在しなくなった時刻（例: America/New_York の 02:00〜02:59）に
+//     スケジュールされた実行は、切り替え直後の時刻（03:00）に1回だけ実行されます。
+//     存在しない時間帯に複数の実行時刻がある場合も、まとめて1回になります。
+//   - 時計が戻ることで2回現れる時刻（例: 01:00〜01:59）にスケジュールされた実行は、
+//     1回目（切り替え前）にのみ実行され、2回目には実行されません。
+type zonedSchedule struct {
+	schedule cron.Schedule
+	loc      *time.Location
+}
+
+// Next は、t より後で最初の実行時刻を loc の時刻として返します。
+// 5年以内に実行時刻が見つからない場合はゼロ値を返します。
+func (s *zonedSchedule) Next(t time.Time) time.Time {
+	wall := toWallClock(t, s.loc)
+	for {
+		wall = s.schedule.Next(wall)
+		if wall.IsZero() {
+			return time.Time{}
+		}
+		// 時計が戻った後の2回目の時刻や、存在しない時間帯がまとめられた時刻は
+		// t 以前の実行時刻に対応するため、読み飛ばす
+		if next := fromWallClock(wall, s.loc); next.After(t) {
+			return next
+		}
+	}
+}
+
+// toWallClock は、t の loc における壁時計時刻を、同じ年月日・時分秒を持つUTCの時刻として返します。
+// UTCには夏時間がないため、Cron式を壁時計時刻のまま評価できます。
+func toWallClock(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// fromWallClock は、toWallClock で表した壁時計時刻を loc の時刻に戻します。
+// 壁時計時刻が2回現れる場合は早い方を、存在しない場合は時計が進んだ直後の時刻を返します。
+func fromWallClock(wall time.Time, loc *time.Location) time.Time {
+	t := time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), loc)
+	start, end := t.ZoneBounds()
+
+	if toWallClock(t, loc).Equal(wall) {
+		// 直前のオフセットでも同じ壁時計時刻になる場合は、時計が戻る前の早い方を選ぶ
+		if !start.IsZero() {
+			_, prevOffset := start.Add(-time.Nanosecond).Zone()
+			earlier := wall.Add(-time.Duration(prevOffset) * time.Second).In(loc)
+			if earlier.Before(t) && toWallClock(earlier, loc).Equal(wall) {
+				return earlier
+			}
+		}
+		return t
+	}
+
+	// 存在しない時刻: 時計が進んだ瞬間（t を含むゾーン期間の境界）を返す
+	if toWallClock(t, loc).After(wall) {
+		return start
+	}
+	return end.In(loc)
+}
@@ -0,0 +1,83 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourname/go-dist-scheduler/pkg/webhook"
+)
+
+// SigningPolicy は、送信するHTTPリクエストに HMAC-SHA256 の署名を付与する設定です。
+// 署名はタイムスタンプとボディから計算します。形式と受信側での検証方法は pkg/webhook パッケージを参照してください。
+// ゼロ値は署名しないことを意味します。
+type SigningPolicy struct {
+	// Secrets は、署名に使う秘密鍵です。鍵のローテーション中は新旧の鍵を指定し、それぞれの鍵で計算した署名をすべて送信します。
+	Secrets []string
+	// SignatureHeader は、署名を送信するヘッダーの名前です。空の場合は webhook.DefaultSignatureHeader です。
+	SignatureHeader string
+	// TimestampHeader は、署名した時刻を送信するヘッダーの名前です。空の場合は webhook.DefaultTimestampHeader です。
+	TimestampHeader string
+}
+
+// Enabled は、リクエストに署名するかを返します。
+func (p SigningPolicy) Enabled() bool {
+	return len(p.Secrets) > 0
+}
+
+// SignatureHeaderName は、署名を送信するヘッダーの名前を、デフォルトを補って返します。
+func (p SigningPolicy) SignatureHeaderName() string {
+	if p.SignatureHeader == "" {
+		return webhook.DefaultSignatureHeader
+	}
+	return p.SignatureHeader
+}
+
+// TimestampHeaderName は、署名した時刻を送信するヘッダーの名前を、デフォルトを補って返します。
+func (p SigningPolicy) TimestampHeaderName() string {
+	if p.TimestampHeader == "" {
+		return webhook.DefaultTimestampHeader
+	}
+	return p.TimestampHeader
+}
+
+// Validate は、署名の設定が有効であるかを検証します。
+// 無効な場合は ErrInvalidArgument をラップしたエラーを返します。
+func (p SigningPolicy) Validate() error {
+	if !p.Enabled() {
+		if p.SignatureHeader != "" || p.TimestampHeader != "" {
+			return fmt.Errorf("%w: signing headers require at least one secret", ErrInvalidArgument)
+		}
+		return nil
+	}
+	for _, secret := range p.Secrets {
+		if secret == "" {
+			return fmt.Errorf("%w: signing secret must not be empty", ErrInvalidArgument)
+		}
+	}
+	signatureHeader, timestampHeader := p.SignatureHeaderName(), p.TimestampHeaderName()
+	for _, name := range []string{signatureHeader, timestampHeader} {
+		if !isHeaderName(name) {
+			return fmt.Errorf("%w: invalid signing header name %q", ErrInvalidArgument, name)
+		}
+	}
+	if strings.EqualFold(signatureHeader, timestampHeader) {
+		return fmt.Errorf("%w: signature and timestamp headers must differ", ErrInvalidArgument)
+	}
+	return nil
+}
+
+// isHeaderName は、name がHTTPヘッダーの名前として使えるトークン（RFC 9110）であるかを返します。
+func isHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", c):
+		default:
+			return false
+		}
+	}
+	return true
+}
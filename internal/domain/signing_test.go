@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourname/go-dist-scheduler/pkg/webhook"
+)
+
+func TestSigningPolicy_Validate(t *testing.T) {
+	assert.NoError(t, SigningPolicy{}.Validate())
+	assert.NoError(t, SigningPolicy{Secrets: []string{"old", "new"}}.Validate())
+	assert.NoError(t, SigningPolicy{Secrets: []string{"secret"}, SignatureHeader: "X-Signature", TimestampHeader: "X-Timestamp"}.Validate())
+
+	invalid := []SigningPolicy{
+		{SignatureHeader: "X-Signature"},
+		{Secrets: []string{"secret", ""}},
+		{Secrets: []string{"secret"}, SignatureHeader: "X Signature"},
+		{Secrets: []string{"secret"}, TimestampHeader: "X-Timestamp:"},
+		{Secrets: []string{"secret"}, SignatureHeader: "x-scheduler-timestamp"},
+	}
+	for _, policy := range invalid {
+		assert.ErrorIs(t, policy.Validate(), ErrInvalidArgument, "%+v", policy)
+	}
+}
+
+func TestSigningPolicy_HeaderNames(t *testing.T) {
+	policy := SigningPolicy{Secrets: []string{"secret"}}
+	assert.True(t, policy.Enabled())
+	assert.Equal(t, webhook.DefaultSignatureHeader, policy.SignatureHeaderName())
+	assert.Equal(t, webhook.DefaultTimestampHeader, policy.TimestampHeaderName())
+
+	policy.SignatureHeader = "X-Signature"
+	policy.TimestampHeader = "X-Timestamp"
+	assert.Equal(t, "X-Signature", policy.SignatureHeaderName())
+	assert.Equal(t, "X-Timestamp", policy.TimestampHeaderName())
+
+	assert.False(t, SigningPolicy{}.Enabled())
+}
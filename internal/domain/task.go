@@ -1,6 +1,9 @@
 package domain
 
 import (
+	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/robfig/cron/v3"
@@ -13,32 +16,215 @@ const (
 	TaskStatusPaused
 )
 
+// MaxStatusHistory は、タスクに保持するステータス変更履歴の最大件数です。超えた分は古いものから削除されます。
+const MaxStatusHistory = 50
+
+// TaskStatusChange は、タスクの一時停止・再開の履歴の1件です。
+type TaskStatusChange struct {
+	// Status は、変更後のステータスです。
+	Status TaskStatus
+	// Actor は、ステータスを変更した人やシステムの識別子です。
+	Actor string
+	// Reason は、ステータスを変更した理由です。空の場合もあります。
+	Reason    string
+	ChangedAt time.Time
+}
+
+// ResumeMode は、一時停止中のタスクを再開する際に、一時停止中に到来した実行時刻をどう扱うかを表します。
+// ゼロ値は無効で、再開する側が明示的に選択する必要があります。
+type ResumeMode int
+
+const (
+	// ResumeFromNow は、一時停止中の実行時刻を読み飛ばし、再開した時刻より後の実行時刻からスケジュールします。
+	ResumeFromNow ResumeMode = iota + 1
+	// ResumeBackfill は、一時停止中の実行時刻を、次回のチェックでタスクの MisfirePolicy に従ってエンキューします。
+	ResumeBackfill
+)
+
+// Validate は、再開モードが有効であるかを検証します。
+func (m ResumeMode) Validate() error {
+	if m != ResumeFromNow && m != ResumeBackfill {
+		return fmt.Errorf("%w: unknown resume mode %d", ErrInvalidArgument, m)
+	}
+	return nil
+}
+
 type HTTPRequestInfo struct {
 	URL     string
 	Method  string
 	Headers map[string]string
 	Body    []byte
+	// Signing は、リクエストに HMAC-SHA256 の署名を付与する設定です。ゼロ値の場合は署名しません。
+	Signing SigningPolicy
 }
 
 type Task struct {
 	ID             string
 	Name           string
 	CronExpression string
-	Payload        HTTPRequestInfo
-	Status         TaskStatus
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
-	LastCheckedAt  time.Time
+	// Timezone は、CronExpression を評価するタイムゾーンのIANA名（例: "Asia/Tokyo"）です。空の場合はUTCです。
+	Timezone      string
+	Payload       HTTPRequestInfo
+	Status        TaskStatus
+	RetryPolicy   RetryPolicy
+	MisfirePolicy MisfirePolicy
+	// ConcurrencyPolicy は、前回の実行が終わっていない場合に新しい実行をどう扱うかです。
+	ConcurrencyPolicy ConcurrencyPolicy
+	// Timeout は、1回の実行（HTTPリクエストの送信からレスポンスボディの読み込みまで）の制限時間です。0の場合は制限しません。
+	Timeout time.Duration
+	// RetentionPolicy は、終了したジョブの履歴を保持する期間と件数です。ゼロ値の場合はスケジューラー全体の設定に従います。
+	RetentionPolicy RetentionPolicy
+	// StatusHistory は、タスクの一時停止・再開の履歴を古い順に最大 MaxStatusHistory 件保持します。
+	StatusHistory []TaskStatusChange
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	LastCheckedAt time.Time
+	// Version は、楽観的ロックのためのバージョンです。TaskRepository.Save で保存されるたびに1つ進みます。
+	Version int64
 }
 
 // cronParser は、標準的な5フィールド（分・時・日・月・曜日）のCron式を解析するパーサーです。
 // このパーサーはパッケージレベルで一度だけ生成され、複数のgoroutineから安全に利用できます。
 var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
 
+// ValidateCronExpression は、Cron式が cronParser で解析できるかを検証します。
+// 解析できない場合は ErrInvalidArgument をラップしたエラーを返します。
+// タイムゾーンは Task.Timezone で指定するため、"CRON_TZ=" や "TZ=" の接頭辞は受け付けません。
+func ValidateCronExpression(expr string) error {
+	if strings.HasPrefix(expr, "CRON_TZ=") || strings.HasPrefix(expr, "TZ=") {
+		return fmt.Errorf("%w: invalid cron expression %q: use the timezone field instead of a TZ prefix", ErrInvalidArgument, expr)
+	}
+	if _, err := cronParser.Parse(expr); err != nil {
+		return fmt.Errorf("%w: invalid cron expression %q: %v", ErrInvalidArgument, expr, err)
+	}
+	return nil
+}
+
+// ValidateTimezone は、タイムゾーン名が有効なIANA名であるかを検証します。空文字列はUTCとして有効です。
+func ValidateTimezone(name string) error {
+	if _, err := loadLocation(name); err != nil {
+		return fmt.Errorf("%w: invalid timezone %q", ErrInvalidArgument, name)
+	}
+	return nil
+}
+
+// loadLocation は、タイムゾーン名に対応する *time.Location を返します。空文字列はUTCです。
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	// "Local" はプロセスの環境に依存するため受け付けない
+	if name == "Local" {
+		return nil, fmt.Errorf("unknown time zone %s", name)
+	}
+	return time.LoadLocation(name)
+}
+
+// Validate は、タスクの各値が有効であるかを検証します。
+// 無効な場合は ErrInvalidArgument をラップしたエラーを返します。
+func (t *Task) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidArgument)
+	}
+	if err := ValidateCronExpression(t.CronExpression); err != nil {
+		return err
+	}
+	if err := ValidateTimezone(t.Timezone); err != nil {
+		return err
+	}
+	if err := t.Payload.Validate(); err != nil {
+		return err
+	}
+	if t.Status != TaskStatusActive && t.Status != TaskStatusPaused {
+		return fmt.Errorf("%w: unknown task status %d", ErrInvalidArgument, t.Status)
+	}
+	if err := t.RetryPolicy.Validate(); err != nil {
+		return err
+	}
+	if err := t.MisfirePolicy.Validate(); err != nil {
+		return err
+	}
+	if t.Timeout < 0 {
+		return fmt.Errorf("%w: timeout must not be negative", ErrInvalidArgument)
+	}
+	if err := t.RetentionPolicy.Validate(); err != nil {
+		return err
+	}
+	return t.ConcurrencyPolicy.Validate()
+}
+
+// supportedMethods は、タスクのHTTPリクエストに指定できるメソッドの一覧です。
+var supportedMethods = map[string]bool{
+	"GET":     true,
+	"HEAD":    true,
+	"POST":    true,
+	"PUT":     true,
+	"PATCH":   true,
+	"DELETE":  true,
+	"OPTIONS": true,
+}
+
+// Validate は、HTTPリクエストの送信先URL・メソッド・署名の設定が有効であるかを検証します。
+// Method が空の場合は GET として扱われるため有効とみなします。
+func (i HTTPRequestInfo) Validate() error {
+	u, err := url.Parse(i.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidArgument)
+	}
+	if i.Method != "" && !supportedMethods[i.Method] {
+		return fmt.Errorf("%w: unsupported method %q", ErrInvalidArgument, i.Method)
+	}
+	return i.Signing.Validate()
+}
+
+// Pause は、タスクを一時停止し、新しいジョブがエンキューされないようにします。
+// actor と reason はステータス変更履歴に記録されます。
+func (t *Task) Pause(actor, reason string, now time.Time) {
+	t.Status = TaskStatusPaused
+	t.recordStatusChange(actor, reason, now)
+}
+
+// Resume は、一時停止中のタスクを再開します。
+// ResumeFromNow の場合は LastCheckedAt を now にして、一時停止中の実行時刻がエンキューされないようにします。
+// ResumeBackfill の場合は LastCheckedAt を変更しないため、一時停止中の実行時刻は次回のチェックでミスファイアとして扱われます。
+// actor と reason はステータス変更履歴に記録されます。
+func (t *Task) Resume(mode ResumeMode, actor, reason string, now time.Time) {
+	t.Status = TaskStatusActive
+	if mode == ResumeFromNow {
+		t.LastCheckedAt = now
+	}
+	t.recordStatusChange(actor, reason, now)
+}
+
+// recordStatusChange は、現在のステータスへの変更を履歴に追加し、MaxStatusHistory を超えた古い履歴を削除します。
+func (t *Task) recordStatusChange(actor, reason string, now time.Time) {
+	t.StatusHistory = append(t.StatusHistory, TaskStatusChange{
+		Status:    t.Status,
+		Actor:     actor,
+		Reason:    reason,
+		ChangedAt: now,
+	})
+	if n := len(t.StatusHistory); n > MaxStatusHistory {
+		t.StatusHistory = append([]TaskStatusChange(nil), t.StatusHistory[n-MaxStatusHistory:]...)
+	}
+	t.UpdatedAt = now
+}
+
+// getSchedule は、CronExpression を Timezone の壁時計時刻で評価するスケジュールを返します。
+// 夏時間の切り替えの扱いは zonedSchedule を参照してください。
 func (t *Task) getSchedule() (cron.Schedule, error) {
-	return cronParser.Parse(t.CronExpression)
+	loc, err := loadLocation(t.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	schedule, err := cronParser.Parse(t.CronExpression)
+	if err != nil {
+		return nil, err
+	}
+	return &zonedSchedule{schedule: schedule, loc: loc}, nil
 }
 
+// NextRunTime は、now より後で最初の実行時刻を、タスクのタイムゾーンの時刻として返します。
 func (t *Task) NextRunTime(now time.Time) (time.Time, error) {
 	schedule, err := t.getSchedule()
 	if err != nil {
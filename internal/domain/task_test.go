@@ -3,6 +3,8 @@ package domain
 import (
 	"testing"
 	"time"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestTask_NextRunTime(t *testing.T) {
@@ -63,3 +65,259 @@ func TestTask_NextRunTime(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateCronExpression(t *testing.T) {
+	assert.NoError(t, ValidateCronExpression("*/5 * * * *"))
+
+	err := ValidateCronExpression("invalid")
+	assert.ErrorIs(t, err, ErrInvalidArgument)
+	err = ValidateCronExpression("0 0 * * * *")
+	assert.ErrorIs(t, err, ErrInvalidArgument, "six-field expressions are not supported")
+}
+
+func TestTask_Validate(t *testing.T) {
+	validTask := func() *Task {
+		return &Task{
+			Name:           "task",
+			CronExpression: "* * * * *",
+			Payload:        HTTPRequestInfo{URL: "https://example.com/webhook", Method: "POST"},
+			Status:         TaskStatusActive,
+		}
+	}
+	assert.NoError(t, validTask().Validate())
+
+	testCases := []struct {
+		name   string
+		modify func(task *Task)
+	}{
+		{name: "empty name", modify: func(task *Task) { task.Name = " " }},
+		{name: "invalid cron expression", modify: func(task *Task) { task.CronExpression = "every minute" }},
+		{name: "relative url", modify: func(task *Task) { task.Payload.URL = "/webhook" }},
+		{name: "unsupported scheme", modify: func(task *Task) { task.Payload.URL = "ftp://example.com" }},
+		{name: "unsupported method", modify: func(task *Task) { task.Payload.Method = "FETCH" }},
+		{name: "unknown status", modify: func(task *Task) { task.Status = TaskStatus(99) }},
+		{name: "negative max retries", modify: func(task *Task) { task.RetryPolicy.MaxRetries = -1 }},
+		{name: "too many max retries", modify: func(task *Task) { task.RetryPolicy.MaxRetries = MaxRetriesLimit + 1 }},
+		{name: "jitter out of range", modify: func(task *Task) { task.RetryPolicy.Jitter = 1.5 }},
+		{name: "invalid misfire policy", modify: func(task *Task) { task.MisfirePolicy.Strategy = MisfireFireLimited }},
+		{name: "unknown concurrency policy", modify: func(task *Task) { task.ConcurrencyPolicy = ConcurrencyPolicy(99) }},
+		{name: "negative timeout", modify: func(task *Task) { task.Timeout = -time.Second }},
+		{name: "invalid retention policy", modify: func(task *Task) { task.RetentionPolicy.Failed.MaxAge = -time.Hour }},
+		{name: "invalid signing policy", modify: func(task *Task) { task.Payload.Signing.Secrets = []string{""} }},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			task := validTask()
+			tc.modify(task)
+			assert.ErrorIs(t, task.Validate(), ErrInvalidArgument)
+		})
+	}
+}
+
+func TestTask_PauseAndResume(t *testing.T) {
+	lastChecked := time.Date(2024, time.April, 1, 9, 0, 0, 0, time.UTC)
+	pausedAt := lastChecked.Add(time.Minute)
+	resumedAt := pausedAt.Add(time.Hour)
+
+	t.Run("resume from now", func(t *testing.T) {
+		task := &Task{Status: TaskStatusActive, LastCheckedAt: lastChecked}
+
+		task.Pause("alice", "incident", pausedAt)
+		assert.Equal(t, TaskStatusPaused, task.Status)
+		assert.Equal(t, pausedAt, task.UpdatedAt)
+
+		task.Resume(ResumeFromNow, "bob", "", resumedAt)
+		assert.Equal(t, TaskStatusActive, task.Status)
+		assert.Equal(t, resumedAt, task.LastCheckedAt, "runs missed while paused must be skipped")
+		assert.Equal(t, []TaskStatusChange{
+			{Status: TaskStatusPaused, Actor: "alice", Reason: "incident", ChangedAt: pausedAt},
+			{Status: TaskStatusActive, Actor: "bob", ChangedAt: resumedAt},
+		}, task.StatusHistory)
+	})
+
+	t.Run("resume with backfill", func(t *testing.T) {
+		task := &Task{Status: TaskStatusActive, LastCheckedAt: lastChecked}
+
+		task.Pause("alice", "", pausedAt)
+		task.Resume(ResumeBackfill, "alice", "", resumedAt)
+		assert.Equal(t, TaskStatusActive, task.Status)
+		assert.Equal(t, lastChecked, task.LastCheckedAt, "runs missed while paused must remain due")
+	})
+
+	t.Run("history is bounded", func(t *testing.T) {
+		task := &Task{Status: TaskStatusActive}
+		for i := 0; i < MaxStatusHistory; i++ {
+			task.Pause("alice", "", pausedAt.Add(time.Duration(i)*time.Second))
+			task.Resume(ResumeFromNow, "alice", "", resumedAt.Add(time.Duration(i)*time.Second))
+		}
+		assert.Len(t, task.StatusHistory, MaxStatusHistory)
+		assert.Equal(t, TaskStatusActive, task.StatusHistory[MaxStatusHistory-1].Status)
+	})
+}
+
+func TestResumeMode_Validate(t *testing.T) {
+	assert.NoError(t, ResumeFromNow.Validate())
+	assert.NoError(t, ResumeBackfill.Validate())
+	assert.ErrorIs(t, ResumeMode(0).Validate(), ErrInvalidArgument)
+}
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("failed to load location %s: %v", name, err)
+	}
+	return loc
+}
+
+func TestTask_NextRunTime_Timezone(t *testing.T) {
+	tokyo := mustLoadLocation(t, "Asia/Tokyo")
+	newYork := mustLoadLocation(t, "America/New_York")
+	now := time.Date(2024, time.April, 1, 1, 0, 0, 0, time.UTC) // 10:00 JST, 21:00 EDT (Mar 31)
+
+	testCases := []struct {
+		name     string
+		timezone string
+		now      time.Time
+		expected time.Time
+	}{
+		{
+			name:     "09:00 in Tokyo",
+			timezone: "Asia/Tokyo",
+			now:      now,
+			expected: time.Date(2024, time.April, 2, 9, 0, 0, 0, tokyo),
+		},
+		{
+			name:     "09:00 in New York",
+			timezone: "America/New_York",
+			now:      now,
+			expected: time.Date(2024, time.April, 1, 9, 0, 0, 0, newYork),
+		},
+		{
+			name:     "empty timezone is UTC",
+			timezone: "",
+			now:      now,
+			expected: time.Date(2024, time.April, 1, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "location of now is ignored",
+			timezone: "",
+			now:      now.In(tokyo),
+			expected: time.Date(2024, time.April, 1, 9, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			task := &Task{CronExpression: "0 9 * * *", Timezone: tc.timezone}
+			next, err := task.NextRunTime(tc.now)
+			assert.NoError(t, err)
+			assert.True(t, tc.expected.Equal(next), "expected %v, got %v", tc.expected, next)
+			if tc.timezone != "" {
+				assert.Equal(t, tc.timezone, next.Location().String(), "run time is expressed in the task's timezone")
+			}
+		})
+	}
+}
+
+func TestTask_NextRunTime_InvalidTimezone(t *testing.T) {
+	task := &Task{CronExpression: "0 9 * * *", Timezone: "Mars/Olympus_Mons"}
+	_, err := task.NextRunTime(time.Now())
+	assert.Error(t, err)
+	assert.ErrorIs(t, task.Validate(), ErrInvalidArgument)
+	assert.ErrorIs(t, ValidateTimezone("Local"), ErrInvalidArgument)
+	assert.NoError(t, ValidateTimezone(""))
+	assert.ErrorIs(t, ValidateCronExpression("CRON_TZ=Asia/Tokyo 0 9 * * *"), ErrInvalidArgument)
+}
+
+// 2024-03-10 02:00 EST, clocks in New York jump forward to 03:00 EDT.
+func TestTask_GetDueRunTimes_DSTSkippedHour(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+	from := time.Date(2024, time.March, 10, 0, 0, 0, 0, newYork)
+	to := time.Date(2024, time.March, 11, 0, 0, 0, 0, newYork)
+	jumpedAt := time.Date(2024, time.March, 10, 7, 0, 0, 0, time.UTC) // 03:00 EDT
+
+	testCases := []struct {
+		name           string
+		cronExpression string
+		expected       []time.Time
+	}{
+		{
+			name:           "run time in the skipped hour fires once right after the jump",
+			cronExpression: "30 2 * * *",
+			expected:       []time.Time{jumpedAt},
+		},
+		{
+			name:           "several run times in the skipped hour fire only once",
+			cronExpression: "*/15 2 * * *",
+			expected:       []time.Time{jumpedAt},
+		},
+		{
+			name:           "run time at the jump is not duplicated",
+			cronExpression: "0 2,3 * * *",
+			expected:       []time.Time{jumpedAt},
+		},
+		{
+			name:           "run times around the skipped hour are unaffected",
+			cronExpression: "30 1,3 * * *",
+			expected: []time.Time{
+				time.Date(2024, time.March, 10, 1, 30, 0, 0, newYork),
+				time.Date(2024, time.March, 10, 3, 30, 0, 0, newYork),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			task := &Task{CronExpression: tc.cronExpression, Timezone: "America/New_York"}
+			runTimes, err := task.GetDueRunTimes(from, to)
+			assert.NoError(t, err)
+			assertSameInstants(t, tc.expected, runTimes)
+		})
+	}
+
+	// The next day is scheduled normally again
+	task := &Task{CronExpression: "30 2 * * *", Timezone: "America/New_York"}
+	next, err := task.NextRunTime(jumpedAt)
+	assert.NoError(t, err)
+	assert.True(t, time.Date(2024, time.March, 11, 2, 30, 0, 0, newYork).Equal(next), "got %v", next)
+}
+
+// 2024-11-03 02:00 EDT, clocks in New York fall back to 01:00 EST, so 01:00-01:59 occurs twice.
+func TestTask_GetDueRunTimes_DSTRepeatedHour(t *testing.T) {
+	newYork := mustLoadLocation(t, "America/New_York")
+	from := time.Date(2024, time.November, 3, 0, 0, 0, 0, newYork)
+	to := time.Date(2024, time.November, 4, 0, 0, 0, 0, newYork)
+	firstPass := time.Date(2024, time.November, 3, 5, 30, 0, 0, time.UTC) // 01:30 EDT
+
+	task := &Task{CronExpression: "30 1 * * *", Timezone: "America/New_York"}
+	runTimes, err := task.GetDueRunTimes(from, to)
+	assert.NoError(t, err)
+	assertSameInstants(t, []time.Time{firstPass}, runTimes)
+
+	// Checking again from within the second pass does not fire the repeated run time
+	secondPass := time.Date(2024, time.November, 3, 6, 0, 0, 0, time.UTC) // 01:00 EST
+	runTimes, err = task.GetDueRunTimes(secondPass, to)
+	assert.NoError(t, err)
+	assert.Empty(t, runTimes)
+
+	// Every wall-clock minute of the repeated hour fires exactly once
+	task = &Task{CronExpression: "* 1 * * *", Timezone: "America/New_York"}
+	runTimes, err = task.GetDueRunTimes(from, to)
+	assert.NoError(t, err)
+	assert.Len(t, runTimes, 60)
+	for _, runTime := range runTimes {
+		_, offset := runTime.Zone()
+		assert.Equal(t, -4*60*60, offset, "%v should be in EDT", runTime)
+	}
+}
+
+func assertSameInstants(t *testing.T, expected, actual []time.Time) {
+	t.Helper()
+	if !assert.Len(t, actual, len(expected)) {
+		return
+	}
+	for i := range expected {
+		assert.True(t, expected[i].Equal(actual[i]), "run time %d: expected %v, got %v", i, expected[i], actual[i])
+	}
+}
@@ -2,7 +2,9 @@ package memory
 
 import (
 	"context"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/yourname/go-dist-scheduler/internal/domain"
 )
@@ -12,38 +14,80 @@ type InMemoryJobRepository struct {
 	mu    sync.Mutex
 	queue []string
 	jobs  map[string]*domain.Job
+	// scheduled indexes job IDs by task ID and scheduled time to reject duplicates.
+	scheduled map[string]string
+	// results holds the execution results of each job in the order they were saved.
+	results map[string][]*domain.JobResult
+	// visibilityTimeout is the lease duration granted on Dequeue and ExtendLease.
+	visibilityTimeout time.Duration
 }
 
-func NewInMemoryJobRepository() *InMemoryJobRepository {
-	return &InMemoryJobRepository{
-		queue: make([]string, 0),
-		jobs:  make(map[string]*domain.Job),
+// InMemoryJobRepositoryOption configures an InMemoryJobRepository.
+type InMemoryJobRepositoryOption func(*InMemoryJobRepository)
+
+// WithVisibilityTimeout sets the lease duration granted on Dequeue and ExtendLease.
+func WithVisibilityTimeout(d time.Duration) InMemoryJobRepositoryOption {
+	return func(r *InMemoryJobRepository) {
+		r.visibilityTimeout = d
+	}
+}
+
+func NewInMemoryJobRepository(opts ...InMemoryJobRepositoryOption) *InMemoryJobRepository {
+	r := &InMemoryJobRepository{
+		queue:             make([]string, 0),
+		jobs:              make(map[string]*domain.Job),
+		scheduled:         make(map[string]string),
+		results:           make(map[string][]*domain.JobResult),
+		visibilityTimeout: domain.DefaultVisibilityTimeout,
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
+// Enqueue adds a job to the queue. It returns domain.ErrConstraintViolation if a
+// job with the same ID, or with the same task ID and scheduled time, already exists.
 func (r *InMemoryJobRepository) Enqueue(ctx context.Context, job *domain.Job) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	key := scheduleKey(job)
+	if _, ok := r.jobs[job.ID]; ok {
+		return domain.ErrConstraintViolation
+	}
+	if _, ok := r.scheduled[key]; ok {
+		return domain.ErrConstraintViolation
+	}
 	r.jobs[job.ID] = copyJob(job)
+	r.scheduled[key] = job.ID
 	r.queue = append(r.queue, job.ID)
 	return nil
 }
 
+// Dequeue removes the oldest pending job in the queue whose AvailableAt has passed,
+// marks it as Running and leases it for the visibility timeout, as the PostgreSQL
+// repository does.
 func (r *InMemoryJobRepository) Dequeue(ctx context.Context) (*domain.Job, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if len(r.queue) == 0 {
-		return nil, nil
+	now := time.Now()
+	for i, jobID := range r.queue {
+		job := r.jobs[jobID]
+		if job.Status != domain.JobStatusPending || !job.IsAvailable(now) {
+			continue
+		}
+		r.queue = append(r.queue[:i:i], r.queue[i+1:]...)
+		job.MarkAsRunning()
+		job.LeaseExpiresAt = now.Add(r.visibilityTimeout)
+		return copyJob(job), nil
 	}
-	jobID := r.queue[0]
-	r.queue = r.queue[1:]
-	return copyJob(r.jobs[jobID]), nil
+	return nil, nil
 }
 
 func (r *InMemoryJobRepository) UpdateStatus(ctx context.Context, jobID string, status domain.JobStatus) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
-	if job, ok := r.jobs[jobID]; ok {
+	if job, ok := r.jobs[jobID]; ok && job.Status != domain.JobStatusCancelled {
 		switch status {
 		case domain.JobStatusRunning:
 			job.MarkAsRunning()
@@ -51,16 +95,277 @@ func (r *InMemoryJobRepository) UpdateStatus(ctx context.Context, jobID string,
 			job.MarkAsSuccess()
 		case domain.JobStatusFailed:
 			job.MarkAsFailed()
+		case domain.JobStatusTimedOut:
+			job.MarkAsTimedOut()
+		}
+	}
+	return nil
+}
+
+// Requeue stores the updated job and puts it back to the end of the queue.
+// A job that is still waiting in the queue is moved rather than duplicated.
+// Cancelled jobs are left untouched.
+func (r *InMemoryJobRepository) Requeue(ctx context.Context, job *domain.Job) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if current, ok := r.jobs[job.ID]; ok && current.Status == domain.JobStatusCancelled {
+		return nil
+	}
+	r.jobs[job.ID] = copyJob(job)
+	r.removeFromQueue(job.ID)
+	r.queue = append(r.queue, job.ID)
+	return nil
+}
+
+// FindByID returns a job by ID, or nil if it does not exist.
+func (r *InMemoryJobRepository) FindByID(ctx context.Context, jobID string) (*domain.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return copyJob(r.jobs[jobID]), nil
+}
+
+// FindByTaskID returns up to limit jobs of a task, most recently scheduled first.
+func (r *InMemoryJobRepository) FindByTaskID(ctx context.Context, taskID string, limit int) ([]*domain.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	jobs := make([]*domain.Job, 0)
+	for _, job := range r.jobs {
+		if job.TaskID == taskID {
+			jobs = append(jobs, copyJob(job))
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].ScheduledAt.After(jobs[j].ScheduledAt)
+	})
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return jobs, nil
+}
+
+// FindActiveByTaskID returns the pending or running jobs of a task, earliest scheduled first.
+func (r *InMemoryJobRepository) FindActiveByTaskID(ctx context.Context, taskID string) ([]*domain.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	jobs := make([]*domain.Job, 0)
+	for _, job := range r.jobs {
+		if job.TaskID == taskID && !job.IsFinished() {
+			jobs = append(jobs, copyJob(job))
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].ScheduledAt.Before(jobs[j].ScheduledAt)
+	})
+	return jobs, nil
+}
+
+// CountPending returns the number of jobs waiting in the queue, including jobs waiting for a retry.
+func (r *InMemoryJobRepository) CountPending(ctx context.Context) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.queue), nil
+}
+
+// Cancel marks an unfinished job as cancelled and removes it from the queue.
+func (r *InMemoryJobRepository) Cancel(ctx context.Context, jobID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if job, ok := r.jobs[jobID]; ok && !job.IsFinished() {
+		job.MarkAsCancelled()
+		r.removeFromQueue(jobID)
+	}
+	return nil
+}
+
+// ExtendLease extends the lease of an unfinished leased job by the visibility timeout.
+func (r *InMemoryJobRepository) ExtendLease(ctx context.Context, jobID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if job, ok := r.jobs[jobID]; ok && !job.IsFinished() && !job.LeaseExpiresAt.IsZero() {
+		job.LeaseExpiresAt = time.Now().Add(r.visibilityTimeout)
+	}
+	return nil
+}
+
+// FindExpiredLeases returns up to limit unfinished jobs whose lease expired before now,
+// ordered by lease expiration.
+func (r *InMemoryJobRepository) FindExpiredLeases(ctx context.Context, now time.Time, limit int) ([]*domain.Job, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var expired []*domain.Job
+	for _, job := range r.jobs {
+		if job.IsLeaseExpired(now) {
+			expired = append(expired, copyJob(job))
 		}
 	}
+	sort.Slice(expired, func(i, j int) bool {
+		return expired[i].LeaseExpiresAt.Before(expired[j].LeaseExpiresAt)
+	})
+	if len(expired) > limit {
+		expired = expired[:limit]
+	}
+	return expired, nil
+}
+
+// ReclaimExpired stores a job updated by the reaper if the stored job is still
+// running with a lease that expired before now. A job returned to Pending is
+// put back to the end of the queue.
+func (r *InMemoryJobRepository) ReclaimExpired(ctx context.Context, job *domain.Job, now time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	current, ok := r.jobs[job.ID]
+	if !ok || current.Status != domain.JobStatusRunning || !current.IsLeaseExpired(now) {
+		return false, nil
+	}
+	r.jobs[job.ID] = copyJob(job)
+	r.removeFromQueue(job.ID)
+	if job.Status == domain.JobStatusPending {
+		r.queue = append(r.queue, job.ID)
+	}
+	return true, nil
+}
+
+// SaveResult stores the execution result of a job attempt.
+func (r *InMemoryJobRepository) SaveResult(ctx context.Context, result *domain.JobResult) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results[result.JobID] = append(r.results[result.JobID], copyJobResult(result))
 	return nil
 }
 
+// FindResultsByJobID returns the execution results of a job ordered by attempt.
+func (r *InMemoryJobRepository) FindResultsByJobID(ctx context.Context, jobID string) ([]*domain.JobResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	results := make([]*domain.JobResult, 0, len(r.results[jobID]))
+	for _, result := range r.results[jobID] {
+		results = append(results, copyJobResult(result))
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Attempt < results[j].Attempt
+	})
+	return results, nil
+}
+
+// FindRecentResultsByTaskID returns up to limit execution results of a task,
+// most recently finished first.
+func (r *InMemoryJobRepository) FindRecentResultsByTaskID(ctx context.Context, taskID string, limit int) ([]*domain.JobResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var results []*domain.JobResult
+	for _, jobResults := range r.results {
+		for _, result := range jobResults {
+			if result.TaskID == taskID {
+				results = append(results, copyJobResult(result))
+			}
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].FinishedAt.After(results[j].FinishedAt)
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// DeleteFinished deletes up to limit finished jobs that match filter, together
+// with their results, and returns the number of deleted jobs.
+func (r *InMemoryJobRepository) DeleteFinished(ctx context.Context, filter domain.JobPruneFilter, limit int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	statuses := make(map[domain.JobStatus]bool, len(filter.Statuses))
+	for _, status := range filter.Statuses {
+		statuses[status] = true
+	}
+	excluded := make(map[string]bool, len(filter.ExcludeTaskIDs))
+	for _, taskID := range filter.ExcludeTaskIDs {
+		excluded[taskID] = true
+	}
+
+	byTask := make(map[string][]*domain.Job)
+	for _, job := range r.jobs {
+		if !job.IsFinished() || !statuses[job.Status] {
+			continue
+		}
+		if (filter.TaskID != "" && job.TaskID != filter.TaskID) || excluded[job.TaskID] {
+			continue
+		}
+		byTask[job.TaskID] = append(byTask[job.TaskID], job)
+	}
+
+	deleted := 0
+	for _, jobs := range byTask {
+		sort.Slice(jobs, func(i, j int) bool {
+			return jobs[i].ScheduledAt.After(jobs[j].ScheduledAt)
+		})
+		for i, job := range jobs {
+			if deleted >= limit {
+				return deleted, nil
+			}
+			expired := !filter.FinishedBefore.IsZero() && job.FinishedAt.Before(filter.FinishedBefore)
+			overflowed := filter.KeepLatest > 0 && i >= filter.KeepLatest
+			if !expired && !overflowed {
+				continue
+			}
+			delete(r.jobs, job.ID)
+			delete(r.scheduled, scheduleKey(job))
+			delete(r.results, job.ID)
+			r.removeFromQueue(job.ID)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// removeFromQueue removes the job ID from the queue if present. The caller must hold r.mu.
+func (r *InMemoryJobRepository) removeFromQueue(jobID string) {
+	for i, id := range r.queue {
+		if id == jobID {
+			r.queue = append(r.queue[:i:i], r.queue[i+1:]...)
+			return
+		}
+	}
+}
+
+// scheduleKey returns the key identifying a job by its task and scheduled time.
+func scheduleKey(job *domain.Job) string {
+	return job.TaskID + "@" + job.ScheduledAt.UTC().Format(time.RFC3339Nano)
+}
+
 // copyJob creates a shallow copy of a Job object.
 func copyJob(j *domain.Job) *domain.Job {
 	if j == nil {
 		return nil
 	}
 	c := *j
+
+	if j.TraceContext != nil {
+		c.TraceContext = make(map[string]string, len(j.TraceContext))
+		for k, v := range j.TraceContext {
+			c.TraceContext[k] = v
+		}
+	}
+
+	return &c
+}
+
+// copyJobResult creates a deep copy of a JobResult object.
+func copyJobResult(r *domain.JobResult) *domain.JobResult {
+	c := *r
+
+	if r.ResponseHeaders != nil {
+		c.ResponseHeaders = make(map[string]string, len(r.ResponseHeaders))
+		for k, v := range r.ResponseHeaders {
+			c.ResponseHeaders[k] = v
+		}
+	}
+
+	if r.ResponseBody != nil {
+		c.ResponseBody = make([]byte, len(r.ResponseBody))
+		copy(c.ResponseBody, r.ResponseBody)
+	}
+
 	return &c
 }
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// LeaderLockStore holds the leadership state shared by InMemoryLeaderLock candidates.
+// It simulates a distributed lock within a single process, mainly for tests.
+type LeaderLockStore struct {
+	mu        sync.Mutex
+	holder    string
+	expiresAt time.Time
+}
+
+func NewLeaderLockStore() *LeaderLockStore {
+	return &LeaderLockStore{}
+}
+
+// InMemoryLeaderLock implements domain.LeaderLock on top of a LeaderLockStore.
+// Leadership is a lease that expires after ttl unless renewed by TryAcquire,
+// so a candidate that stops renewing (e.g. a crashed process) is failed over.
+type InMemoryLeaderLock struct {
+	store       *LeaderLockStore
+	candidateID string
+	ttl         time.Duration
+}
+
+func NewInMemoryLeaderLock(store *LeaderLockStore, candidateID string, ttl time.Duration) *InMemoryLeaderLock {
+	return &InMemoryLeaderLock{
+		store:       store,
+		candidateID: candidateID,
+		ttl:         ttl,
+	}
+}
+
+func (l *InMemoryLeaderLock) TryAcquire(ctx context.Context) (bool, error) {
+	l.store.mu.Lock()
+	defer l.store.mu.Unlock()
+
+	now := time.Now()
+	if l.store.holder != "" && l.store.holder != l.candidateID && now.Before(l.store.expiresAt) {
+		return false, nil
+	}
+	l.store.holder = l.candidateID
+	l.store.expiresAt = now.Add(l.ttl)
+	return true, nil
+}
+
+func (l *InMemoryLeaderLock) Release(ctx context.Context) error {
+	l.store.mu.Lock()
+	defer l.store.mu.Unlock()
+
+	if l.store.holder == l.candidateID {
+		l.store.holder = ""
+		l.store.expiresAt = time.Time{}
+	}
+	return nil
+}
@@ -3,9 +3,11 @@ package memory
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/yourname/go-dist-scheduler/internal/domain"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/repotest"
 )
 
 func TestInMemoryTaskRepository_Copy(t *testing.T) {
@@ -18,6 +20,7 @@ func TestInMemoryTaskRepository_Copy(t *testing.T) {
 		Payload: domain.HTTPRequestInfo{
 			Headers: map[string]string{"Content-Type": "application/json"},
 			Body:    []byte(`{"key":"value"}`),
+			Signing: domain.SigningPolicy{Secrets: []string{"secret"}},
 		},
 		Status: domain.TaskStatusActive,
 	}
@@ -28,6 +31,7 @@ func TestInMemoryTaskRepository_Copy(t *testing.T) {
 	task.Name = "Modified Task"
 	task.Payload.Headers["X-Test"] = "true"
 	task.Payload.Body[8] = 'X'
+	task.Payload.Signing.Secrets[0] = "modified"
 
 	foundTask, _ := repo.FindByID(ctx, "1")
 
@@ -36,6 +40,7 @@ func TestInMemoryTaskRepository_Copy(t *testing.T) {
 	assert.Equal(t, "application/json", foundTask.Payload.Headers["Content-Type"])
 	assert.NotContains(t, foundTask.Payload.Headers, "X-Test")
 	assert.Equal(t, `{"key":"value"}`, string(foundTask.Payload.Body))
+	assert.Equal(t, []string{"secret"}, foundTask.Payload.Signing.Secrets)
 
 	// Modify the found task
 	foundTask.Name = "Modified Found Task"
@@ -52,10 +57,10 @@ func TestInMemoryJobRepository_Copy(t *testing.T) {
 	job := &domain.Job{ID: "1", TaskID: "task1", Status: domain.JobStatusPending}
 
 	_ = repo.Enqueue(ctx, job)
-	job.Status = domain.JobStatusRunning // Modify original after enqueue
+	job.TaskID = "task2" // Modify original after enqueue
 
 	dequeuedJob, _ := repo.Dequeue(ctx)
-	assert.Equal(t, domain.JobStatusPending, dequeuedJob.Status)
+	assert.Equal(t, "task1", dequeuedJob.TaskID)
 }
 
 func TestInMemoryJobRepository(t *testing.T) {
@@ -69,8 +74,24 @@ func TestInMemoryJobRepository(t *testing.T) {
 
 	dequeuedJob, err := repo.Dequeue(ctx)
 	assert.NoError(t, err)
+	// Dequeue marks the job as Running and leases it for the visibility timeout
+	assert.Equal(t, domain.JobStatusRunning, dequeuedJob.Status)
+	assert.False(t, dequeuedJob.StartedAt.IsZero())
+	assert.False(t, dequeuedJob.LeaseExpiresAt.IsZero())
+	job1.Status = domain.JobStatusRunning
+	job1.StartedAt = dequeuedJob.StartedAt
+	job1.UpdatedAt = dequeuedJob.UpdatedAt
+	job1.LeaseExpiresAt = dequeuedJob.LeaseExpiresAt
 	assert.EqualValues(t, job1, dequeuedJob)
 
+	// The stored job is leased as well, so the reaper can find it once the lease expires
+	stored, err := repo.FindByID(ctx, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, dequeuedJob, stored)
+	expired, err := repo.FindExpiredLeases(ctx, dequeuedJob.LeaseExpiresAt.Add(time.Second), 10)
+	assert.NoError(t, err)
+	assert.Len(t, expired, 1)
+
 	// Test Dequeue from empty queue
 	dequeuedJob, err = repo.Dequeue(ctx)
 	assert.NoError(t, err)
@@ -89,8 +110,8 @@ func TestInMemoryJobRepository_UpdateStatus(t *testing.T) {
 	err = repo.UpdateStatus(ctx, "job1", domain.JobStatusRunning)
 	assert.NoError(t, err)
 
-	// Dequeue and verify status was updated
-	dequeuedJob, err := repo.Dequeue(ctx)
+	// Verify status was updated
+	dequeuedJob, err := repo.FindByID(ctx, "job1")
 	assert.NoError(t, err)
 	assert.Equal(t, domain.JobStatusRunning, dequeuedJob.Status)
 	assert.False(t, dequeuedJob.StartedAt.IsZero(), "StartedAt should be set when marking as Running")
@@ -103,7 +124,7 @@ func TestInMemoryJobRepository_UpdateStatus(t *testing.T) {
 	err = repo.UpdateStatus(ctx, "job2", domain.JobStatusSuccess)
 	assert.NoError(t, err)
 
-	dequeuedJob2, err := repo.Dequeue(ctx)
+	dequeuedJob2, err := repo.FindByID(ctx, "job2")
 	assert.NoError(t, err)
 	assert.Equal(t, domain.JobStatusSuccess, dequeuedJob2.Status)
 	assert.False(t, dequeuedJob2.FinishedAt.IsZero(), "FinishedAt should be set when marking as Success")
@@ -116,7 +137,7 @@ func TestInMemoryJobRepository_UpdateStatus(t *testing.T) {
 	err = repo.UpdateStatus(ctx, "job3", domain.JobStatusFailed)
 	assert.NoError(t, err)
 
-	dequeuedJob3, err := repo.Dequeue(ctx)
+	dequeuedJob3, err := repo.FindByID(ctx, "job3")
 	assert.NoError(t, err)
 	assert.Equal(t, domain.JobStatusFailed, dequeuedJob3.Status)
 	assert.False(t, dequeuedJob3.FinishedAt.IsZero(), "FinishedAt should be set when marking as Failed")
@@ -126,4 +147,437 @@ func TestInMemoryJobRepository_UpdateStatus(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestInMemoryJobRepository_Requeue(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryJobRepository()
+
+	job := &domain.Job{ID: "job1", TaskID: "task1", Status: domain.JobStatusPending}
+	err := repo.Enqueue(ctx, job)
+	assert.NoError(t, err)
+
+	dequeuedJob, err := repo.Dequeue(ctx)
+	assert.NoError(t, err)
+
+	// Requeue with a delay: the job must not be dequeued before AvailableAt
+	dequeuedJob.ScheduleRetry(time.Now().Add(time.Hour))
+	err = repo.Requeue(ctx, dequeuedJob)
+	assert.NoError(t, err)
+
+	notYet, err := repo.Dequeue(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, notYet)
+
+	// Requeue without a delay: the job is dequeued immediately
+	dequeuedJob.ScheduleRetry(time.Time{})
+	err = repo.Requeue(ctx, dequeuedJob)
+	assert.NoError(t, err)
+
+	retried, err := repo.Dequeue(ctx)
+	assert.NoError(t, err)
+	if assert.NotNil(t, retried) {
+		assert.Equal(t, "job1", retried.ID)
+		assert.Equal(t, 2, retried.RetryCount)
+	}
+
+	// The job was requeued twice but must be queued only once
+	again, err := repo.Dequeue(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, again)
+}
+
+func TestInMemoryJobRepository_Enqueue_Duplicate(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryJobRepository()
+	scheduledAt := time.Date(2024, time.April, 1, 9, 0, 0, 0, time.UTC)
+
+	job := &domain.Job{ID: "job1", TaskID: "task1", ScheduledAt: scheduledAt}
+	err := repo.Enqueue(ctx, job)
+	assert.NoError(t, err)
+
+	// Same ID
+	err = repo.Enqueue(ctx, &domain.Job{ID: "job1", TaskID: "task1", ScheduledAt: scheduledAt.Add(time.Minute)})
+	assert.ErrorIs(t, err, domain.ErrConstraintViolation)
+
+	// Same task and scheduled time, even in another time zone
+	jst := time.FixedZone("JST", 9*60*60)
+	err = repo.Enqueue(ctx, &domain.Job{ID: "job2", TaskID: "task1", ScheduledAt: scheduledAt.In(jst)})
+	assert.ErrorIs(t, err, domain.ErrConstraintViolation)
+
+	// Same scheduled time for another task is not a duplicate
+	err = repo.Enqueue(ctx, &domain.Job{ID: "job3", TaskID: "task2", ScheduledAt: scheduledAt})
+	assert.NoError(t, err)
+
+	// Only the non-duplicate jobs are queued
+	first, _ := repo.Dequeue(ctx)
+	second, _ := repo.Dequeue(ctx)
+	third, _ := repo.Dequeue(ctx)
+	assert.Equal(t, "job1", first.ID)
+	assert.Equal(t, "job3", second.ID)
+	assert.Nil(t, third)
+}
+
+func TestInMemoryJobRepository_Lease(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryJobRepository(WithVisibilityTimeout(time.Minute))
+
+	job := &domain.Job{ID: "job1", TaskID: "task1", Status: domain.JobStatusPending}
+	err := repo.Enqueue(ctx, job)
+	assert.NoError(t, err)
+
+	dequeuedJob, err := repo.Dequeue(ctx)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), dequeuedJob.LeaseExpiresAt, time.Second)
+
+	err = repo.UpdateStatus(ctx, "job1", domain.JobStatusRunning)
+	assert.NoError(t, err)
+
+	// The lease has not expired yet
+	expired, err := repo.FindExpiredLeases(ctx, time.Now(), 10)
+	assert.NoError(t, err)
+	assert.Empty(t, expired)
+
+	// The lease expires unless it is extended
+	expired, err = repo.FindExpiredLeases(ctx, time.Now().Add(2*time.Minute), 10)
+	assert.NoError(t, err)
+	if assert.Len(t, expired, 1) {
+		assert.Equal(t, "job1", expired[0].ID)
+		assert.Equal(t, domain.JobStatusRunning, expired[0].Status)
+	}
+
+	// A heartbeat pushes the lease forward
+	time.Sleep(10 * time.Millisecond)
+	err = repo.ExtendLease(ctx, "job1")
+	assert.NoError(t, err)
+	expired, err = repo.FindExpiredLeases(ctx, dequeuedJob.LeaseExpiresAt.Add(time.Millisecond), 10)
+	assert.NoError(t, err)
+	assert.Empty(t, expired)
+
+	// Finished jobs are never expired
+	err = repo.UpdateStatus(ctx, "job1", domain.JobStatusSuccess)
+	assert.NoError(t, err)
+	expired, err = repo.FindExpiredLeases(ctx, time.Now().Add(time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Empty(t, expired)
+}
+
+func TestInMemoryJobRepository_Results(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryJobRepository()
+	now := time.Now()
+
+	first := &domain.JobResult{
+		JobID:           "job1",
+		TaskID:          "task1",
+		Attempt:         1,
+		StatusCode:      500,
+		ResponseHeaders: map[string]string{"Content-Type": "text/plain"},
+		ResponseBody:    []byte("internal error"),
+		Error:           "unexpected status code: 500",
+		FinishedAt:      now.Add(-2 * time.Minute),
+	}
+	second := &domain.JobResult{JobID: "job1", TaskID: "task1", Attempt: 2, StatusCode: 200, FinishedAt: now.Add(-time.Minute)}
+	other := &domain.JobResult{JobID: "job2", TaskID: "task1", Attempt: 1, StatusCode: 200, FinishedAt: now}
+	otherTask := &domain.JobResult{JobID: "job3", TaskID: "task2", Attempt: 1, StatusCode: 200, FinishedAt: now}
+	for _, result := range []*domain.JobResult{second, first, other, otherTask} {
+		assert.NoError(t, repo.SaveResult(ctx, result))
+	}
+
+	// Results of a job are ordered by attempt
+	results, err := repo.FindResultsByJobID(ctx, "job1")
+	assert.NoError(t, err)
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, first, results[0])
+		assert.Equal(t, second, results[1])
+	}
+
+	// Modifying a returned result must not affect the stored one
+	results[0].ResponseHeaders["Content-Type"] = "modified"
+	results[0].ResponseBody[0] = 'X'
+	results, err = repo.FindResultsByJobID(ctx, "job1")
+	assert.NoError(t, err)
+	assert.Equal(t, "text/plain", results[0].ResponseHeaders["Content-Type"])
+	assert.Equal(t, []byte("internal error"), results[0].ResponseBody)
+
+	// Recent results of a task are ordered from the most recently finished
+	results, err = repo.FindRecentResultsByTaskID(ctx, "task1", 2)
+	assert.NoError(t, err)
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, "job2", results[0].JobID)
+		assert.Equal(t, 2, results[1].Attempt)
+	}
+
+	results, err = repo.FindResultsByJobID(ctx, "nonexistent")
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestInMemoryTaskRepository_FindAllAndDelete(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryTaskRepository()
+	now := time.Now()
+
+	active := &domain.Task{ID: "1", Status: domain.TaskStatusActive, CreatedAt: now}
+	paused := &domain.Task{ID: "2", Status: domain.TaskStatusPaused, CreatedAt: now.Add(-time.Minute)}
+	assert.NoError(t, repo.Save(ctx, active))
+	assert.NoError(t, repo.Save(ctx, paused))
+
+	// FindAll returns tasks of every status ordered by creation time
+	tasks, err := repo.FindAll(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, tasks, 2) {
+		assert.Equal(t, "2", tasks[0].ID)
+		assert.Equal(t, "1", tasks[1].ID)
+	}
+
+	assert.NoError(t, repo.Delete(ctx, "2"))
+	assert.NoError(t, repo.Delete(ctx, "nonexistent"))
+
+	deleted, err := repo.FindByID(ctx, "2")
+	assert.NoError(t, err)
+	assert.Nil(t, deleted)
+	tasks, err = repo.FindAll(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, tasks, 1)
+}
 
+func TestInMemoryTaskRepository_Version(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryTaskRepository()
+
+	task := &domain.Task{ID: "1", Name: "Task", Status: domain.TaskStatusActive}
+	assert.NoError(t, repo.Save(ctx, task))
+	assert.Equal(t, int64(1), task.Version)
+
+	// Two writers read the same version; the second save conflicts
+	first, _ := repo.FindByID(ctx, "1")
+	second, _ := repo.FindByID(ctx, "1")
+	first.Name = "First"
+	assert.NoError(t, repo.Save(ctx, first))
+	assert.Equal(t, int64(2), first.Version)
+	second.Name = "Second"
+	assert.ErrorIs(t, repo.Save(ctx, second), domain.ErrConflict)
+
+	stored, _ := repo.FindByID(ctx, "1")
+	assert.Equal(t, "First", stored.Name)
+	assert.Equal(t, int64(2), stored.Version)
+}
+
+func TestInMemoryTaskRepository_UpdateLastCheckedAt(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryTaskRepository()
+	now := time.Date(2024, time.January, 1, 10, 0, 0, 0, time.UTC)
+
+	task := &domain.Task{ID: "1", Status: domain.TaskStatusActive}
+	assert.NoError(t, repo.Save(ctx, task))
+
+	// A concurrent pause saved between the scheduler's read and write is kept
+	paused, _ := repo.FindByID(ctx, "1")
+	paused.Status = domain.TaskStatusPaused
+	assert.NoError(t, repo.Save(ctx, paused))
+	assert.NoError(t, repo.UpdateLastCheckedAt(ctx, "1", now))
+
+	stored, _ := repo.FindByID(ctx, "1")
+	assert.Equal(t, domain.TaskStatusPaused, stored.Status)
+	assert.Equal(t, now, stored.LastCheckedAt)
+	assert.Equal(t, paused.Version, stored.Version, "updating the last checked time does not change the version")
+
+	// The last checked time never moves backwards, neither here nor through Save
+	assert.NoError(t, repo.UpdateLastCheckedAt(ctx, "1", now.Add(-time.Minute)))
+	assert.NoError(t, repo.Save(ctx, paused))
+	stored, _ = repo.FindByID(ctx, "1")
+	assert.Equal(t, now, stored.LastCheckedAt)
+
+	// Unknown tasks are ignored
+	assert.NoError(t, repo.UpdateLastCheckedAt(ctx, "nonexistent", now))
+}
+
+func TestInMemoryJobRepository_FindByIDAndTaskID(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryJobRepository()
+	now := time.Now()
+
+	older := &domain.Job{ID: "1", TaskID: "task1", ScheduledAt: now.Add(-time.Minute)}
+	newer := &domain.Job{ID: "2", TaskID: "task1", ScheduledAt: now}
+	other := &domain.Job{ID: "3", TaskID: "task2", ScheduledAt: now}
+	for _, job := range []*domain.Job{older, newer, other} {
+		assert.NoError(t, repo.Enqueue(ctx, job))
+	}
+
+	job, err := repo.FindByID(ctx, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, older, job)
+
+	job, err = repo.FindByID(ctx, "nonexistent")
+	assert.NoError(t, err)
+	assert.Nil(t, job)
+
+	// Jobs of a task are ordered from the most recently scheduled
+	jobs, err := repo.FindByTaskID(ctx, "task1", 10)
+	assert.NoError(t, err)
+	if assert.Len(t, jobs, 2) {
+		assert.Equal(t, "2", jobs[0].ID)
+		assert.Equal(t, "1", jobs[1].ID)
+	}
+
+	jobs, err = repo.FindByTaskID(ctx, "task1", 1)
+	assert.NoError(t, err)
+	assert.Len(t, jobs, 1)
+}
+
+func TestInMemoryJobRepository_Cancel(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryJobRepository()
+	now := time.Now()
+
+	pending := &domain.Job{ID: "1", TaskID: "task1", ScheduledAt: now.Add(-time.Minute), Status: domain.JobStatusPending}
+	running := &domain.Job{ID: "2", TaskID: "task1", ScheduledAt: now.Add(-2 * time.Minute), Status: domain.JobStatusPending}
+	finished := &domain.Job{ID: "3", TaskID: "task1", ScheduledAt: now.Add(-3 * time.Minute), Status: domain.JobStatusPending}
+	for _, job := range []*domain.Job{running, finished, pending} {
+		assert.NoError(t, repo.Enqueue(ctx, job))
+	}
+	for _, id := range []string{"2", "3"} {
+		_, err := repo.Dequeue(ctx)
+		assert.NoError(t, err)
+		assert.NoError(t, repo.UpdateStatus(ctx, id, domain.JobStatusRunning))
+	}
+	assert.NoError(t, repo.UpdateStatus(ctx, "3", domain.JobStatusSuccess))
+
+	// Only unfinished jobs are active, earliest scheduled first
+	active, err := repo.FindActiveByTaskID(ctx, "task1")
+	assert.NoError(t, err)
+	if assert.Len(t, active, 2) {
+		assert.Equal(t, "2", active[0].ID)
+		assert.Equal(t, "1", active[1].ID)
+	}
+
+	for _, id := range []string{"1", "2", "3", "nonexistent"} {
+		assert.NoError(t, repo.Cancel(ctx, id))
+	}
+
+	active, err = repo.FindActiveByTaskID(ctx, "task1")
+	assert.NoError(t, err)
+	assert.Empty(t, active)
+
+	// Cancelled jobs are no longer dequeued or leased
+	job, err := repo.Dequeue(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, job)
+	expired, err := repo.FindExpiredLeases(ctx, now.Add(time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Empty(t, expired)
+
+	// Finished jobs are not cancelled
+	job, err = repo.FindByID(ctx, "3")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.JobStatusSuccess, job.Status)
+
+	// The status of a cancelled job does not change afterwards
+	job, err = repo.FindByID(ctx, "2")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.JobStatusCancelled, job.Status)
+	assert.False(t, job.FinishedAt.IsZero())
+	assert.NoError(t, repo.UpdateStatus(ctx, "2", domain.JobStatusSuccess))
+	job.ScheduleRetry(now)
+	assert.NoError(t, repo.Requeue(ctx, job))
+	job, err = repo.FindByID(ctx, "2")
+	assert.NoError(t, err)
+	assert.Equal(t, domain.JobStatusCancelled, job.Status)
+	job, err = repo.Dequeue(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, job)
+}
+
+func TestInMemoryJobRepository_CountPending(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryJobRepository()
+
+	for _, id := range []string{"job1", "job2", "job3"} {
+		assert.NoError(t, repo.Enqueue(ctx, &domain.Job{ID: id, TaskID: id, Status: domain.JobStatusPending}))
+	}
+	count, err := repo.CountPending(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	// A dequeued job is no longer pending, but it is counted again while waiting for a retry
+	job, err := repo.Dequeue(ctx)
+	assert.NoError(t, err)
+	count, err = repo.CountPending(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	job.ScheduleRetry(time.Now().Add(time.Hour))
+	assert.NoError(t, repo.Requeue(ctx, job))
+	assert.NoError(t, repo.Cancel(ctx, "job2"))
+	count, err = repo.CountPending(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestInMemoryJobRepository_DeleteFinished(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryJobRepository()
+
+	now := time.Date(2024, time.January, 8, 12, 0, 0, 0, time.UTC)
+	newFinishedJob := func(id, taskID string, age time.Duration, status domain.JobStatus) {
+		job := &domain.Job{ID: id, TaskID: taskID, ScheduledAt: now.Add(-age), Status: status, FinishedAt: now.Add(-age)}
+		assert.NoError(t, repo.Enqueue(ctx, job))
+	}
+	newFinishedJob("a-old", "a", 3*time.Hour, domain.JobStatusSuccess)
+	newFinishedJob("a-older", "a", 4*time.Hour, domain.JobStatusCancelled)
+	newFinishedJob("a-recent", "a", time.Minute, domain.JobStatusSuccess)
+	newFinishedJob("a-failed", "a", 150*time.Minute, domain.JobStatusFailed)
+	newFinishedJob("b-old", "b", 3*time.Hour, domain.JobStatusSuccess)
+	assert.NoError(t, repo.Enqueue(ctx, &domain.Job{ID: "a-pending", TaskID: "a", ScheduledAt: now.Add(-5 * time.Hour)}))
+	assert.NoError(t, repo.SaveResult(ctx, &domain.JobResult{JobID: "a-old", TaskID: "a", Attempt: 1}))
+
+	// Completed jobs of every task but b older than an hour, one per call
+	filter := domain.NewJobPruneFilter(domain.RetentionRule{MaxAge: time.Hour}, domain.CompletedJobStatuses, now)
+	filter.ExcludeTaskIDs = []string{"b"}
+	deleted, err := repo.DeleteFinished(ctx, filter, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	deleted, err = repo.DeleteFinished(ctx, filter, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	deleted, err = repo.DeleteFinished(ctx, filter, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, deleted)
+
+	for _, id := range []string{"a-old", "a-older"} {
+		job, _ := repo.FindByID(ctx, id)
+		assert.Nil(t, job, id)
+	}
+	for _, id := range []string{"a-recent", "a-failed", "b-old", "a-pending"} {
+		job, _ := repo.FindByID(ctx, id)
+		assert.NotNil(t, job, id)
+	}
+	results, _ := repo.FindResultsByJobID(ctx, "a-old")
+	assert.Empty(t, results)
+
+	// A deleted job no longer blocks a job with the same schedule
+	assert.NoError(t, repo.Enqueue(ctx, &domain.Job{ID: "a-old-again", TaskID: "a", ScheduledAt: now.Add(-3 * time.Hour)}))
+
+	// Keep only the latest failed job of task a
+	newFinishedJob("a-failed-latest", "a", 2*time.Hour, domain.JobStatusTimedOut)
+	filter = domain.NewJobPruneFilter(domain.RetentionRule{MaxJobs: 1}, domain.FailedJobStatuses, now)
+	filter.TaskID = "a"
+	deleted, err = repo.DeleteFinished(ctx, filter, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	job, _ := repo.FindByID(ctx, "a-failed")
+	assert.Nil(t, job)
+	job, _ = repo.FindByID(ctx, "a-failed-latest")
+	assert.NotNil(t, job)
+}
+
+func TestInMemoryJobRepository_ReclaimExpired_Shared(t *testing.T) {
+	repotest.TestReclaimExpired(t, func(t *testing.T) domain.JobRepository {
+		return NewInMemoryJobRepository()
+	})
+}
+
+func TestInMemoryJobRepository_DeleteFinished_Shared(t *testing.T) {
+	repotest.TestDeleteFinished(t, func(t *testing.T) repotest.JobHistoryRepository {
+		return NewInMemoryJobRepository()
+	})
+}
@@ -2,7 +2,10 @@ package memory
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	"github.com/yourname/go-dist-scheduler/internal/domain"
 )
@@ -18,11 +21,38 @@ func NewInMemoryTaskRepository() *InMemoryTaskRepository {
 	}
 }
 
+// Save stores a task and advances its version. Saving a task whose version
+// differs from the stored one returns domain.ErrConflict.
 func (r *InMemoryTaskRepository) Save(ctx context.Context, task *domain.Task) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.tasks[task.ID] = copyTask(task)
+	saved := copyTask(task)
+	if stored, ok := r.tasks[task.ID]; ok {
+		if stored.Version != task.Version {
+			return fmt.Errorf("%w: task %s has been modified", domain.ErrConflict, task.ID)
+		}
+		// The last checked time is owned by UpdateLastCheckedAt and never moves backwards
+		if stored.LastCheckedAt.After(saved.LastCheckedAt) {
+			saved.LastCheckedAt = stored.LastCheckedAt
+		}
+	}
+	saved.Version++
+	r.tasks[task.ID] = saved
+	task.Version = saved.Version
+	task.LastCheckedAt = saved.LastCheckedAt
+	return nil
+}
+
+// UpdateLastCheckedAt moves the last checked time of a task forward without
+// touching its other fields or version.
+func (r *InMemoryTaskRepository) UpdateLastCheckedAt(ctx context.Context, taskID string, checkedAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if task, ok := r.tasks[taskID]; ok && checkedAt.After(task.LastCheckedAt) {
+		task.LastCheckedAt = checkedAt
+	}
 	return nil
 }
 
@@ -47,6 +77,31 @@ func (r *InMemoryTaskRepository) FindAllActive(ctx context.Context) ([]*domain.T
 	return activeTasks, nil
 }
 
+// FindAll returns all tasks ordered by creation time.
+func (r *InMemoryTaskRepository) FindAll(ctx context.Context) ([]*domain.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tasks := make([]*domain.Task, 0, len(r.tasks))
+	for _, task := range r.tasks {
+		tasks = append(tasks, copyTask(task))
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		if !tasks[i].CreatedAt.Equal(tasks[j].CreatedAt) {
+			return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+		}
+		return tasks[i].ID < tasks[j].ID
+	})
+	return tasks, nil
+}
+
+// Delete removes a task. Deleting a task that does not exist is not an error.
+func (r *InMemoryTaskRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.tasks, id)
+	return nil
+}
+
 // copyTask creates a deep copy of a Task object.
 func copyTask(t *domain.Task) *domain.Task {
 	if t == nil {
@@ -69,5 +124,17 @@ func copyTask(t *domain.Task) *domain.Task {
 		copy(c.Payload.Body, t.Payload.Body)
 	}
 
+	// Deep copy the signing Secrets slice
+	if t.Payload.Signing.Secrets != nil {
+		c.Payload.Signing.Secrets = make([]string, len(t.Payload.Signing.Secrets))
+		copy(c.Payload.Signing.Secrets, t.Payload.Signing.Secrets)
+	}
+
+	// Deep copy the StatusHistory slice
+	if t.StatusHistory != nil {
+		c.StatusHistory = make([]domain.TaskStatusChange, len(t.StatusHistory))
+		copy(c.StatusHistory, t.StatusHistory)
+	}
+
 	return &c
 }
@@ -0,0 +1,150 @@
+// Package metrics exposes the scheduler's Prometheus metrics. The scheduler
+// and executor report to Metrics through their observer hooks, and the
+// repositories are instrumented by wrapping them with decorators, so nothing
+// is registered in the global Prometheus registry.
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+)
+
+const namespace = "scheduler"
+
+// Metrics holds the scheduler's Prometheus collectors. It implements
+// usecase.SchedulerObserver, usecase.ExecutorObserver and usecase.PrunerObserver.
+type Metrics struct {
+	jobsEnqueued     *prometheus.CounterVec
+	jobsStarted      *prometheus.CounterVec
+	jobsSucceeded    *prometheus.CounterVec
+	jobsFailed       *prometheus.CounterVec
+	scheduleLag      *prometheus.HistogramVec
+	jobDuration      *prometheus.HistogramVec
+	checkDuration    prometheus.Histogram
+	jobsPruned       prometheus.Counter
+	repositoryErrors *prometheus.CounterVec
+}
+
+// New creates the scheduler's collectors and registers them with reg.
+func New(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		jobsEnqueued: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "jobs_enqueued_total",
+			Help:      "Number of jobs enqueued, including manually triggered jobs.",
+		}, []string{"task_id"}),
+		jobsStarted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "jobs_started_total",
+			Help:      "Number of job attempts started, including retries.",
+		}, []string{"task_id"}),
+		jobsSucceeded: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "jobs_succeeded_total",
+			Help:      "Number of job attempts that succeeded.",
+		}, []string{"task_id"}),
+		jobsFailed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "jobs_failed_total",
+			Help:      "Number of job attempts that failed or timed out, including attempts that are retried.",
+		}, []string{"task_id"}),
+		scheduleLag: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "job_schedule_lag_seconds",
+			Help:      "Delay between the scheduled time of a job and the start of its first attempt.",
+			Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300},
+		}, []string{"task_id"}),
+		jobDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "job_duration_seconds",
+			Help:      "Duration of job attempts.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"task_id"}),
+		checkDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "check_duration_seconds",
+			Help:      "Duration of one CheckAndEnqueue pass over the active tasks.",
+			Buckets:   prometheus.DefBuckets,
+		}),
+		jobsPruned: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "jobs_pruned_total",
+			Help:      "Number of finished jobs deleted by the retention policies.",
+		}),
+		repositoryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "repository_errors_total",
+			Help:      "Number of failed repository operations.",
+		}, []string{"repository", "operation"}),
+	}
+
+	reg.MustRegister(
+		m.jobsEnqueued,
+		m.jobsStarted,
+		m.jobsSucceeded,
+		m.jobsFailed,
+		m.scheduleLag,
+		m.jobDuration,
+		m.checkDuration,
+		m.jobsPruned,
+		m.repositoryErrors,
+	)
+	return m
+}
+
+// Handler returns an HTTP handler that serves the metrics gathered by g.
+func Handler(g prometheus.Gatherer) http.Handler {
+	return promhttp.HandlerFor(g, promhttp.HandlerOpts{})
+}
+
+// CheckCompleted records the duration of a CheckAndEnqueue pass.
+func (m *Metrics) CheckCompleted(duration time.Duration, err error) {
+	m.checkDuration.Observe(duration.Seconds())
+}
+
+// JobStarted counts a started attempt. The schedule lag is only recorded for
+// the first attempt, because retries are delayed on purpose by the backoff.
+func (m *Metrics) JobStarted(job *domain.Job, startedAt time.Time) {
+	m.jobsStarted.WithLabelValues(job.TaskID).Inc()
+	if job.RetryCount == 0 {
+		m.scheduleLag.WithLabelValues(job.TaskID).Observe(startedAt.Sub(job.ScheduledAt).Seconds())
+	}
+}
+
+// JobFinished counts the outcome of an attempt and records its duration.
+// Cancelled attempts are neither successes nor failures.
+func (m *Metrics) JobFinished(job *domain.Job, result *domain.JobResult, err error) {
+	m.jobDuration.WithLabelValues(job.TaskID).Observe(result.Duration.Seconds())
+	switch {
+	case err == nil:
+		m.jobsSucceeded.WithLabelValues(job.TaskID).Inc()
+	case !errors.Is(err, domain.ErrJobCancelled):
+		m.jobsFailed.WithLabelValues(job.TaskID).Inc()
+	}
+}
+
+// JobsPruned counts the finished jobs deleted by one Prune pass.
+func (m *Metrics) JobsPruned(count int) {
+	m.jobsPruned.Add(float64(count))
+}
+
+// jobEnqueued counts a job that was added to the queue.
+func (m *Metrics) jobEnqueued(job *domain.Job) {
+	m.jobsEnqueued.WithLabelValues(job.TaskID).Inc()
+}
+
+// observeRepository counts err as a failed operation of a repository.
+// domain.ErrConstraintViolation is not counted, because the scheduler relies
+// on it to detect jobs that were already enqueued. Neither is domain.ErrConflict,
+// which only means that a concurrent update of a task won and is retried.
+func (m *Metrics) observeRepository(repository, operation string, err error) {
+	if err == nil || errors.Is(err, domain.ErrConstraintViolation) || errors.Is(err, domain.ErrConflict) {
+		return
+	}
+	m.repositoryErrors.WithLabelValues(repository, operation).Inc()
+}
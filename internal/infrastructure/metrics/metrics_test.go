@@ -0,0 +1,143 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/memory"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/metrics"
+)
+
+// failingTaskRepository is a TaskRepository whose FindAllActive always fails.
+type failingTaskRepository struct {
+	domain.TaskRepository
+}
+
+func (r *failingTaskRepository) FindAllActive(ctx context.Context) ([]*domain.Task, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestMetrics_JobLifecycle(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := metrics.New(reg)
+
+	scheduledAt := time.Date(2024, time.April, 1, 9, 0, 0, 0, time.UTC)
+	job := &domain.Job{ID: "job1", TaskID: "task1", ScheduledAt: scheduledAt}
+
+	m.JobStarted(job, scheduledAt.Add(2*time.Second))
+	m.JobFinished(job, &domain.JobResult{Duration: 300 * time.Millisecond}, errors.New("unexpected status code: 500"))
+	job.RetryCount = 1
+	m.JobStarted(job, scheduledAt.Add(time.Minute))
+	m.JobFinished(job, &domain.JobResult{Duration: 100 * time.Millisecond}, nil)
+	m.JobStarted(job, scheduledAt.Add(2*time.Minute))
+	m.JobFinished(job, &domain.JobResult{}, domain.ErrJobCancelled)
+	m.CheckCompleted(10*time.Millisecond, nil)
+
+	expected := `
+# HELP scheduler_jobs_started_total Number of job attempts started, including retries.
+# TYPE scheduler_jobs_started_total counter
+scheduler_jobs_started_total{task_id="task1"} 3
+# HELP scheduler_jobs_succeeded_total Number of job attempts that succeeded.
+# TYPE scheduler_jobs_succeeded_total counter
+scheduler_jobs_succeeded_total{task_id="task1"} 1
+# HELP scheduler_jobs_failed_total Number of job attempts that failed or timed out, including attempts that are retried.
+# TYPE scheduler_jobs_failed_total counter
+scheduler_jobs_failed_total{task_id="task1"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"scheduler_jobs_started_total", "scheduler_jobs_succeeded_total", "scheduler_jobs_failed_total"))
+
+	// Only the first attempt contributes to the schedule lag
+	assert.Equal(t, 1, histogramCount(t, reg, "scheduler_job_schedule_lag_seconds"))
+	assert.Equal(t, 3, histogramCount(t, reg, "scheduler_job_duration_seconds"))
+	assert.Equal(t, 1, histogramCount(t, reg, "scheduler_check_duration_seconds"))
+}
+
+func TestMetrics_JobsPruned(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := metrics.New(reg)
+
+	m.JobsPruned(120)
+	m.JobsPruned(0)
+	m.JobsPruned(5)
+
+	expected := `
+# HELP scheduler_jobs_pruned_total Number of finished jobs deleted by the retention policies.
+# TYPE scheduler_jobs_pruned_total counter
+scheduler_jobs_pruned_total 125
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "scheduler_jobs_pruned_total"))
+}
+
+func TestMetrics_InstrumentRepositories(t *testing.T) {
+	ctx := context.Background()
+	reg := prometheus.NewRegistry()
+	m := metrics.New(reg)
+
+	jobRepo := m.InstrumentJobRepository(memory.NewInMemoryJobRepository())
+	reg.MustRegister(metrics.NewQueueDepthCollector(jobRepo))
+
+	job := &domain.Job{ID: "job1", TaskID: "task1", Status: domain.JobStatusPending}
+	require.NoError(t, jobRepo.Enqueue(ctx, job))
+	require.NoError(t, jobRepo.Enqueue(ctx, &domain.Job{ID: "job2", TaskID: "task1", Status: domain.JobStatusPending, ScheduledAt: time.Now()}))
+	// A duplicate enqueue is an expected outcome and is not counted as an error
+	assert.ErrorIs(t, jobRepo.Enqueue(ctx, job), domain.ErrConstraintViolation)
+
+	taskRepo := m.InstrumentTaskRepository(&failingTaskRepository{TaskRepository: memory.NewInMemoryTaskRepository()})
+	_, err := taskRepo.FindAllActive(ctx)
+	assert.Error(t, err)
+
+	expected := `
+# HELP scheduler_jobs_enqueued_total Number of jobs enqueued, including manually triggered jobs.
+# TYPE scheduler_jobs_enqueued_total counter
+scheduler_jobs_enqueued_total{task_id="task1"} 2
+# HELP scheduler_queue_depth Number of pending jobs waiting to be dequeued, including jobs waiting for a retry.
+# TYPE scheduler_queue_depth gauge
+scheduler_queue_depth 2
+# HELP scheduler_repository_errors_total Number of failed repository operations.
+# TYPE scheduler_repository_errors_total counter
+scheduler_repository_errors_total{operation="find_all_active",repository="task"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"scheduler_jobs_enqueued_total", "scheduler_queue_depth", "scheduler_repository_errors_total"))
+}
+
+func TestHandler(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := metrics.New(reg)
+	m.CheckCompleted(time.Millisecond, nil)
+
+	rec := httptest.NewRecorder()
+	metrics.Handler(reg).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "scheduler_check_duration_seconds_count 1")
+}
+
+// histogramCount returns the total number of observations of the histogram family name.
+func histogramCount(t *testing.T, g prometheus.Gatherer, name string) int {
+	t.Helper()
+
+	families, err := g.Gather()
+	require.NoError(t, err)
+	count := 0
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			count += int(metric.GetHistogram().GetSampleCount())
+		}
+	}
+	return count
+}
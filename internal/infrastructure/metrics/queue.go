@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+)
+
+// queueDepthTimeout bounds how long a scrape waits for the pending job count.
+const queueDepthTimeout = 5 * time.Second
+
+// QueueDepthCollector reports the number of pending jobs, read from the job
+// repository on every scrape so that it covers jobs enqueued by every
+// scheduler process.
+type QueueDepthCollector struct {
+	jobRepo domain.JobRepository
+	desc    *prometheus.Desc
+	logger  *slog.Logger
+}
+
+// QueueDepthCollectorOption configures a QueueDepthCollector.
+type QueueDepthCollectorOption func(*QueueDepthCollector)
+
+// WithQueueDepthLogger sets the logger that reports failed reads of the queue depth.
+func WithQueueDepthLogger(logger *slog.Logger) QueueDepthCollectorOption {
+	return func(c *QueueDepthCollector) {
+		c.logger = logger
+	}
+}
+
+// NewQueueDepthCollector creates a collector that reads the queue depth from
+// jobRepo. It logs to slog.Default() unless another logger is set.
+func NewQueueDepthCollector(jobRepo domain.JobRepository, opts ...QueueDepthCollectorOption) *QueueDepthCollector {
+	c := &QueueDepthCollector{
+		jobRepo: jobRepo,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "queue_depth"),
+			"Number of pending jobs waiting to be dequeued, including jobs waiting for a retry.",
+			nil, nil,
+		),
+		logger: slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Describe implements prometheus.Collector.
+func (c *QueueDepthCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector. If the count cannot be read, the
+// metric is omitted from the scrape instead of failing the other metrics.
+func (c *QueueDepthCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), queueDepthTimeout)
+	defer cancel()
+
+	count, err := c.jobRepo.CountPending(ctx)
+	if err != nil {
+		c.logger.Error("failed to collect queue depth", slog.Any("error", err))
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count))
+}
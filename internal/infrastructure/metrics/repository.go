@@ -0,0 +1,185 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+)
+
+// InstrumentTaskRepository wraps repo so that its failed operations are counted.
+func (m *Metrics) InstrumentTaskRepository(repo domain.TaskRepository) domain.TaskRepository {
+	return &taskRepository{next: repo, metrics: m}
+}
+
+// InstrumentJobRepository wraps repo so that its failed operations and
+// enqueued jobs are counted.
+func (m *Metrics) InstrumentJobRepository(repo domain.JobRepository) domain.JobRepository {
+	return &jobRepository{next: repo, metrics: m}
+}
+
+// InstrumentJobHistoryRepository wraps repo so that its failed operations are counted.
+func (m *Metrics) InstrumentJobHistoryRepository(repo domain.JobHistoryRepository) domain.JobHistoryRepository {
+	return &jobHistoryRepository{next: repo, metrics: m}
+}
+
+// taskRepository is a domain.TaskRepository decorator that records metrics.
+type taskRepository struct {
+	next    domain.TaskRepository
+	metrics *Metrics
+}
+
+func (r *taskRepository) observe(operation string, err error) {
+	r.metrics.observeRepository("task", operation, err)
+}
+
+func (r *taskRepository) Save(ctx context.Context, task *domain.Task) error {
+	err := r.next.Save(ctx, task)
+	r.observe("save", err)
+	return err
+}
+
+func (r *taskRepository) UpdateLastCheckedAt(ctx context.Context, taskID string, checkedAt time.Time) error {
+	err := r.next.UpdateLastCheckedAt(ctx, taskID, checkedAt)
+	r.observe("update_last_checked_at", err)
+	return err
+}
+
+func (r *taskRepository) FindByID(ctx context.Context, id string) (*domain.Task, error) {
+	task, err := r.next.FindByID(ctx, id)
+	r.observe("find_by_id", err)
+	return task, err
+}
+
+func (r *taskRepository) FindAllActive(ctx context.Context) ([]*domain.Task, error) {
+	tasks, err := r.next.FindAllActive(ctx)
+	r.observe("find_all_active", err)
+	return tasks, err
+}
+
+func (r *taskRepository) FindAll(ctx context.Context) ([]*domain.Task, error) {
+	tasks, err := r.next.FindAll(ctx)
+	r.observe("find_all", err)
+	return tasks, err
+}
+
+func (r *taskRepository) Delete(ctx context.Context, id string) error {
+	err := r.next.Delete(ctx, id)
+	r.observe("delete", err)
+	return err
+}
+
+// jobRepository is a domain.JobRepository decorator that records metrics.
+type jobRepository struct {
+	next    domain.JobRepository
+	metrics *Metrics
+}
+
+func (r *jobRepository) observe(operation string, err error) {
+	r.metrics.observeRepository("job", operation, err)
+}
+
+func (r *jobRepository) Enqueue(ctx context.Context, job *domain.Job) error {
+	err := r.next.Enqueue(ctx, job)
+	r.observe("enqueue", err)
+	if err == nil {
+		r.metrics.jobEnqueued(job)
+	}
+	return err
+}
+
+func (r *jobRepository) Dequeue(ctx context.Context) (*domain.Job, error) {
+	job, err := r.next.Dequeue(ctx)
+	r.observe("dequeue", err)
+	return job, err
+}
+
+func (r *jobRepository) UpdateStatus(ctx context.Context, jobID string, status domain.JobStatus) error {
+	err := r.next.UpdateStatus(ctx, jobID, status)
+	r.observe("update_status", err)
+	return err
+}
+
+func (r *jobRepository) FindByID(ctx context.Context, jobID string) (*domain.Job, error) {
+	job, err := r.next.FindByID(ctx, jobID)
+	r.observe("find_by_id", err)
+	return job, err
+}
+
+func (r *jobRepository) FindByTaskID(ctx context.Context, taskID string, limit int) ([]*domain.Job, error) {
+	jobs, err := r.next.FindByTaskID(ctx, taskID, limit)
+	r.observe("find_by_task_id", err)
+	return jobs, err
+}
+
+func (r *jobRepository) FindActiveByTaskID(ctx context.Context, taskID string) ([]*domain.Job, error) {
+	jobs, err := r.next.FindActiveByTaskID(ctx, taskID)
+	r.observe("find_active_by_task_id", err)
+	return jobs, err
+}
+
+func (r *jobRepository) CountPending(ctx context.Context) (int, error) {
+	count, err := r.next.CountPending(ctx)
+	r.observe("count_pending", err)
+	return count, err
+}
+
+func (r *jobRepository) Cancel(ctx context.Context, jobID string) error {
+	err := r.next.Cancel(ctx, jobID)
+	r.observe("cancel", err)
+	return err
+}
+
+func (r *jobRepository) Requeue(ctx context.Context, job *domain.Job) error {
+	err := r.next.Requeue(ctx, job)
+	r.observe("requeue", err)
+	return err
+}
+
+func (r *jobRepository) ExtendLease(ctx context.Context, jobID string) error {
+	err := r.next.ExtendLease(ctx, jobID)
+	r.observe("extend_lease", err)
+	return err
+}
+
+func (r *jobRepository) FindExpiredLeases(ctx context.Context, now time.Time, limit int) ([]*domain.Job, error) {
+	jobs, err := r.next.FindExpiredLeases(ctx, now, limit)
+	r.observe("find_expired_leases", err)
+	return jobs, err
+}
+
+func (r *jobRepository) ReclaimExpired(ctx context.Context, job *domain.Job, now time.Time) (bool, error) {
+	reclaimed, err := r.next.ReclaimExpired(ctx, job, now)
+	r.observe("reclaim_expired", err)
+	return reclaimed, err
+}
+
+func (r *jobRepository) SaveResult(ctx context.Context, result *domain.JobResult) error {
+	err := r.next.SaveResult(ctx, result)
+	r.observe("save_result", err)
+	return err
+}
+
+func (r *jobRepository) FindResultsByJobID(ctx context.Context, jobID string) ([]*domain.JobResult, error) {
+	results, err := r.next.FindResultsByJobID(ctx, jobID)
+	r.observe("find_results_by_job_id", err)
+	return results, err
+}
+
+func (r *jobRepository) FindRecentResultsByTaskID(ctx context.Context, taskID string, limit int) ([]*domain.JobResult, error) {
+	results, err := r.next.FindRecentResultsByTaskID(ctx, taskID, limit)
+	r.observe("find_recent_results_by_task_id", err)
+	return results, err
+}
+
+// jobHistoryRepository is a domain.JobHistoryRepository decorator that records metrics.
+type jobHistoryRepository struct {
+	next    domain.JobHistoryRepository
+	metrics *Metrics
+}
+
+func (r *jobHistoryRepository) DeleteFinished(ctx context.Context, filter domain.JobPruneFilter, limit int) (int, error) {
+	deleted, err := r.next.DeleteFinished(ctx, filter, limit)
+	r.metrics.observeRepository("job_history", "delete_finished", err)
+	return deleted, err
+}
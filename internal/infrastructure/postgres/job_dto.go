@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+)
+
+// JobDTO represents the database row structure for a Job.
+type JobDTO struct {
+	ID             string       `db:"id"`
+	TaskID         string       `db:"task_id"`
+	ScheduledAt    time.Time    `db:"scheduled_at"`
+	StartedAt      sql.NullTime `db:"started_at"`
+	FinishedAt     sql.NullTime `db:"finished_at"`
+	Status         int          `db:"status"`
+	RetryCount     int          `db:"retry_count"`
+	AvailableAt    sql.NullTime `db:"available_at"`
+	TriggerSource  int          `db:"trigger_source"`
+	TraceContext   []byte       `db:"trace_context"`
+	LeaseExpiresAt sql.NullTime `db:"lease_expires_at"`
+	CreatedAt      time.Time    `db:"created_at"`
+	UpdatedAt      time.Time    `db:"updated_at"`
+}
+
+// ToJobDTO converts a domain Job to a JobDTO.
+func ToJobDTO(job *domain.Job) *JobDTO {
+	traceContext := []byte("{}")
+	if len(job.TraceContext) > 0 {
+		// Marshalling a map[string]string cannot fail
+		traceContext, _ = json.Marshal(job.TraceContext)
+	}
+
+	dto := &JobDTO{
+		ID:            job.ID,
+		TaskID:        job.TaskID,
+		ScheduledAt:   job.ScheduledAt.UTC(), // Normalized so that the unique constraint compares instants
+		Status:        int(job.Status),
+		RetryCount:    job.RetryCount,
+		TriggerSource: int(job.TriggerSource),
+		TraceContext:  traceContext,
+		CreatedAt:     job.CreatedAt,
+		UpdatedAt:     job.UpdatedAt,
+	}
+
+	if !job.StartedAt.IsZero() {
+		dto.StartedAt = sql.NullTime{Time: job.StartedAt, Valid: true}
+	}
+	if !job.FinishedAt.IsZero() {
+		dto.FinishedAt = sql.NullTime{Time: job.FinishedAt, Valid: true}
+	}
+	if !job.AvailableAt.IsZero() {
+		// Stored in UTC because Dequeue compares it against the current UTC time
+		dto.AvailableAt = sql.NullTime{Time: job.AvailableAt.UTC(), Valid: true}
+	}
+	if !job.LeaseExpiresAt.IsZero() {
+		// Stored in UTC because the reaper compares it against the current UTC time
+		dto.LeaseExpiresAt = sql.NullTime{Time: job.LeaseExpiresAt.UTC(), Valid: true}
+	}
+
+	return dto
+}
+
+// ToDomain converts a JobDTO to a domain Job.
+func (dto *JobDTO) ToDomain() *domain.Job {
+	job := &domain.Job{
+		ID:            dto.ID,
+		TaskID:        dto.TaskID,
+		ScheduledAt:   dto.ScheduledAt,
+		Status:        domain.JobStatus(dto.Status),
+		RetryCount:    dto.RetryCount,
+		TriggerSource: domain.TriggerSource(dto.TriggerSource),
+		CreatedAt:     dto.CreatedAt,
+		UpdatedAt:     dto.UpdatedAt,
+	}
+
+	if dto.StartedAt.Valid {
+		job.StartedAt = dto.StartedAt.Time
+	}
+	if dto.FinishedAt.Valid {
+		job.FinishedAt = dto.FinishedAt.Time
+	}
+	if dto.AvailableAt.Valid {
+		job.AvailableAt = dto.AvailableAt.Time
+	}
+	if dto.LeaseExpiresAt.Valid {
+		job.LeaseExpiresAt = dto.LeaseExpiresAt.Time
+	}
+	if len(dto.TraceContext) > 0 {
+		// A malformed trace context only breaks the link to the trace, so it is ignored
+		var traceContext map[string]string
+		if err := json.Unmarshal(dto.TraceContext, &traceContext); err == nil && len(traceContext) > 0 {
+			job.TraceContext = traceContext
+		}
+	}
+
+	return job
+}
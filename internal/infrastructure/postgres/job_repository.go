@@ -0,0 +1,535 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+)
+
+// jobColumns is the list of columns selected when reading a job row.
+const jobColumns = `id, task_id, scheduled_at, started_at, finished_at, status, retry_count, available_at, trigger_source, trace_context, lease_expires_at, created_at, updated_at`
+
+// jobResultColumns is the list of columns selected when reading a job result row.
+const jobResultColumns = `job_id, task_id, attempt, status_code, response_headers, response_body, body_truncated, error, started_at, finished_at, duration_ms`
+
+// JobRepository is a PostgreSQL implementation of the JobRepository interface.
+// The jobs table itself acts as the queue: pending rows are claimed with
+// SELECT ... FOR UPDATE SKIP LOCKED so that multiple executor processes can
+// share one queue without running the same job twice.
+//
+// Dequeued jobs are leased for the visibility timeout. Running jobs whose lease
+// was not extended in time can be found with FindExpiredLeases.
+type JobRepository struct {
+	db                *sql.DB
+	visibilityTimeout time.Duration
+}
+
+// JobRepositoryOption configures a JobRepository.
+type JobRepositoryOption func(*JobRepository)
+
+// WithVisibilityTimeout sets the lease duration granted on Dequeue and ExtendLease.
+func WithVisibilityTimeout(d time.Duration) JobRepositoryOption {
+	return func(r *JobRepository) {
+		r.visibilityTimeout = d
+	}
+}
+
+// NewJobRepository creates a new PostgreSQL JobRepository.
+func NewJobRepository(db *sql.DB, opts ...JobRepositoryOption) *JobRepository {
+	r := &JobRepository{db: db, visibilityTimeout: domain.DefaultVisibilityTimeout}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Enqueue inserts a job into the queue. It returns domain.ErrConstraintViolation
+// if a job with the same ID, or with the same task ID and scheduled time, already exists.
+func (r *JobRepository) Enqueue(ctx context.Context, job *domain.Job) error {
+	dto := ToJobDTO(job)
+
+	query := `
+		INSERT INTO jobs (` + jobColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		dto.ID,
+		dto.TaskID,
+		dto.ScheduledAt,
+		dto.StartedAt,
+		dto.FinishedAt,
+		dto.Status,
+		dto.RetryCount,
+		dto.AvailableAt,
+		dto.TriggerSource,
+		dto.TraceContext,
+		dto.LeaseExpiresAt,
+		dto.CreatedAt,
+		dto.UpdatedAt,
+	)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			if pqErr.Code == "23505" { // unique_violation
+				return domain.ErrConstraintViolation
+			}
+		}
+		return fmt.Errorf("failed to enqueue job: %w", err)
+	}
+
+	return nil
+}
+
+// Dequeue atomically claims the oldest available pending job, marks it as Running
+// and leases it for the visibility timeout. Rows locked by other transactions are
+// skipped, so concurrent callers never receive the same job. It returns nil if no
+// pending job is available.
+func (r *JobRepository) Dequeue(ctx context.Context) (*domain.Job, error) {
+	now := time.Now().UTC()
+
+	query := `
+		UPDATE jobs
+		SET status = $1, started_at = $2, updated_at = $2, lease_expires_at = $4
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE status = $3 AND (available_at IS NULL OR available_at <= $2)
+			ORDER BY scheduled_at, created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + jobColumns
+
+	job, err := scanJob(r.db.QueryRowContext(ctx, query,
+		int(domain.JobStatusRunning),
+		now,
+		int(domain.JobStatusPending),
+		now.Add(r.visibilityTimeout),
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to dequeue job: %w", err)
+	}
+
+	return job, nil
+}
+
+// UpdateStatus updates the status of a job along with its timestamps.
+// Updating a job that does not exist or was cancelled is not an error.
+func (r *JobRepository) UpdateStatus(ctx context.Context, jobID string, status domain.JobStatus) error {
+	now := time.Now().UTC()
+
+	var query string
+	switch status {
+	case domain.JobStatusRunning:
+		query = `UPDATE jobs SET status = $2, started_at = $3, updated_at = $3 WHERE id = $1 AND status <> $4`
+	case domain.JobStatusSuccess, domain.JobStatusFailed, domain.JobStatusTimedOut:
+		query = `UPDATE jobs SET status = $2, finished_at = $3, updated_at = $3 WHERE id = $1 AND status <> $4`
+	default:
+		query = `UPDATE jobs SET status = $2, updated_at = $3 WHERE id = $1 AND status <> $4`
+	}
+
+	if _, err := r.db.ExecContext(ctx, query, jobID, int(status), now, int(domain.JobStatusCancelled)); err != nil {
+		return fmt.Errorf("failed to update job status: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID finds a job by its ID. It returns nil if the job does not exist.
+func (r *JobRepository) FindByID(ctx context.Context, jobID string) (*domain.Job, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE id = $1
+	`
+
+	job, err := scanJob(r.db.QueryRowContext(ctx, query, jobID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find job: %w", err)
+	}
+
+	return job, nil
+}
+
+// FindByTaskID returns up to limit jobs of a task, most recently scheduled first.
+func (r *JobRepository) FindByTaskID(ctx context.Context, taskID string, limit int) ([]*domain.Job, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE task_id = $1
+		ORDER BY scheduled_at DESC, created_at DESC
+		LIMIT $2
+	`
+	return r.queryJobs(ctx, query, taskID, limit)
+}
+
+// FindActiveByTaskID returns the pending or running jobs of a task, earliest
+// scheduled first.
+func (r *JobRepository) FindActiveByTaskID(ctx context.Context, taskID string) ([]*domain.Job, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE task_id = $1 AND status IN ($2, $3)
+		ORDER BY scheduled_at ASC, created_at ASC
+	`
+	return r.queryJobs(ctx, query, taskID, int(domain.JobStatusPending), int(domain.JobStatusRunning))
+}
+
+// CountPending returns the number of pending jobs, including jobs waiting for
+// a retry.
+func (r *JobRepository) CountPending(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM jobs WHERE status = $1`, int(domain.JobStatusPending)).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count pending jobs: %w", err)
+	}
+	return count, nil
+}
+
+// Cancel marks a pending or running job as cancelled. Cancelled jobs are no
+// longer dequeued, and their lease is released. Cancelling a finished or
+// missing job is not an error.
+func (r *JobRepository) Cancel(ctx context.Context, jobID string) error {
+	query := `
+		UPDATE jobs
+		SET status = $2, finished_at = $3, lease_expires_at = NULL, updated_at = $3
+		WHERE id = $1 AND status IN ($4, $5)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		jobID,
+		int(domain.JobStatusCancelled),
+		time.Now().UTC(),
+		int(domain.JobStatusPending),
+		int(domain.JobStatusRunning),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to cancel job: %w", err)
+	}
+
+	return nil
+}
+
+// Requeue stores the retry state of a job and returns it to Pending so that it
+// is dequeued again once available_at has passed. Cancelled jobs are left
+// untouched.
+func (r *JobRepository) Requeue(ctx context.Context, job *domain.Job) error {
+	dto := ToJobDTO(job)
+
+	query := `
+		UPDATE jobs
+		SET status = $2, retry_count = $3, available_at = $4,
+			started_at = NULL, finished_at = NULL, lease_expires_at = NULL, updated_at = $5
+		WHERE id = $1 AND status <> $6
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		dto.ID,
+		int(domain.JobStatusPending),
+		dto.RetryCount,
+		dto.AvailableAt,
+		time.Now().UTC(),
+		int(domain.JobStatusCancelled),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to requeue job: %w", err)
+	}
+
+	return nil
+}
+
+// ExtendLease extends the lease of a running job by the visibility timeout.
+// Extending the lease of a job that is not running is not an error.
+func (r *JobRepository) ExtendLease(ctx context.Context, jobID string) error {
+	now := time.Now().UTC()
+
+	query := `UPDATE jobs SET lease_expires_at = $3, updated_at = $2 WHERE id = $1 AND status = $4`
+	if _, err := r.db.ExecContext(ctx, query, jobID, now, now.Add(r.visibilityTimeout), int(domain.JobStatusRunning)); err != nil {
+		return fmt.Errorf("failed to extend job lease: %w", err)
+	}
+
+	return nil
+}
+
+// FindExpiredLeases returns up to limit running jobs whose lease expired before
+// now, ordered by lease expiration.
+func (r *JobRepository) FindExpiredLeases(ctx context.Context, now time.Time, limit int) ([]*domain.Job, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE status = $1 AND lease_expires_at < $2
+		ORDER BY lease_expires_at
+		LIMIT $3
+	`
+	return r.queryJobs(ctx, query, int(domain.JobStatusRunning), now.UTC(), limit)
+}
+
+// ReclaimExpired stores a job updated by the reaper, releasing its lease, only
+// if the row is still running with a lease that expired before now. A job
+// returned to Pending is dequeued again once available_at has passed.
+func (r *JobRepository) ReclaimExpired(ctx context.Context, job *domain.Job, now time.Time) (bool, error) {
+	dto := ToJobDTO(job)
+
+	query := `
+		UPDATE jobs
+		SET status = $2, retry_count = $3, available_at = $4, started_at = $5, finished_at = $6,
+			lease_expires_at = NULL, updated_at = $7
+		WHERE id = $1 AND status = $8 AND lease_expires_at < $9
+	`
+	result, err := r.db.ExecContext(ctx, query,
+		dto.ID,
+		dto.Status,
+		dto.RetryCount,
+		dto.AvailableAt,
+		dto.StartedAt,
+		dto.FinishedAt,
+		time.Now().UTC(),
+		int(domain.JobStatusRunning),
+		now.UTC(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to reclaim job: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to reclaim job: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// queryJobs runs a query selecting jobColumns and scans every row.
+func (r *JobRepository) queryJobs(ctx context.Context, query string, args ...any) ([]*domain.Job, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query jobs: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = fmt.Errorf("failed to close rows: %w", closeErr)
+		}
+	}()
+
+	jobs := make([]*domain.Job, 0)
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job row: %w", err)
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating job rows: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// SaveResult inserts the execution result of a job attempt.
+func (r *JobRepository) SaveResult(ctx context.Context, result *domain.JobResult) error {
+	dto, err := ToJobResultDTO(result)
+	if err != nil {
+		return fmt.Errorf("failed to convert job result to DTO: %w", err)
+	}
+
+	query := `
+		INSERT INTO job_results (` + jobResultColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+	_, err = r.db.ExecContext(ctx, query,
+		dto.JobID,
+		dto.TaskID,
+		dto.Attempt,
+		dto.StatusCode,
+		dto.ResponseHeaders,
+		dto.ResponseBody,
+		dto.BodyTruncated,
+		dto.Error,
+		dto.StartedAt,
+		dto.FinishedAt,
+		dto.DurationMS,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save job result: %w", err)
+	}
+
+	return nil
+}
+
+// FindResultsByJobID returns the execution results of a job ordered by attempt.
+func (r *JobRepository) FindResultsByJobID(ctx context.Context, jobID string) ([]*domain.JobResult, error) {
+	query := `
+		SELECT ` + jobResultColumns + `
+		FROM job_results
+		WHERE job_id = $1
+		ORDER BY attempt, id
+	`
+	return r.queryResults(ctx, query, jobID)
+}
+
+// FindRecentResultsByTaskID returns up to limit execution results of a task,
+// most recently finished first.
+func (r *JobRepository) FindRecentResultsByTaskID(ctx context.Context, taskID string, limit int) ([]*domain.JobResult, error) {
+	query := `
+		SELECT ` + jobResultColumns + `
+		FROM job_results
+		WHERE task_id = $1
+		ORDER BY finished_at DESC, id DESC
+		LIMIT $2
+	`
+	return r.queryResults(ctx, query, taskID, limit)
+}
+
+// DeleteFinished deletes up to limit finished jobs that match filter, together
+// with their results, in a single statement and returns the number of deleted
+// jobs. Jobs are ranked per task by scheduled time within the filter's
+// statuses, so KeepLatest keeps the most recent jobs of each task.
+func (r *JobRepository) DeleteFinished(ctx context.Context, filter domain.JobPruneFilter, limit int) (int, error) {
+	statuses := make([]int64, 0, len(filter.Statuses))
+	for _, status := range filter.Statuses {
+		// Unfinished jobs are never deleted, whatever the filter says
+		if (&domain.Job{Status: status}).IsFinished() {
+			statuses = append(statuses, int64(status))
+		}
+	}
+	if len(statuses) == 0 || (filter.FinishedBefore.IsZero() && filter.KeepLatest == 0) {
+		return 0, nil
+	}
+
+	var taskID sql.NullString
+	if filter.TaskID != "" {
+		taskID = sql.NullString{String: filter.TaskID, Valid: true}
+	}
+	var finishedBefore sql.NullTime
+	if !filter.FinishedBefore.IsZero() {
+		finishedBefore = sql.NullTime{Time: filter.FinishedBefore.UTC(), Valid: true}
+	}
+	excludeTaskIDs := filter.ExcludeTaskIDs
+	if excludeTaskIDs == nil {
+		excludeTaskIDs = []string{}
+	}
+
+	query := `
+		WITH candidates AS (
+			SELECT id, finished_at,
+				ROW_NUMBER() OVER (PARTITION BY task_id ORDER BY scheduled_at DESC) AS rank
+			FROM jobs
+			WHERE status = ANY($1)
+				AND ($2::uuid IS NULL OR task_id = $2::uuid)
+				AND NOT (task_id = ANY($3::uuid[]))
+		),
+		deleted AS (
+			DELETE FROM jobs
+			WHERE id IN (
+				SELECT id FROM candidates
+				WHERE ($4::timestamp IS NOT NULL AND finished_at < $4::timestamp)
+					OR ($5::bigint > 0 AND rank > $5::bigint)
+				LIMIT $6
+			)
+			RETURNING id
+		),
+		deleted_results AS (
+			DELETE FROM job_results WHERE job_id IN (SELECT id FROM deleted)
+		)
+		SELECT COUNT(*) FROM deleted
+	`
+	var deleted int
+	err := r.db.QueryRowContext(ctx, query,
+		pq.Array(statuses),
+		taskID,
+		pq.Array(excludeTaskIDs),
+		finishedBefore,
+		filter.KeepLatest,
+		limit,
+	).Scan(&deleted)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete finished jobs: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// queryResults runs a query selecting jobResultColumns and scans every row.
+func (r *JobRepository) queryResults(ctx context.Context, query string, args ...any) ([]*domain.JobResult, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query job results: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			err = fmt.Errorf("failed to close rows: %w", closeErr)
+		}
+	}()
+
+	results := make([]*domain.JobResult, 0)
+	for rows.Next() {
+		var dto JobResultDTO
+		err := rows.Scan(
+			&dto.JobID,
+			&dto.TaskID,
+			&dto.Attempt,
+			&dto.StatusCode,
+			&dto.ResponseHeaders,
+			&dto.ResponseBody,
+			&dto.BodyTruncated,
+			&dto.Error,
+			&dto.StartedAt,
+			&dto.FinishedAt,
+			&dto.DurationMS,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job result row: %w", err)
+		}
+
+		result, err := dto.ToDomain()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert DTO to job result: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating job result rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanJob scans a row selected with jobColumns into a domain Job.
+func scanJob(row rowScanner) (*domain.Job, error) {
+	var dto JobDTO
+	err := row.Scan(
+		&dto.ID,
+		&dto.TaskID,
+		&dto.ScheduledAt,
+		&dto.StartedAt,
+		&dto.FinishedAt,
+		&dto.Status,
+		&dto.RetryCount,
+		&dto.AvailableAt,
+		&dto.TriggerSource,
+		&dto.TraceContext,
+		&dto.LeaseExpiresAt,
+		&dto.CreatedAt,
+		&dto.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToDomain(), nil
+}
@@ -0,0 +1,566 @@
+package postgres_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/postgres"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/repotest"
+)
+
+func newPendingJob(scheduledAt time.Time) *domain.Job {
+	now := time.Now().UTC()
+	return &domain.Job{
+		ID:          uuid.NewString(),
+		TaskID:      uuid.NewString(),
+		ScheduledAt: scheduledAt,
+		Status:      domain.JobStatusPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+func TestJobRepository_EnqueueAndDequeue(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := postgres.NewJobRepository(db)
+	ctx := context.Background()
+
+	job := newPendingJob(time.Now().UTC())
+	job.TriggerSource = domain.TriggerSourceManual
+	err := repo.Enqueue(ctx, job)
+	require.NoError(t, err)
+
+	dequeued, err := repo.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, dequeued)
+	assert.Equal(t, job.ID, dequeued.ID)
+	assert.Equal(t, job.TaskID, dequeued.TaskID)
+	assert.WithinDuration(t, job.ScheduledAt, dequeued.ScheduledAt, time.Second)
+	assert.Equal(t, domain.JobStatusRunning, dequeued.Status, "dequeued job should be claimed as Running")
+	assert.Equal(t, domain.TriggerSourceManual, dequeued.TriggerSource)
+	assert.False(t, dequeued.StartedAt.IsZero())
+
+	// The claimed job must not be dequeued again
+	dequeued, err = repo.Dequeue(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, dequeued)
+}
+
+func TestJobRepository_Dequeue_Empty(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := postgres.NewJobRepository(db)
+
+	dequeued, err := repo.Dequeue(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, dequeued)
+}
+
+func TestJobRepository_Dequeue_OrderByScheduledAt(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := postgres.NewJobRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	later := newPendingJob(now)
+	earlier := newPendingJob(now.Add(-1 * time.Minute))
+	require.NoError(t, repo.Enqueue(ctx, later))
+	require.NoError(t, repo.Enqueue(ctx, earlier))
+
+	first, err := repo.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, first)
+	assert.Equal(t, earlier.ID, first.ID)
+
+	second, err := repo.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, second)
+	assert.Equal(t, later.ID, second.ID)
+}
+
+func TestJobRepository_Enqueue_DuplicateID(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := postgres.NewJobRepository(db)
+	ctx := context.Background()
+
+	job := newPendingJob(time.Now().UTC())
+	require.NoError(t, repo.Enqueue(ctx, job))
+
+	err := repo.Enqueue(ctx, job)
+	assert.ErrorIs(t, err, domain.ErrConstraintViolation)
+}
+
+func TestJobRepository_Enqueue_DuplicateSchedule(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := postgres.NewJobRepository(db)
+	ctx := context.Background()
+
+	job := newPendingJob(time.Now().UTC().Truncate(time.Minute))
+	require.NoError(t, repo.Enqueue(ctx, job))
+
+	// Same task and scheduled time with another ID, expressed in another time zone
+	jst := time.FixedZone("JST", 9*60*60)
+	duplicate := newPendingJob(job.ScheduledAt.In(jst))
+	duplicate.TaskID = job.TaskID
+	err := repo.Enqueue(ctx, duplicate)
+	assert.ErrorIs(t, err, domain.ErrConstraintViolation)
+}
+
+func TestJobRepository_UpdateStatus(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := postgres.NewJobRepository(db)
+	ctx := context.Background()
+
+	job := newPendingJob(time.Now().UTC())
+	require.NoError(t, repo.Enqueue(ctx, job))
+
+	dequeued, err := repo.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, dequeued)
+
+	err = repo.UpdateStatus(ctx, job.ID, domain.JobStatusSuccess)
+	assert.NoError(t, err)
+
+	var (
+		status     int
+		finishedAt *time.Time
+	)
+	err = db.QueryRowContext(ctx, "SELECT status, finished_at FROM jobs WHERE id = $1", job.ID).Scan(&status, &finishedAt)
+	require.NoError(t, err)
+	assert.Equal(t, int(domain.JobStatusSuccess), status)
+	assert.NotNil(t, finishedAt, "finished_at should be set when marking as Success")
+
+	// Updating a non-existent job should not error
+	err = repo.UpdateStatus(ctx, uuid.NewString(), domain.JobStatusFailed)
+	assert.NoError(t, err)
+}
+
+func TestJobRepository_ConcurrentDequeue(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := postgres.NewJobRepository(db)
+	ctx := context.Background()
+
+	const numJobs = 20
+	for i := 0; i < numJobs; i++ {
+		require.NoError(t, repo.Enqueue(ctx, newPendingJob(time.Now().UTC())))
+	}
+
+	// Run concurrent dequeues simulating multiple executor processes
+	const numWorkers = 5
+	var (
+		mu      sync.Mutex
+		claimed = make(map[string]int)
+		wg      sync.WaitGroup
+	)
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				job, err := repo.Dequeue(ctx)
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+				if job == nil {
+					return
+				}
+				mu.Lock()
+				claimed[job.ID]++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, claimed, numJobs, "every job should be claimed")
+	for id, count := range claimed {
+		assert.Equal(t, 1, count, "job %s should be claimed exactly once", id)
+	}
+}
+
+func TestJobRepository_Requeue(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := postgres.NewJobRepository(db)
+	ctx := context.Background()
+
+	job := newPendingJob(time.Now().UTC())
+	require.NoError(t, repo.Enqueue(ctx, job))
+
+	dequeued, err := repo.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, dequeued)
+
+	// Requeue with a delay: the job must not be dequeued before available_at
+	dequeued.ScheduleRetry(time.Now().Add(time.Hour))
+	require.NoError(t, repo.Requeue(ctx, dequeued))
+
+	notYet, err := repo.Dequeue(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, notYet)
+
+	// Requeue without a delay: the job is dequeued immediately
+	dequeued.ScheduleRetry(time.Now().Add(-time.Second))
+	require.NoError(t, repo.Requeue(ctx, dequeued))
+
+	retried, err := repo.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, retried)
+	assert.Equal(t, job.ID, retried.ID)
+	assert.Equal(t, 2, retried.RetryCount)
+}
+
+func TestJobRepository_Lease(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := postgres.NewJobRepository(db, postgres.WithVisibilityTimeout(time.Minute))
+	ctx := context.Background()
+
+	job := newPendingJob(time.Now().UTC())
+	require.NoError(t, repo.Enqueue(ctx, job))
+
+	dequeued, err := repo.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, dequeued)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), dequeued.LeaseExpiresAt, 5*time.Second)
+
+	// The stored job is Running and leased, not only the returned copy
+	stored, err := repo.FindByID(ctx, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, domain.JobStatusRunning, stored.Status)
+	assert.WithinDuration(t, dequeued.LeaseExpiresAt, stored.LeaseExpiresAt, time.Millisecond)
+
+	// The lease has not expired yet
+	expired, err := repo.FindExpiredLeases(ctx, time.Now(), 10)
+	require.NoError(t, err)
+	assert.Empty(t, expired)
+
+	// The lease expires unless it is extended
+	expired, err = repo.FindExpiredLeases(ctx, time.Now().Add(2*time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, expired, 1)
+	assert.Equal(t, job.ID, expired[0].ID)
+
+	// A heartbeat pushes the lease forward
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, repo.ExtendLease(ctx, job.ID))
+	expired, err = repo.FindExpiredLeases(ctx, dequeued.LeaseExpiresAt.Add(time.Millisecond), 10)
+	require.NoError(t, err)
+	assert.Empty(t, expired)
+
+	// Finished jobs are never expired
+	require.NoError(t, repo.UpdateStatus(ctx, job.ID, domain.JobStatusSuccess))
+	expired, err = repo.FindExpiredLeases(ctx, time.Now().Add(time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, expired)
+}
+
+func TestJobRepository_Results(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := postgres.NewJobRepository(db)
+	ctx := context.Background()
+
+	jobID := uuid.NewString()
+	taskID := uuid.NewString()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	first := &domain.JobResult{
+		JobID:           jobID,
+		TaskID:          taskID,
+		Attempt:         1,
+		StatusCode:      500,
+		ResponseHeaders: map[string]string{"Content-Type": "text/plain"},
+		ResponseBody:    []byte("internal error"),
+		Error:           "unexpected status code: 500",
+		StartedAt:       now.Add(-2*time.Minute - time.Second),
+		FinishedAt:      now.Add(-2 * time.Minute),
+		Duration:        time.Second,
+	}
+	second := &domain.JobResult{
+		JobID:           jobID,
+		TaskID:          taskID,
+		Attempt:         2,
+		StatusCode:      200,
+		ResponseHeaders: map[string]string{},
+		ResponseBody:    []byte("ok"),
+		BodyTruncated:   true,
+		StartedAt:       now.Add(-time.Minute),
+		FinishedAt:      now.Add(-time.Minute),
+	}
+	require.NoError(t, repo.SaveResult(ctx, second))
+	require.NoError(t, repo.SaveResult(ctx, first))
+
+	// Results of a job are ordered by attempt
+	results, err := repo.FindResultsByJobID(ctx, jobID)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, first.StatusCode, results[0].StatusCode)
+	assert.Equal(t, first.ResponseHeaders, results[0].ResponseHeaders)
+	assert.Equal(t, first.ResponseBody, results[0].ResponseBody)
+	assert.Equal(t, first.Error, results[0].Error)
+	assert.Equal(t, first.Duration, results[0].Duration)
+	assert.True(t, first.FinishedAt.Equal(results[0].FinishedAt))
+	assert.Equal(t, 2, results[1].Attempt)
+	assert.True(t, results[1].BodyTruncated)
+
+	// Recent results of a task are ordered from the most recently finished
+	results, err = repo.FindRecentResultsByTaskID(ctx, taskID, 1)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, 2, results[0].Attempt)
+
+	results, err = repo.FindResultsByJobID(ctx, uuid.NewString())
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestJobRepository_FindByIDAndTaskID(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := postgres.NewJobRepository(db)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	older := newPendingJob(now.Add(-time.Minute))
+	newer := newPendingJob(now)
+	newer.TaskID = older.TaskID
+	require.NoError(t, repo.Enqueue(ctx, older))
+	require.NoError(t, repo.Enqueue(ctx, newer))
+	require.NoError(t, repo.Enqueue(ctx, newPendingJob(now)))
+
+	job, err := repo.FindByID(ctx, older.ID)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, older.TaskID, job.TaskID)
+	assert.Equal(t, domain.JobStatusPending, job.Status)
+
+	job, err = repo.FindByID(ctx, uuid.NewString())
+	require.NoError(t, err)
+	assert.Nil(t, job)
+
+	// Jobs of a task are ordered from the most recently scheduled
+	jobs, err := repo.FindByTaskID(ctx, older.TaskID, 10)
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, newer.ID, jobs[0].ID)
+	assert.Equal(t, older.ID, jobs[1].ID)
+
+	jobs, err = repo.FindByTaskID(ctx, older.TaskID, 1)
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1)
+}
+
+func TestJobRepository_Cancel(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := postgres.NewJobRepository(db)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	running := newPendingJob(now.Add(-2 * time.Minute))
+	pending := newPendingJob(now.Add(-time.Minute))
+	pending.TaskID = running.TaskID
+	require.NoError(t, repo.Enqueue(ctx, running))
+	require.NoError(t, repo.Enqueue(ctx, pending))
+
+	dequeued, err := repo.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, dequeued)
+	require.Equal(t, running.ID, dequeued.ID)
+	require.NoError(t, repo.UpdateStatus(ctx, running.ID, domain.JobStatusRunning))
+
+	// Both unfinished jobs are active, earliest scheduled first
+	active, err := repo.FindActiveByTaskID(ctx, running.TaskID)
+	require.NoError(t, err)
+	require.Len(t, active, 2)
+	assert.Equal(t, running.ID, active[0].ID)
+	assert.Equal(t, pending.ID, active[1].ID)
+
+	require.NoError(t, repo.Cancel(ctx, running.ID))
+	require.NoError(t, repo.Cancel(ctx, pending.ID))
+	require.NoError(t, repo.Cancel(ctx, uuid.NewString()))
+
+	active, err = repo.FindActiveByTaskID(ctx, running.TaskID)
+	require.NoError(t, err)
+	assert.Empty(t, active)
+
+	// Cancelled jobs are not dequeued
+	dequeued, err = repo.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, dequeued)
+
+	// The status of a cancelled job does not change afterwards
+	require.NoError(t, repo.UpdateStatus(ctx, running.ID, domain.JobStatusSuccess))
+	job, err := repo.FindByID(ctx, running.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusCancelled, job.Status)
+	assert.False(t, job.FinishedAt.IsZero())
+	assert.True(t, job.LeaseExpiresAt.IsZero())
+
+	job.ScheduleRetry(time.Time{})
+	require.NoError(t, repo.Requeue(ctx, job))
+	job, err = repo.FindByID(ctx, running.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusCancelled, job.Status)
+}
+
+func TestJobRepository_CountPending(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := postgres.NewJobRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	require.NoError(t, repo.Enqueue(ctx, newPendingJob(now)))
+	require.NoError(t, repo.Enqueue(ctx, newPendingJob(now.Add(time.Minute))))
+	count, err := repo.CountPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	// A dequeued job is no longer pending, but it is counted again while waiting for a retry
+	dequeued, err := repo.Dequeue(ctx)
+	require.NoError(t, err)
+	count, err = repo.CountPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	dequeued.ScheduleRetry(now.Add(time.Hour))
+	require.NoError(t, repo.Requeue(ctx, dequeued))
+	count, err = repo.CountPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestJobRepository_DeleteFinished(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := postgres.NewJobRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	newFinishedJob := func(taskID string, age time.Duration, status domain.JobStatus) *domain.Job {
+		job := newPendingJob(now.Add(-age))
+		job.TaskID = taskID
+		job.Status = status
+		job.FinishedAt = now.Add(-age)
+		require.NoError(t, repo.Enqueue(ctx, job))
+		return job
+	}
+
+	taskA, taskB := uuid.NewString(), uuid.NewString()
+	oldSuccess := newFinishedJob(taskA, 3*time.Hour, domain.JobStatusSuccess)
+	recentSuccess := newFinishedJob(taskA, time.Minute, domain.JobStatusSuccess)
+	oldFailure := newFinishedJob(taskA, 150*time.Minute, domain.JobStatusFailed)
+	otherTask := newFinishedJob(taskB, 3*time.Hour, domain.JobStatusSuccess)
+	pending := newPendingJob(now.Add(-4 * time.Hour))
+	pending.TaskID = taskA
+	require.NoError(t, repo.Enqueue(ctx, pending))
+	require.NoError(t, repo.SaveResult(ctx, &domain.JobResult{
+		JobID: oldSuccess.ID, TaskID: taskA, Attempt: 1, StartedAt: now, FinishedAt: now,
+	}))
+
+	// Only the old completed job of task A is older than an hour
+	filter := domain.NewJobPruneFilter(domain.RetentionRule{MaxAge: time.Hour}, domain.CompletedJobStatuses, now)
+	filter.TaskID = taskA
+	deleted, err := repo.DeleteFinished(ctx, filter, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	for _, job := range []*domain.Job{recentSuccess, oldFailure, otherTask, pending} {
+		found, err := repo.FindByID(ctx, job.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, found, "job %s should be kept", job.ID)
+	}
+	found, err := repo.FindByID(ctx, oldSuccess.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+	results, err := repo.FindResultsByJobID(ctx, oldSuccess.ID)
+	require.NoError(t, err)
+	assert.Empty(t, results, "results of a deleted job are deleted with it")
+
+	// Keeping the latest job of every task except task A deletes nothing, since task B has a single job
+	filter = domain.NewJobPruneFilter(domain.RetentionRule{MaxJobs: 1}, domain.CompletedJobStatuses, now)
+	filter.ExcludeTaskIDs = []string{taskA}
+	deleted, err = repo.DeleteFinished(ctx, filter, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 0, deleted)
+}
+
+func TestJobRepository_ReclaimExpired_Shared(t *testing.T) {
+	repotest.TestReclaimExpired(t, func(t *testing.T) domain.JobRepository {
+		db, cleanup := setupTestDB(t)
+		t.Cleanup(cleanup)
+		return postgres.NewJobRepository(db)
+	})
+}
+
+func TestJobRepository_DeleteFinished_Shared(t *testing.T) {
+	repotest.TestDeleteFinished(t, func(t *testing.T) repotest.JobHistoryRepository {
+		db, cleanup := setupTestDB(t)
+		t.Cleanup(cleanup)
+		return postgres.NewJobRepository(db)
+	})
+}
+
+func TestJobRepository_DeleteFinished_KeepLatestInBatches(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := postgres.NewJobRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	taskID := uuid.NewString()
+	var jobs []*domain.Job
+	for i := range 5 {
+		job := newPendingJob(now.Add(time.Duration(i) * time.Minute))
+		job.TaskID = taskID
+		job.Status = domain.JobStatusFailed
+		job.FinishedAt = now
+		require.NoError(t, repo.Enqueue(ctx, job))
+		jobs = append(jobs, job)
+	}
+
+	filter := domain.NewJobPruneFilter(domain.RetentionRule{MaxJobs: 2}, domain.FailedJobStatuses, now)
+	deleted, err := repo.DeleteFinished(ctx, filter, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+	deleted, err = repo.DeleteFinished(ctx, filter, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	remaining, err := repo.FindByTaskID(ctx, taskID, 10)
+	require.NoError(t, err)
+	require.Len(t, remaining, 2)
+	assert.Equal(t, jobs[4].ID, remaining[0].ID)
+	assert.Equal(t, jobs[3].ID, remaining[1].ID)
+}
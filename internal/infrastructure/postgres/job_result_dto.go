@@ -0,0 +1,73 @@
+package postgres
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+)
+
+// JobResultDTO represents the database row structure for a JobResult.
+type JobResultDTO struct {
+	JobID           string    `db:"job_id"`
+	TaskID          string    `db:"task_id"`
+	Attempt         int       `db:"attempt"`
+	StatusCode      int       `db:"status_code"`
+	ResponseHeaders []byte    `db:"response_headers"`
+	ResponseBody    []byte    `db:"response_body"`
+	BodyTruncated   bool      `db:"body_truncated"`
+	Error           string    `db:"error"`
+	StartedAt       time.Time `db:"started_at"`
+	FinishedAt      time.Time `db:"finished_at"`
+	DurationMS      int64     `db:"duration_ms"`
+}
+
+// ToJobResultDTO converts a domain JobResult to a JobResultDTO.
+func ToJobResultDTO(result *domain.JobResult) (*JobResultDTO, error) {
+	headers := result.ResponseHeaders
+	if headers == nil {
+		headers = map[string]string{}
+	}
+	headersBytes, err := json.Marshal(headers)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JobResultDTO{
+		JobID:           result.JobID,
+		TaskID:          result.TaskID,
+		Attempt:         result.Attempt,
+		StatusCode:      result.StatusCode,
+		ResponseHeaders: headersBytes,
+		ResponseBody:    result.ResponseBody,
+		BodyTruncated:   result.BodyTruncated,
+		Error:           result.Error,
+		StartedAt:       result.StartedAt.UTC(),
+		FinishedAt:      result.FinishedAt.UTC(),
+		DurationMS:      result.Duration.Milliseconds(),
+	}, nil
+}
+
+// ToDomain converts a JobResultDTO to a domain JobResult.
+func (dto *JobResultDTO) ToDomain() (*domain.JobResult, error) {
+	var headers map[string]string
+	if len(dto.ResponseHeaders) > 0 {
+		if err := json.Unmarshal(dto.ResponseHeaders, &headers); err != nil {
+			return nil, err
+		}
+	}
+
+	return &domain.JobResult{
+		JobID:           dto.JobID,
+		TaskID:          dto.TaskID,
+		Attempt:         dto.Attempt,
+		StatusCode:      dto.StatusCode,
+		ResponseHeaders: headers,
+		ResponseBody:    dto.ResponseBody,
+		BodyTruncated:   dto.BodyTruncated,
+		Error:           dto.Error,
+		StartedAt:       dto.StartedAt,
+		FinishedAt:      dto.FinishedAt,
+		Duration:        time.Duration(dto.DurationMS) * time.Millisecond,
+	}, nil
+}
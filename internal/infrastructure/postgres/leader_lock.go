@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"sync"
+)
+
+// DefaultLeaderLockKey is the advisory lock key used for scheduler leader election.
+const DefaultLeaderLockKey int64 = 0x5363686564756c65 // "Schedule"
+
+// LeaderLock is a PostgreSQL implementation of the LeaderLock interface based on
+// a session-level advisory lock. The lock is held on a dedicated connection, so
+// PostgreSQL releases it automatically when the leader process dies and its
+// connection is closed.
+type LeaderLock struct {
+	db  *sql.DB
+	key int64
+
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+// NewLeaderLock creates a new PostgreSQL LeaderLock for the given advisory lock key.
+func NewLeaderLock(db *sql.DB, key int64) *LeaderLock {
+	return &LeaderLock{db: db, key: key}
+}
+
+// TryAcquire tries to acquire the advisory lock without blocking. If the lock is
+// already held, it verifies that the connection holding it is still alive.
+func (l *LeaderLock) TryAcquire(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		if err := l.conn.PingContext(ctx); err != nil {
+			// The session is gone, and the advisory lock with it
+			discardConn(l.conn)
+			l.conn = nil
+			return false, fmt.Errorf("lost leader lock connection: %w", err)
+		}
+		return true, nil
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return false, fmt.Errorf("failed to acquire advisory lock: %w", err)
+	}
+	if !acquired {
+		_ = conn.Close()
+		return false, nil
+	}
+
+	l.conn = conn
+	return true, nil
+}
+
+// Release releases the advisory lock if it is held.
+func (l *LeaderLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+	conn := l.conn
+	l.conn = nil
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key); err != nil {
+		// The session may still hold the lock, so it must not go back to the pool
+		discardConn(conn)
+		return fmt.Errorf("failed to release advisory lock: %w", err)
+	}
+
+	return conn.Close()
+}
+
+// discardConn closes the underlying connection instead of returning it to the
+// pool, which ends the session and releases any advisory lock it holds.
+func discardConn(conn *sql.Conn) {
+	_ = conn.Raw(func(any) error {
+		return driver.ErrBadConn
+	})
+	_ = conn.Close()
+}
@@ -0,0 +1,43 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/postgres"
+)
+
+func TestLeaderLock_OnlyOneHolder(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	const key int64 = 42
+	first := postgres.NewLeaderLock(db, key)
+	second := postgres.NewLeaderLock(db, key)
+	defer func() {
+		_ = first.Release(ctx)
+		_ = second.Release(ctx)
+	}()
+
+	acquired, err := first.TryAcquire(ctx)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = second.TryAcquire(ctx)
+	require.NoError(t, err)
+	assert.False(t, acquired, "the lock is held by another session")
+
+	// Renewing keeps the lock
+	acquired, err = first.TryAcquire(ctx)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	// After release, the other candidate can take over
+	require.NoError(t, first.Release(ctx))
+	acquired, err = second.TryAcquire(ctx)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+}
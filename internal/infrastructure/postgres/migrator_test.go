@@ -0,0 +1,227 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourname/go-dist-scheduler/db/migrations"
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/postgres"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000002_add_column.up.sql":     {Data: []byte("ALTER TABLE t ADD COLUMN c INTEGER;")},
+		"000002_add_column.down.sql":   {Data: []byte("ALTER TABLE t DROP COLUMN c;")},
+		"000001_create_table.up.sql":   {Data: []byte("CREATE TABLE t (id INTEGER);")},
+		"000001_create_table.down.sql": {Data: []byte("DROP TABLE t;")},
+	}
+
+	got, err := postgres.LoadMigrations(fsys)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, postgres.Migration{
+		Version: 1,
+		Name:    "create_table",
+		Up:      "CREATE TABLE t (id INTEGER);",
+		Down:    "DROP TABLE t;",
+	}, got[0])
+	assert.Equal(t, int64(2), got[1].Version)
+	assert.Equal(t, "000002_add_column", got[1].String())
+}
+
+func TestLoadMigrations_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		fsys     fstest.MapFS
+		expected string
+	}{
+		{
+			name: "invalid file name",
+			fsys: fstest.MapFS{
+				"create_table.sql": {Data: []byte("CREATE TABLE t (id INTEGER);")},
+			},
+			expected: "invalid migration file name",
+		},
+		{
+			name: "missing down file",
+			fsys: fstest.MapFS{
+				"000001_create_table.up.sql": {Data: []byte("CREATE TABLE t (id INTEGER);")},
+			},
+			expected: "must have both an up and a down file",
+		},
+		{
+			name: "conflicting names",
+			fsys: fstest.MapFS{
+				"000001_create_table.up.sql": {Data: []byte("CREATE TABLE t (id INTEGER);")},
+				"000001_drop_table.down.sql": {Data: []byte("DROP TABLE t;")},
+			},
+			expected: "conflicting names",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := postgres.LoadMigrations(tt.fsys)
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	got, err := postgres.LoadMigrations(migrations.FS)
+	require.NoError(t, err)
+	require.NotEmpty(t, got)
+
+	for i, m := range got {
+		assert.Equal(t, int64(i+1), m.Version, "versions must be contiguous starting at 1")
+	}
+}
+
+func TestMigrator_UpDownStatus(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	all, err := postgres.LoadMigrations(migrations.FS)
+	require.NoError(t, err)
+	migrator := postgres.NewMigrator(db, all)
+
+	// Bring the schema up to date; a second run has nothing to apply
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, len(all))
+	for _, s := range statuses {
+		assert.True(t, s.IsApplied(), "%s should be applied", s.Migration)
+	}
+
+	// Roll back the latest migration and apply it again
+	latest := all[len(all)-1]
+	rolledBack, err := migrator.Down(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, rolledBack)
+	assert.Equal(t, latest.Version, rolledBack.Version)
+
+	statuses, err = migrator.Status(ctx)
+	require.NoError(t, err)
+	assert.False(t, statuses[len(statuses)-1].IsApplied())
+
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, latest.Version, applied[0].Version)
+}
+
+// baselineSchema is the schema of a deployment from before the migration
+// runner, when the tasks and jobs tables only had their original columns.
+const baselineSchema = `
+CREATE TABLE tasks (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    cron_expression VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+    status INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_checked_at TIMESTAMP NULL
+);
+
+CREATE TABLE jobs (
+    id UUID PRIMARY KEY,
+    task_id UUID NOT NULL,
+    scheduled_at TIMESTAMP NOT NULL,
+    started_at TIMESTAMP NULL,
+    finished_at TIMESTAMP NULL,
+    status INTEGER NOT NULL DEFAULT 0,
+    retry_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+`
+
+func TestMigrator_UpgradesBaselineSchema(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	all, err := postgres.LoadMigrations(migrations.FS)
+	require.NoError(t, err)
+	migrator := postgres.NewMigrator(db, all)
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+
+	// Recreate a deployment from before the migration runner: the tables
+	// exist with their original columns, and no migration is recorded
+	for {
+		rolledBack, err := migrator.Down(ctx)
+		require.NoError(t, err)
+		if rolledBack == nil {
+			break
+		}
+	}
+	_, err = db.ExecContext(ctx, baselineSchema)
+	require.NoError(t, err)
+
+	// Every column added since is created even though the tables already exist
+	_, err = migrator.Up(ctx)
+	require.NoError(t, err)
+
+	repo := postgres.NewTaskRepository(db)
+	task := &domain.Task{
+		ID:                uuid.NewString(),
+		Name:              "Baseline Task",
+		CronExpression:    "0 9 * * *",
+		Timezone:          "Asia/Tokyo",
+		Payload:           domain.HTTPRequestInfo{URL: "http://example.com", Method: "POST"},
+		Status:            domain.TaskStatusActive,
+		ConcurrencyPolicy: domain.ConcurrencyForbid,
+		Timeout:           time.Minute,
+		CreatedAt:         time.Now().UTC(),
+		UpdatedAt:         time.Now().UTC(),
+	}
+	require.NoError(t, repo.Save(ctx, task))
+	saved, err := repo.FindByID(ctx, task.ID)
+	require.NoError(t, err)
+	require.NotNil(t, saved)
+	assert.Equal(t, "Asia/Tokyo", saved.Timezone)
+	assert.Equal(t, domain.ConcurrencyForbid, saved.ConcurrencyPolicy)
+	assert.Equal(t, time.Minute, saved.Timeout)
+
+	jobRepo := postgres.NewJobRepository(db)
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	job := &domain.Job{
+		ID:            uuid.NewString(),
+		TaskID:        task.ID,
+		ScheduledAt:   now,
+		Status:        domain.JobStatusPending,
+		TriggerSource: domain.TriggerSourceManual,
+		TraceContext:  map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	require.NoError(t, jobRepo.Enqueue(ctx, job))
+
+	// The unique constraint on (task_id, scheduled_at) is in place
+	duplicate := *job
+	duplicate.ID = uuid.NewString()
+	assert.ErrorIs(t, jobRepo.Enqueue(ctx, &duplicate), domain.ErrConstraintViolation)
+
+	dequeued, err := jobRepo.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, dequeued)
+	assert.Equal(t, job.ID, dequeued.ID)
+	assert.Equal(t, domain.TriggerSourceManual, dequeued.TriggerSource)
+	assert.Equal(t, job.TraceContext, dequeued.TraceContext)
+	assert.False(t, dequeued.LeaseExpiresAt.IsZero())
+}
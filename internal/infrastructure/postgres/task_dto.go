@@ -11,14 +11,22 @@ import (
 
 // TaskDTO represents the database row structure for a Task.
 type TaskDTO struct {
-	ID             string         `db:"id"`
-	Name           string         `db:"name"`
-	CronExpression string         `db:"cron_expression"`
-	Payload        []byte         `db:"payload"`
-	Status         int            `db:"status"`
-	CreatedAt      time.Time      `db:"created_at"`
-	UpdatedAt      time.Time      `db:"updated_at"`
-	LastCheckedAt  sql.NullTime   `db:"last_checked_at"`
+	ID                string       `db:"id"`
+	Name              string       `db:"name"`
+	CronExpression    string       `db:"cron_expression"`
+	Timezone          string       `db:"timezone"`
+	Payload           []byte       `db:"payload"`
+	Status            int          `db:"status"`
+	RetryPolicy       []byte       `db:"retry_policy"`
+	MisfirePolicy     []byte       `db:"misfire_policy"`
+	ConcurrencyPolicy int          `db:"concurrency_policy"`
+	TimeoutMS         int64        `db:"timeout_ms"`
+	StatusHistory     []byte       `db:"status_history"`
+	CreatedAt         time.Time    `db:"created_at"`
+	UpdatedAt         time.Time    `db:"updated_at"`
+	LastCheckedAt     sql.NullTime `db:"last_checked_at"`
+	RetentionPolicy   []byte       `db:"retention_policy"`
+	Version           int64        `db:"version"`
 }
 
 // payloadJSON represents the JSON structure stored in the payload column.
@@ -27,6 +35,50 @@ type payloadJSON struct {
 	Method  string            `json:"method"`
 	Headers map[string]string `json:"headers"`
 	Body    string            `json:"body"` // base64-encoded
+	Signing *signingJSON      `json:"signing,omitempty"`
+}
+
+// signingJSON represents the signing settings stored in the payload column.
+type signingJSON struct {
+	Secrets         []string `json:"secrets"`
+	SignatureHeader string   `json:"signature_header,omitempty"`
+	TimestampHeader string   `json:"timestamp_header,omitempty"`
+}
+
+// retryPolicyJSON represents the JSON structure stored in the retry_policy column.
+type retryPolicyJSON struct {
+	MaxRetries        int     `json:"max_retries"`
+	Backoff           int     `json:"backoff"`
+	InitialIntervalMS int64   `json:"initial_interval_ms"`
+	MaxIntervalMS     int64   `json:"max_interval_ms"`
+	Jitter            float64 `json:"jitter"`
+}
+
+// misfirePolicyJSON represents the JSON structure stored in the misfire_policy column.
+type misfirePolicyJSON struct {
+	Strategy int   `json:"strategy"`
+	MaxRuns  int   `json:"max_runs"`
+	MaxAgeMS int64 `json:"max_age_ms"`
+}
+
+// retentionRuleJSON represents one rule of the JSON structure stored in the retention_policy column.
+type retentionRuleJSON struct {
+	MaxAgeMS int64 `json:"max_age_ms"`
+	MaxJobs  int   `json:"max_jobs"`
+}
+
+// retentionPolicyJSON represents the JSON structure stored in the retention_policy column.
+type retentionPolicyJSON struct {
+	Completed retentionRuleJSON `json:"completed"`
+	Failed    retentionRuleJSON `json:"failed"`
+}
+
+// statusChangeJSON represents one entry of the JSON array stored in the status_history column.
+type statusChangeJSON struct {
+	Status    int       `json:"status"`
+	Actor     string    `json:"actor"`
+	Reason    string    `json:"reason,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
 }
 
 // ToDTO converts a domain Task to a TaskDTO.
@@ -38,20 +90,87 @@ func ToDTO(task *domain.Task) (*TaskDTO, error) {
 		Headers: task.Payload.Headers,
 		Body:    base64.StdEncoding.EncodeToString(task.Payload.Body),
 	}
+	if task.Payload.Signing.Enabled() {
+		payload.Signing = &signingJSON{
+			Secrets:         task.Payload.Signing.Secrets,
+			SignatureHeader: task.Payload.Signing.SignatureHeader,
+			TimestampHeader: task.Payload.Signing.TimestampHeader,
+		}
+	}
 
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
 		return nil, err
 	}
 
+	// Convert RetryPolicy to JSON
+	retryPolicyBytes, err := json.Marshal(retryPolicyJSON{
+		MaxRetries:        task.RetryPolicy.MaxRetries,
+		Backoff:           int(task.RetryPolicy.Backoff),
+		InitialIntervalMS: task.RetryPolicy.InitialInterval.Milliseconds(),
+		MaxIntervalMS:     task.RetryPolicy.MaxInterval.Milliseconds(),
+		Jitter:            task.RetryPolicy.Jitter,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert MisfirePolicy to JSON
+	misfirePolicyBytes, err := json.Marshal(misfirePolicyJSON{
+		Strategy: int(task.MisfirePolicy.Strategy),
+		MaxRuns:  task.MisfirePolicy.MaxRuns,
+		MaxAgeMS: task.MisfirePolicy.MaxAge.Milliseconds(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert RetentionPolicy to JSON
+	retentionPolicyBytes, err := json.Marshal(retentionPolicyJSON{
+		Completed: retentionRuleJSON{
+			MaxAgeMS: task.RetentionPolicy.Completed.MaxAge.Milliseconds(),
+			MaxJobs:  task.RetentionPolicy.Completed.MaxJobs,
+		},
+		Failed: retentionRuleJSON{
+			MaxAgeMS: task.RetentionPolicy.Failed.MaxAge.Milliseconds(),
+			MaxJobs:  task.RetentionPolicy.Failed.MaxJobs,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Convert StatusHistory to JSON
+	statusHistory := make([]statusChangeJSON, 0, len(task.StatusHistory))
+	for _, change := range task.StatusHistory {
+		statusHistory = append(statusHistory, statusChangeJSON{
+			Status:    int(change.Status),
+			Actor:     change.Actor,
+			Reason:    change.Reason,
+			ChangedAt: change.ChangedAt,
+		})
+	}
+	statusHistoryBytes, err := json.Marshal(statusHistory)
+	if err != nil {
+		return nil, err
+	}
+
 	dto := &TaskDTO{
-		ID:             task.ID,
-		Name:           task.Name,
-		CronExpression: task.CronExpression,
-		Payload:        payloadBytes,
-		Status:         int(task.Status),
-		CreatedAt:      task.CreatedAt,
-		UpdatedAt:      task.UpdatedAt,
+		ID:                task.ID,
+		Name:              task.Name,
+		CronExpression:    task.CronExpression,
+		Timezone:          task.Timezone,
+		Payload:           payloadBytes,
+		Status:            int(task.Status),
+		RetryPolicy:       retryPolicyBytes,
+		MisfirePolicy:     misfirePolicyBytes,
+		ConcurrencyPolicy: int(task.ConcurrencyPolicy),
+		TimeoutMS:         task.Timeout.Milliseconds(),
+		StatusHistory:     statusHistoryBytes,
+		RetentionPolicy:   retentionPolicyBytes,
+		CreatedAt:         task.CreatedAt,
+		UpdatedAt:         task.UpdatedAt,
+		Version:           task.Version,
 	}
 
 	if !task.LastCheckedAt.IsZero() {
@@ -79,19 +198,94 @@ func (dto *TaskDTO) ToDomain() (*domain.Task, error) {
 		}
 	}
 
+	// Parse JSON retry policy
+	var retryPolicy retryPolicyJSON
+	if len(dto.RetryPolicy) > 0 {
+		if err := json.Unmarshal(dto.RetryPolicy, &retryPolicy); err != nil {
+			return nil, err
+		}
+	}
+
+	// Parse JSON misfire policy
+	var misfirePolicy misfirePolicyJSON
+	if len(dto.MisfirePolicy) > 0 {
+		if err := json.Unmarshal(dto.MisfirePolicy, &misfirePolicy); err != nil {
+			return nil, err
+		}
+	}
+
+	// Parse JSON retention policy
+	var retentionPolicy retentionPolicyJSON
+	if len(dto.RetentionPolicy) > 0 {
+		if err := json.Unmarshal(dto.RetentionPolicy, &retentionPolicy); err != nil {
+			return nil, err
+		}
+	}
+
+	// Parse JSON status history
+	var statusHistory []statusChangeJSON
+	if len(dto.StatusHistory) > 0 {
+		if err := json.Unmarshal(dto.StatusHistory, &statusHistory); err != nil {
+			return nil, err
+		}
+	}
+
 	task := &domain.Task{
 		ID:             dto.ID,
 		Name:           dto.Name,
 		CronExpression: dto.CronExpression,
+		Timezone:       dto.Timezone,
 		Payload: domain.HTTPRequestInfo{
 			URL:     payload.URL,
 			Method:  payload.Method,
 			Headers: payload.Headers,
 			Body:    body,
 		},
-		Status:    domain.TaskStatus(dto.Status),
+		Status: domain.TaskStatus(dto.Status),
+		RetryPolicy: domain.RetryPolicy{
+			MaxRetries:      retryPolicy.MaxRetries,
+			Backoff:         domain.BackoffStrategy(retryPolicy.Backoff),
+			InitialInterval: time.Duration(retryPolicy.InitialIntervalMS) * time.Millisecond,
+			MaxInterval:     time.Duration(retryPolicy.MaxIntervalMS) * time.Millisecond,
+			Jitter:          retryPolicy.Jitter,
+		},
+		MisfirePolicy: domain.MisfirePolicy{
+			Strategy: domain.MisfireStrategy(misfirePolicy.Strategy),
+			MaxRuns:  misfirePolicy.MaxRuns,
+			MaxAge:   time.Duration(misfirePolicy.MaxAgeMS) * time.Millisecond,
+		},
+		ConcurrencyPolicy: domain.ConcurrencyPolicy(dto.ConcurrencyPolicy),
+		Timeout:           time.Duration(dto.TimeoutMS) * time.Millisecond,
+		RetentionPolicy: domain.RetentionPolicy{
+			Completed: domain.RetentionRule{
+				MaxAge:  time.Duration(retentionPolicy.Completed.MaxAgeMS) * time.Millisecond,
+				MaxJobs: retentionPolicy.Completed.MaxJobs,
+			},
+			Failed: domain.RetentionRule{
+				MaxAge:  time.Duration(retentionPolicy.Failed.MaxAgeMS) * time.Millisecond,
+				MaxJobs: retentionPolicy.Failed.MaxJobs,
+			},
+		},
 		CreatedAt: dto.CreatedAt,
 		UpdatedAt: dto.UpdatedAt,
+		Version:   dto.Version,
+	}
+
+	if payload.Signing != nil {
+		task.Payload.Signing = domain.SigningPolicy{
+			Secrets:         payload.Signing.Secrets,
+			SignatureHeader: payload.Signing.SignatureHeader,
+			TimestampHeader: payload.Signing.TimestampHeader,
+		}
+	}
+
+	for _, change := range statusHistory {
+		task.StatusHistory = append(task.StatusHistory, domain.TaskStatusChange{
+			Status:    domain.TaskStatus(change.Status),
+			Actor:     change.Actor,
+			Reason:    change.Reason,
+			ChangedAt: change.ChangedAt,
+		})
 	}
 
 	if dto.LastCheckedAt.Valid {
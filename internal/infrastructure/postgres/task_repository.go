@@ -5,11 +5,15 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/lib/pq"
 	"github.com/yourname/go-dist-scheduler/internal/domain"
 )
 
+// taskColumns is the list of columns selected when reading a task row.
+const taskColumns = `id, name, cron_expression, timezone, payload, status, retry_policy, misfire_policy, concurrency_policy, timeout_ms, status_history, created_at, updated_at, last_checked_at, retention_policy, version`
+
 // TaskRepository is a PostgreSQL implementation of the TaskRepository interface.
 type TaskRepository struct {
 	db *sql.DB
@@ -20,7 +24,9 @@ func NewTaskRepository(db *sql.DB) *TaskRepository {
 	return &TaskRepository{db: db}
 }
 
-// Save saves a task to the database using pessimistic locking.
+// Save saves a task to the database using pessimistic locking. Saving an
+// existing task whose version differs from task.Version returns
+// domain.ErrConflict, so that a stale copy never overwrites a newer update.
 func (r *TaskRepository) Save(ctx context.Context, task *domain.Task) error {
 	dto, err := ToDTO(task)
 	if err != nil {
@@ -37,45 +43,74 @@ func (r *TaskRepository) Save(ctx context.Context, task *domain.Task) error {
 	}()
 
 	// Use SELECT ... FOR UPDATE to acquire pessimistic lock on the row
-	var lockedID sql.NullString
-	err = tx.QueryRowContext(ctx, "SELECT id FROM tasks WHERE id = $1 FOR UPDATE", dto.ID).Scan(&lockedID)
+	var storedVersion sql.NullInt64
+	err = tx.QueryRowContext(ctx, "SELECT version FROM tasks WHERE id = $1 FOR UPDATE", dto.ID).Scan(&storedVersion)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		return fmt.Errorf("failed to lock task: %w", err)
 	}
 
-	if lockedID.Valid {
-		// Update existing task
+	var (
+		version       int64
+		lastCheckedAt sql.NullTime
+	)
+	if storedVersion.Valid {
+		if storedVersion.Int64 != dto.Version {
+			return fmt.Errorf("%w: task %s has been modified", domain.ErrConflict, dto.ID)
+		}
+
+		// Update existing task. last_checked_at is owned by UpdateLastCheckedAt
+		// and never moves backwards (GREATEST ignores NULL).
 		query := `
 			UPDATE tasks
-			SET name = $2, cron_expression = $3, payload = $4, status = $5,
-				updated_at = $6, last_checked_at = $7
+			SET name = $2, cron_expression = $3, timezone = $4, payload = $5, status = $6,
+				retry_policy = $7, misfire_policy = $8, concurrency_policy = $9,
+				timeout_ms = $10, status_history = $11, updated_at = $12,
+				last_checked_at = GREATEST(last_checked_at, $13),
+				retention_policy = $14, version = version + 1
 			WHERE id = $1
+			RETURNING version, last_checked_at
 		`
-		_, err = tx.ExecContext(ctx, query,
+		err = tx.QueryRowContext(ctx, query,
 			dto.ID,
 			dto.Name,
 			dto.CronExpression,
+			dto.Timezone,
 			dto.Payload,
 			dto.Status,
+			dto.RetryPolicy,
+			dto.MisfirePolicy,
+			dto.ConcurrencyPolicy,
+			dto.TimeoutMS,
+			dto.StatusHistory,
 			dto.UpdatedAt,
 			dto.LastCheckedAt,
-		)
+			dto.RetentionPolicy,
+		).Scan(&version, &lastCheckedAt)
 	} else {
 		// Insert new task
 		query := `
-			INSERT INTO tasks (id, name, cron_expression, payload, status, created_at, updated_at, last_checked_at)
-			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			INSERT INTO tasks (` + taskColumns + `)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+			RETURNING version, last_checked_at
 		`
-		_, err = tx.ExecContext(ctx, query,
+		err = tx.QueryRowContext(ctx, query,
 			dto.ID,
 			dto.Name,
 			dto.CronExpression,
+			dto.Timezone,
 			dto.Payload,
 			dto.Status,
+			dto.RetryPolicy,
+			dto.MisfirePolicy,
+			dto.ConcurrencyPolicy,
+			dto.TimeoutMS,
+			dto.StatusHistory,
 			dto.CreatedAt,
 			dto.UpdatedAt,
 			dto.LastCheckedAt,
-		)
+			dto.RetentionPolicy,
+			dto.Version+1,
+		).Scan(&version, &lastCheckedAt)
 	}
 
 	if err != nil {
@@ -94,28 +129,36 @@ func (r *TaskRepository) Save(ctx context.Context, task *domain.Task) error {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	task.Version = version
+	if lastCheckedAt.Valid {
+		task.LastCheckedAt = lastCheckedAt.Time
+	}
+	return nil
+}
+
+// UpdateLastCheckedAt moves the last checked time of a task forward without
+// touching its other columns or version.
+func (r *TaskRepository) UpdateLastCheckedAt(ctx context.Context, taskID string, checkedAt time.Time) error {
+	query := `
+		UPDATE tasks
+		SET last_checked_at = $2
+		WHERE id = $1 AND (last_checked_at IS NULL OR last_checked_at < $2)
+	`
+	if _, err := r.db.ExecContext(ctx, query, taskID, checkedAt); err != nil {
+		return fmt.Errorf("failed to update last checked time: %w", err)
+	}
 	return nil
 }
 
 // FindByID finds a task by its ID.
 func (r *TaskRepository) FindByID(ctx context.Context, id string) (*domain.Task, error) {
 	query := `
-		SELECT id, name, cron_expression, payload, status, created_at, updated_at, last_checked_at
+		SELECT ` + taskColumns + `
 		FROM tasks
 		WHERE id = $1
 	`
 
-	var dto TaskDTO
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&dto.ID,
-		&dto.Name,
-		&dto.CronExpression,
-		&dto.Payload,
-		&dto.Status,
-		&dto.CreatedAt,
-		&dto.UpdatedAt,
-		&dto.LastCheckedAt,
-	)
+	task, err := scanTask(r.db.QueryRowContext(ctx, query, id))
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
 	}
@@ -123,25 +166,42 @@ func (r *TaskRepository) FindByID(ctx context.Context, id string) (*domain.Task,
 		return nil, fmt.Errorf("failed to find task: %w", err)
 	}
 
-	task, err := dto.ToDomain()
-	if err != nil {
-		return nil, fmt.Errorf("failed to convert DTO to domain: %w", err)
-	}
-
 	return task, nil
 }
 
 // FindAllActive finds all active tasks.
 func (r *TaskRepository) FindAllActive(ctx context.Context) ([]*domain.Task, error) {
 	query := `
-		SELECT id, name, cron_expression, payload, status, created_at, updated_at, last_checked_at
+		SELECT ` + taskColumns + `
 		FROM tasks
 		WHERE status = $1
 	`
+	return r.queryTasks(ctx, query, int(domain.TaskStatusActive))
+}
 
-	rows, err := r.db.QueryContext(ctx, query, int(domain.TaskStatusActive))
+// FindAll finds all tasks ordered by creation time.
+func (r *TaskRepository) FindAll(ctx context.Context) ([]*domain.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		ORDER BY created_at, id
+	`
+	return r.queryTasks(ctx, query)
+}
+
+// Delete deletes a task. Deleting a task that does not exist is not an error.
+func (r *TaskRepository) Delete(ctx context.Context, id string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM tasks WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+	return nil
+}
+
+// queryTasks runs a query selecting taskColumns and scans every row.
+func (r *TaskRepository) queryTasks(ctx context.Context, query string, args ...any) ([]*domain.Task, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query active tasks: %w", err)
+		return nil, fmt.Errorf("failed to query tasks: %w", err)
 	}
 	defer func() {
 		if closeErr := rows.Close(); closeErr != nil {
@@ -151,26 +211,11 @@ func (r *TaskRepository) FindAllActive(ctx context.Context) ([]*domain.Task, err
 
 	var tasks []*domain.Task
 	for rows.Next() {
-		var dto TaskDTO
-		err := rows.Scan(
-			&dto.ID,
-			&dto.Name,
-			&dto.CronExpression,
-			&dto.Payload,
-			&dto.Status,
-			&dto.CreatedAt,
-			&dto.UpdatedAt,
-			&dto.LastCheckedAt,
-		)
+		task, err := scanTask(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan task row: %w", err)
 		}
 
-		task, err := dto.ToDomain()
-		if err != nil {
-			return nil, fmt.Errorf("failed to convert DTO to domain: %w", err)
-		}
-
 		tasks = append(tasks, task)
 	}
 
@@ -180,3 +225,35 @@ func (r *TaskRepository) FindAllActive(ctx context.Context) ([]*domain.Task, err
 
 	return tasks, nil
 }
+
+// scanTask scans a row selected with taskColumns into a domain Task.
+func scanTask(row rowScanner) (*domain.Task, error) {
+	var dto TaskDTO
+	err := row.Scan(
+		&dto.ID,
+		&dto.Name,
+		&dto.CronExpression,
+		&dto.Timezone,
+		&dto.Payload,
+		&dto.Status,
+		&dto.RetryPolicy,
+		&dto.MisfirePolicy,
+		&dto.ConcurrencyPolicy,
+		&dto.TimeoutMS,
+		&dto.StatusHistory,
+		&dto.CreatedAt,
+		&dto.UpdatedAt,
+		&dto.LastCheckedAt,
+		&dto.RetentionPolicy,
+		&dto.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := dto.ToDomain()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert DTO to domain: %w", err)
+	}
+	return task, nil
+}
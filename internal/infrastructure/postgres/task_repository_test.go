@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,9 +18,13 @@ import (
 func setupTestDB(t *testing.T) (*sql.DB, func()) {
 	t.Helper()
 
-	// Skip test if DB_PASSWORD is not set (not in CI/integration test environment)
+	// Skip test if DB_PASSWORD is not set (not in an integration test environment).
+	// CI provides a database, so a missing DB_PASSWORD there is a misconfiguration rather than a reason to skip.
 	dbPassword := os.Getenv("DB_PASSWORD")
 	if dbPassword == "" {
+		if os.Getenv("CI") != "" {
+			t.Fatal("DB_PASSWORD must be set in CI to run the PostgreSQL integration tests")
+		}
 		t.Skip("DB_PASSWORD not set, skipping integration test")
 	}
 
@@ -54,6 +59,12 @@ func setupTestDB(t *testing.T) (*sql.DB, func()) {
 	// Clean up function to close DB and clean test data
 	cleanup := func() {
 		// Clean up test data
+		if _, err := db.Exec("DELETE FROM job_results"); err != nil {
+			t.Logf("warning: failed to clean up job results: %v", err)
+		}
+		if _, err := db.Exec("DELETE FROM jobs"); err != nil {
+			t.Logf("warning: failed to clean up jobs: %v", err)
+		}
 		if _, err := db.Exec("DELETE FROM tasks"); err != nil {
 			t.Logf("warning: failed to clean up tasks: %v", err)
 		}
@@ -247,6 +258,224 @@ func TestTaskRepository_SaveAndRetrieve_WithLastCheckedAt(t *testing.T) {
 	assert.WithinDuration(t, lastChecked, savedTask.LastCheckedAt, time.Second)
 }
 
+func TestTaskRepository_SaveAndRetrieve_WithRetryPolicy(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := postgres.NewTaskRepository(db)
+	ctx := context.Background()
+
+	task := &domain.Task{
+		ID:             uuid.NewString(),
+		Name:           "Test Task with RetryPolicy",
+		CronExpression: "* * * * *",
+		Payload: domain.HTTPRequestInfo{
+			URL:    "http://example.com",
+			Method: "GET",
+		},
+		Status: domain.TaskStatusActive,
+		RetryPolicy: domain.RetryPolicy{
+			MaxRetries:      3,
+			Backoff:         domain.BackoffExponential,
+			InitialInterval: time.Second,
+			MaxInterval:     time.Minute,
+			Jitter:          0.2,
+		},
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	err := repo.Save(ctx, task)
+	require.NoError(t, err)
+
+	savedTask, err := repo.FindByID(ctx, task.ID)
+	assert.NoError(t, err)
+	require.NotNil(t, savedTask)
+	assert.Equal(t, task.RetryPolicy, savedTask.RetryPolicy)
+}
+
+func TestTaskRepository_SaveAndRetrieve_WithSchedulingPolicies(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := postgres.NewTaskRepository(db)
+	ctx := context.Background()
+
+	task := &domain.Task{
+		ID:             uuid.NewString(),
+		Name:           "Test Task with MisfirePolicy",
+		CronExpression: "* * * * *",
+		Payload: domain.HTTPRequestInfo{
+			URL:    "http://example.com",
+			Method: "GET",
+		},
+		Status: domain.TaskStatusActive,
+		MisfirePolicy: domain.MisfirePolicy{
+			Strategy: domain.MisfireFireLimited,
+			MaxRuns:  10,
+			MaxAge:   time.Hour,
+		},
+		ConcurrencyPolicy: domain.ConcurrencyReplace,
+		Timeout:           30 * time.Second,
+		CreatedAt:         time.Now().UTC(),
+		UpdatedAt:         time.Now().UTC(),
+	}
+
+	err := repo.Save(ctx, task)
+	require.NoError(t, err)
+
+	savedTask, err := repo.FindByID(ctx, task.ID)
+	assert.NoError(t, err)
+	require.NotNil(t, savedTask)
+	assert.Equal(t, task.MisfirePolicy, savedTask.MisfirePolicy)
+	assert.Equal(t, task.ConcurrencyPolicy, savedTask.ConcurrencyPolicy)
+	assert.Equal(t, task.Timeout, savedTask.Timeout)
+}
+
+func TestTaskRepository_SaveAndRetrieve_WithTimezone(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := postgres.NewTaskRepository(db)
+	ctx := context.Background()
+
+	task := &domain.Task{
+		ID:             uuid.NewString(),
+		Name:           "Test Task with Timezone",
+		CronExpression: "0 9 * * *",
+		Timezone:       "Asia/Tokyo",
+		Payload: domain.HTTPRequestInfo{
+			URL:    "http://example.com",
+			Method: "GET",
+		},
+		Status:    domain.TaskStatusActive,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	err := repo.Save(ctx, task)
+	require.NoError(t, err)
+
+	savedTask, err := repo.FindByID(ctx, task.ID)
+	assert.NoError(t, err)
+	require.NotNil(t, savedTask)
+	assert.Equal(t, "Asia/Tokyo", savedTask.Timezone)
+
+	// Update the timezone
+	task.Timezone = "America/New_York"
+	err = repo.Save(ctx, task)
+	require.NoError(t, err)
+
+	savedTask, err = repo.FindByID(ctx, task.ID)
+	assert.NoError(t, err)
+	require.NotNil(t, savedTask)
+	assert.Equal(t, "America/New_York", savedTask.Timezone)
+}
+
+func TestTaskRepository_SaveAndRetrieve_WithStatusHistory(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := postgres.NewTaskRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	task := &domain.Task{
+		ID:             uuid.NewString(),
+		Name:           "Test Task with StatusHistory",
+		CronExpression: "* * * * *",
+		Payload: domain.HTTPRequestInfo{
+			URL:    "http://example.com",
+			Method: "GET",
+		},
+		Status:    domain.TaskStatusActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	require.NoError(t, repo.Save(ctx, task))
+
+	task.Pause("alice", "incident", now.Add(time.Minute))
+	require.NoError(t, repo.Save(ctx, task))
+	task.Resume(domain.ResumeBackfill, "bob", "", now.Add(2*time.Minute))
+	require.NoError(t, repo.Save(ctx, task))
+
+	savedTask, err := repo.FindByID(ctx, task.ID)
+	require.NoError(t, err)
+	require.NotNil(t, savedTask)
+	require.Len(t, savedTask.StatusHistory, 2)
+	assert.Equal(t, domain.TaskStatusPaused, savedTask.StatusHistory[0].Status)
+	assert.Equal(t, "alice", savedTask.StatusHistory[0].Actor)
+	assert.Equal(t, "incident", savedTask.StatusHistory[0].Reason)
+	assert.WithinDuration(t, now.Add(time.Minute), savedTask.StatusHistory[0].ChangedAt, time.Second)
+	assert.Equal(t, domain.TaskStatusActive, savedTask.StatusHistory[1].Status)
+	assert.Equal(t, "bob", savedTask.StatusHistory[1].Actor)
+}
+
+func TestTaskRepository_SaveAndRetrieve_WithRetentionPolicy(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := postgres.NewTaskRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	task := &domain.Task{
+		ID:             uuid.NewString(),
+		Name:           "Test Task with RetentionPolicy",
+		CronExpression: "* * * * *",
+		Payload: domain.HTTPRequestInfo{
+			URL:    "http://example.com",
+			Method: "GET",
+		},
+		Status: domain.TaskStatusActive,
+		RetentionPolicy: domain.RetentionPolicy{
+			Completed: domain.RetentionRule{MaxAge: 24 * time.Hour},
+			Failed:    domain.RetentionRule{MaxAge: 7 * 24 * time.Hour, MaxJobs: 100},
+		},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	require.NoError(t, repo.Save(ctx, task))
+
+	savedTask, err := repo.FindByID(ctx, task.ID)
+	require.NoError(t, err)
+	require.NotNil(t, savedTask)
+	assert.Equal(t, task.RetentionPolicy, savedTask.RetentionPolicy)
+}
+
+func TestTaskRepository_SaveAndRetrieve_WithSigningPolicy(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := postgres.NewTaskRepository(db)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	task := &domain.Task{
+		ID:             uuid.NewString(),
+		Name:           "Test Task with SigningPolicy",
+		CronExpression: "* * * * *",
+		Payload: domain.HTTPRequestInfo{
+			URL:    "http://example.com",
+			Method: "POST",
+			Body:   []byte(`{}`),
+			Signing: domain.SigningPolicy{
+				Secrets:         []string{"old-secret", "new-secret"},
+				SignatureHeader: "X-Signature",
+			},
+		},
+		Status:    domain.TaskStatusActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	require.NoError(t, repo.Save(ctx, task))
+
+	savedTask, err := repo.FindByID(ctx, task.ID)
+	require.NoError(t, err)
+	require.NotNil(t, savedTask)
+	assert.Equal(t, task.Payload.Signing, savedTask.Payload.Signing)
+}
+
 func TestTaskRepository_PayloadEdgeCases(t *testing.T) {
 	db, cleanup := setupTestDB(t)
 	defer cleanup()
@@ -400,27 +629,145 @@ func TestTaskRepository_ConcurrentUpdates(t *testing.T) {
 		}(i)
 	}
 
-	// Wait for all goroutines to complete
-	var successCount, errorCount int
+	// Wait for all goroutines to complete. A save based on a stale read is
+	// rejected instead of silently overwriting another update.
+	var successCount int
 	for i := 0; i < numGoroutines; i++ {
 		err := <-done
 		if err == nil {
 			successCount++
 		} else {
-			errorCount++
+			assert.ErrorIs(t, err, domain.ErrConflict)
 		}
 	}
+	assert.GreaterOrEqual(t, successCount, 1, "at least one concurrent update should succeed")
 
-	// All operations should succeed with pessimistic locking
-	assert.Equal(t, numGoroutines, successCount, "all concurrent updates should succeed with pessimistic locking")
-	assert.Equal(t, 0, errorCount, "no errors should occur with pessimistic locking")
-
-	// Verify final state
+	// Verify final state: every successful update is kept
 	finalTask, err := repo.FindByID(ctx, taskID)
 	require.NoError(t, err)
 	require.NotNil(t, finalTask)
+	assert.Equal(t, "Concurrent Test Task"+strings.Repeat(" - Updated", successCount), finalTask.Name)
+	assert.Equal(t, int64(1+successCount), finalTask.Version)
+}
+
+func TestTaskRepository_Save_Conflict(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := postgres.NewTaskRepository(db)
+	ctx := context.Background()
 
-	// Due to concurrent updates, we just verify the task exists and was updated
-	assert.Contains(t, finalTask.Name, "Concurrent Test Task")
-	assert.Contains(t, finalTask.Name, "Updated")
+	task := &domain.Task{
+		ID:             uuid.NewString(),
+		Name:           "Task",
+		CronExpression: "* * * * *",
+		Payload:        domain.HTTPRequestInfo{URL: "http://example.com", Method: "GET"},
+		Status:         domain.TaskStatusActive,
+		CreatedAt:      time.Now().UTC(),
+		UpdatedAt:      time.Now().UTC(),
+	}
+	require.NoError(t, repo.Save(ctx, task))
+	assert.Equal(t, int64(1), task.Version)
+
+	// Two writers read the same version; the second save conflicts
+	first, err := repo.FindByID(ctx, task.ID)
+	require.NoError(t, err)
+	second, err := repo.FindByID(ctx, task.ID)
+	require.NoError(t, err)
+	first.Status = domain.TaskStatusPaused
+	require.NoError(t, repo.Save(ctx, first))
+	assert.Equal(t, int64(2), first.Version)
+	second.Name = "Renamed"
+	assert.ErrorIs(t, repo.Save(ctx, second), domain.ErrConflict)
+
+	stored, err := repo.FindByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Task", stored.Name)
+	assert.Equal(t, domain.TaskStatusPaused, stored.Status)
+	assert.Equal(t, int64(2), stored.Version)
+}
+
+func TestTaskRepository_UpdateLastCheckedAt(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := postgres.NewTaskRepository(db)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	task := &domain.Task{
+		ID:             uuid.NewString(),
+		Name:           "Task",
+		CronExpression: "* * * * *",
+		Payload:        domain.HTTPRequestInfo{URL: "http://example.com", Method: "GET"},
+		Status:         domain.TaskStatusActive,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	require.NoError(t, repo.Save(ctx, task))
+
+	// A pause saved between the scheduler's read and write is kept
+	paused, err := repo.FindByID(ctx, task.ID)
+	require.NoError(t, err)
+	paused.Status = domain.TaskStatusPaused
+	require.NoError(t, repo.Save(ctx, paused))
+	require.NoError(t, repo.UpdateLastCheckedAt(ctx, task.ID, now))
+
+	stored, err := repo.FindByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.TaskStatusPaused, stored.Status)
+	assert.True(t, now.Equal(stored.LastCheckedAt))
+	assert.Equal(t, paused.Version, stored.Version, "updating the last checked time does not change the version")
+
+	// The last checked time never moves backwards, neither here nor through Save
+	require.NoError(t, repo.UpdateLastCheckedAt(ctx, task.ID, now.Add(-time.Minute)))
+	require.NoError(t, repo.Save(ctx, paused))
+	stored, err = repo.FindByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.True(t, now.Equal(stored.LastCheckedAt))
+
+	// Unknown tasks are ignored
+	require.NoError(t, repo.UpdateLastCheckedAt(ctx, uuid.NewString(), now))
+}
+
+func TestTaskRepository_FindAllAndDelete(t *testing.T) {
+	db, cleanup := setupTestDB(t)
+	defer cleanup()
+
+	repo := postgres.NewTaskRepository(db)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	newTask := func(status domain.TaskStatus, createdAt time.Time) *domain.Task {
+		return &domain.Task{
+			ID:             uuid.NewString(),
+			Name:           "Task",
+			CronExpression: "* * * * *",
+			Payload:        domain.HTTPRequestInfo{URL: "http://example.com", Method: "GET"},
+			Status:         status,
+			CreatedAt:      createdAt,
+			UpdatedAt:      createdAt,
+		}
+	}
+	active := newTask(domain.TaskStatusActive, now)
+	paused := newTask(domain.TaskStatusPaused, now.Add(-time.Minute))
+	require.NoError(t, repo.Save(ctx, active))
+	require.NoError(t, repo.Save(ctx, paused))
+
+	// FindAll returns tasks of every status ordered by creation time
+	tasks, err := repo.FindAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, paused.ID, tasks[0].ID)
+	assert.Equal(t, active.ID, tasks[1].ID)
+
+	require.NoError(t, repo.Delete(ctx, paused.ID))
+	require.NoError(t, repo.Delete(ctx, uuid.NewString()))
+
+	deleted, err := repo.FindByID(ctx, paused.ID)
+	require.NoError(t, err)
+	assert.Nil(t, deleted)
+	tasks, err = repo.FindAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, tasks, 1)
 }
@@ -0,0 +1,22 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// NewClient creates a new Redis client and verifies the connection.
+func NewClient(ctx context.Context, addr string) (*goredis.Client, error) {
+	client := goredis.NewClient(&goredis.Options{
+		Addr: addr,
+	})
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to ping redis: %w", err)
+	}
+
+	return client, nil
+}
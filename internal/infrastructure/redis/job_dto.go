@@ -0,0 +1,64 @@
+package redis
+
+import (
+	"time"
+
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+)
+
+// JobDTO represents the JSON structure of a Job stored in Redis.
+// LeaseExpiresAt mirrors the job's score in the processing set, which remains
+// authoritative for finding expired leases.
+type JobDTO struct {
+	ID             string            `json:"id"`
+	TaskID         string            `json:"task_id"`
+	ScheduledAt    time.Time         `json:"scheduled_at"`
+	StartedAt      time.Time         `json:"started_at"`
+	FinishedAt     time.Time         `json:"finished_at"`
+	Status         int               `json:"status"`
+	RetryCount     int               `json:"retry_count"`
+	AvailableAt    time.Time         `json:"available_at"`
+	TriggerSource  int               `json:"trigger_source"`
+	TraceContext   map[string]string `json:"trace_context,omitempty"`
+	LeaseExpiresAt time.Time         `json:"lease_expires_at"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// ToJobDTO converts a domain Job to a JobDTO.
+func ToJobDTO(job *domain.Job) *JobDTO {
+	return &JobDTO{
+		ID:             job.ID,
+		TaskID:         job.TaskID,
+		ScheduledAt:    job.ScheduledAt,
+		StartedAt:      job.StartedAt,
+		FinishedAt:     job.FinishedAt,
+		Status:         int(job.Status),
+		RetryCount:     job.RetryCount,
+		AvailableAt:    job.AvailableAt,
+		TriggerSource:  int(job.TriggerSource),
+		TraceContext:   job.TraceContext,
+		LeaseExpiresAt: job.LeaseExpiresAt,
+		CreatedAt:      job.CreatedAt,
+		UpdatedAt:      job.UpdatedAt,
+	}
+}
+
+// ToDomain converts a JobDTO to a domain Job.
+func (dto *JobDTO) ToDomain() *domain.Job {
+	return &domain.Job{
+		ID:             dto.ID,
+		TaskID:         dto.TaskID,
+		ScheduledAt:    dto.ScheduledAt,
+		StartedAt:      dto.StartedAt,
+		FinishedAt:     dto.FinishedAt,
+		Status:         domain.JobStatus(dto.Status),
+		RetryCount:     dto.RetryCount,
+		AvailableAt:    dto.AvailableAt,
+		TriggerSource:  domain.TriggerSource(dto.TriggerSource),
+		TraceContext:   dto.TraceContext,
+		LeaseExpiresAt: dto.LeaseExpiresAt,
+		CreatedAt:      dto.CreatedAt,
+		UpdatedAt:      dto.UpdatedAt,
+	}
+}
@@ -0,0 +1,805 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+)
+
+const (
+	// keyPrefix is prepended to every key written by this package.
+	keyPrefix = "scheduler:"
+	// pendingKey is a list of job IDs waiting to be dequeued (FIFO).
+	pendingKey = keyPrefix + "jobs:pending"
+	// processingKey is a sorted set of dequeued job IDs scored by the time
+	// (unix milliseconds) their lease expires.
+	processingKey = keyPrefix + "jobs:processing"
+	// delayedKey is a sorted set of job IDs that must not be dequeued before
+	// their score (unix milliseconds), used for retries with backoff.
+	delayedKey = keyPrefix + "jobs:delayed"
+	// promoteBatchSize is the maximum number of due delayed jobs moved to the
+	// pending list on each dequeue.
+	promoteBatchSize = 100
+	// maxTaskResults is the number of recent results kept per task.
+	maxTaskResults = 1000
+	// pruneLoadBatchSize is the number of jobs loaded with one MGET while
+	// looking for finished jobs to delete.
+	pruneLoadBatchSize = 500
+)
+
+// jobKey returns the key under which the JSON representation of a job is stored.
+func jobKey(jobID string) string {
+	return keyPrefix + "job:" + jobID
+}
+
+// jobResultsKey returns the key of the list holding the results of a job.
+func jobResultsKey(jobID string) string {
+	return keyPrefix + "job:" + jobID + ":results"
+}
+
+// taskJobsKey returns the key of the sorted set indexing the jobs of a task by
+// their scheduled time (unix milliseconds).
+func taskJobsKey(taskID string) string {
+	return keyPrefix + "task:" + taskID + ":jobs"
+}
+
+// taskActiveJobsKey returns the key of the set holding the IDs of the
+// unfinished (pending or running) jobs of a task.
+func taskActiveJobsKey(taskID string) string {
+	return keyPrefix + "task:" + taskID + ":active"
+}
+
+// taskResultsKey returns the key of the list holding the recent results of a
+// task, most recent first.
+func taskResultsKey(taskID string) string {
+	return keyPrefix + "task:" + taskID + ":results"
+}
+
+// taskJobsKeyPattern matches every key returned by taskJobsKey.
+const taskJobsKeyPattern = keyPrefix + "task:*:jobs"
+
+// scheduleKey returns the key that marks a task as enqueued for a scheduled time.
+// It lives as long as the job and is deleted with it by DeleteFinished.
+func scheduleKey(job *domain.Job) string {
+	return keyPrefix + "schedule:" + job.TaskID + ":" + strconv.FormatInt(job.ScheduledAt.UnixNano(), 10)
+}
+
+// enqueueScript stores the job only if neither the job nor another job for the
+// same task and scheduled time (KEYS[4]) exists yet, indexes it under its task
+// (KEYS[5]) by scheduled time (ARGV[4]) and as active (KEYS[6]), and pushes its
+// ID to the pending list,
+// or to the delayed set when ARGV[3] (available at, unix milliseconds) is
+// positive. It returns 0 when the job is a duplicate.
+var enqueueScript = goredis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 or redis.call('EXISTS', KEYS[4]) == 1 then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2])
+redis.call('SET', KEYS[4], ARGV[1])
+redis.call('ZADD', KEYS[5], ARGV[4], ARGV[1])
+redis.call('SADD', KEYS[6], ARGV[1])
+if tonumber(ARGV[3]) > 0 then
+	redis.call('ZADD', KEYS[3], ARGV[3], ARGV[1])
+else
+	redis.call('RPUSH', KEYS[2], ARGV[1])
+end
+return 1
+`)
+
+// dequeueScript first promotes delayed jobs that became available, then
+// atomically moves the head of the pending list into the processing set,
+// scored by its lease deadline (ARGV[3]), so that a crash between the two
+// steps cannot lose a job.
+var dequeueScript = goredis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[3], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, id in ipairs(due) do
+	redis.call('ZREM', KEYS[3], id)
+	redis.call('RPUSH', KEYS[1], id)
+end
+local id = redis.call('LPOP', KEYS[1])
+if not id then
+	return false
+end
+redis.call('ZADD', KEYS[2], ARGV[3], id)
+return id
+`)
+
+// retryScript overwrites the job, removes every existing queue entry for it
+// and puts it back to the pending list, or to the delayed set when ARGV[3]
+// (available at, unix milliseconds) is positive. A job whose stored status is
+// cancelled (ARGV[4]) is left untouched and 0 is returned.
+var retryScript = goredis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if current and cjson.decode(current).status == tonumber(ARGV[4]) then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2])
+redis.call('ZREM', KEYS[2], ARGV[1])
+redis.call('ZREM', KEYS[3], ARGV[1])
+redis.call('LREM', KEYS[4], 0, ARGV[1])
+if tonumber(ARGV[3]) > 0 then
+	redis.call('ZADD', KEYS[3], ARGV[3], ARGV[1])
+else
+	redis.call('RPUSH', KEYS[4], ARGV[1])
+end
+return 1
+`)
+
+// reclaimScript overwrites a job reclaimed by the reaper only if its stored
+// status is pending or running (ARGV[5], ARGV[6]) and its score in the
+// processing set is below ARGV[4] (now, unix milliseconds), so a job that
+// finished, was requeued or renewed its lease after the lookup is left
+// untouched and 0 is returned. A pending job also counts because a job stays
+// pending if its executor crashed between the pop and the claim. The job is
+// removed from the processing set and, when ARGV[7] is 1, put back to the
+// pending list, or to the delayed set when ARGV[3] (available at, unix
+// milliseconds) is positive; otherwise it is no longer active (KEYS[5]).
+var reclaimScript = goredis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if not current then
+	return 0
+end
+local status = cjson.decode(current).status
+if status ~= tonumber(ARGV[5]) and status ~= tonumber(ARGV[6]) then
+	return 0
+end
+local lease = redis.call('ZSCORE', KEYS[2], ARGV[1])
+if not lease or tonumber(lease) >= tonumber(ARGV[4]) then
+	return 0
+end
+redis.call('SET', KEYS[1], ARGV[2])
+redis.call('ZREM', KEYS[2], ARGV[1])
+if ARGV[7] == '1' then
+	if tonumber(ARGV[3]) > 0 then
+		redis.call('ZADD', KEYS[3], ARGV[3], ARGV[1])
+	else
+		redis.call('RPUSH', KEYS[4], ARGV[1])
+	end
+else
+	redis.call('SREM', KEYS[5], ARGV[1])
+end
+return 1
+`)
+
+// JobRepository is a Redis implementation of the JobRepository interface.
+// Dequeued jobs are kept in a processing set, scored by their lease deadline,
+// until they reach a terminal status or are requeued, so jobs held by a
+// crashed executor can be found with FindExpiredLeases.
+type JobRepository struct {
+	client            *goredis.Client
+	visibilityTimeout time.Duration
+}
+
+// JobRepositoryOption configures a JobRepository.
+type JobRepositoryOption func(*JobRepository)
+
+// WithVisibilityTimeout sets the lease duration granted on Dequeue and ExtendLease.
+func WithVisibilityTimeout(d time.Duration) JobRepositoryOption {
+	return func(r *JobRepository) {
+		r.visibilityTimeout = d
+	}
+}
+
+// NewJobRepository creates a new Redis JobRepository.
+func NewJobRepository(client *goredis.Client, opts ...JobRepositoryOption) *JobRepository {
+	r := &JobRepository{client: client, visibilityTimeout: domain.DefaultVisibilityTimeout}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Enqueue stores a job and appends it to the pending list. It returns
+// domain.ErrConstraintViolation if a job with the same ID, or with the same
+// task ID and scheduled time, already exists.
+func (r *JobRepository) Enqueue(ctx context.Context, job *domain.Job) error {
+	data, err := json.Marshal(ToJobDTO(job))
+	if err != nil {
+		return fmt.Errorf("failed to marshal job: %w", err)
+	}
+
+	keys := []string{jobKey(job.ID), pendingKey, delayedKey, scheduleKey(job), taskJobsKey(job.TaskID), taskActiveJobsKey(job.TaskID)}
+	created, err := enqueueScript.Run(ctx, r.client, keys, job.ID, data, availableAtScore(job), job.ScheduledAt.UnixMilli()).Int()
+	if err != nil {
+		return fmt.Errorf("failed to enqueue job: %w", err)
+	}
+	if created == 0 {
+		return domain.ErrConstraintViolation
+	}
+
+	return nil
+}
+
+// Dequeue claims the oldest available job by moving it to the processing set,
+// marks it as Running and leases it for the visibility timeout, as the
+// PostgreSQL repository does. The lease is also stored with the job, so
+// FindByID returns it. It returns nil if no pending job is available, or if
+// the claimed job was finished or deleted after it was popped.
+func (r *JobRepository) Dequeue(ctx context.Context) (*domain.Job, error) {
+	now := time.Now()
+	leaseExpiresAt := now.Add(r.visibilityTimeout)
+
+	keys := []string{pendingKey, processingKey, delayedKey}
+	jobID, err := dequeueScript.Run(ctx, r.client, keys, now.UnixMilli(), promoteBatchSize, leaseExpiresAt.UnixMilli()).Text()
+	if errors.Is(err, goredis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to dequeue job: %w", err)
+	}
+
+	return r.claim(ctx, jobID, time.UnixMilli(leaseExpiresAt.UnixMilli()))
+}
+
+// claimAttempts is the number of times claim retries when the job is updated
+// concurrently, for example cancelled, while it is being claimed.
+const claimAttempts = 3
+
+// claim marks a job popped by dequeueScript as Running and stores its lease.
+// A job that was finished or deleted after it was popped is dropped from the
+// processing set instead, and nil is returned.
+func (r *JobRepository) claim(ctx context.Context, jobID string, leaseExpiresAt time.Time) (*domain.Job, error) {
+	key := jobKey(jobID)
+
+	var job *domain.Job
+	claim := func(tx *goredis.Tx) error {
+		var err error
+		job, err = r.get(ctx, tx, jobID)
+		if err != nil {
+			return err
+		}
+		if job == nil || job.IsFinished() {
+			job = nil
+			_, err = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+				pipe.ZRem(ctx, processingKey, jobID)
+				return nil
+			})
+			return err
+		}
+
+		job.MarkAsRunning()
+		job.LeaseExpiresAt = leaseExpiresAt
+		data, err := json.Marshal(ToJobDTO(job))
+		if err != nil {
+			return fmt.Errorf("failed to marshal job: %w", err)
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			pipe.Set(ctx, key, data, 0)
+			return nil
+		})
+		return err
+	}
+
+	var err error
+	for range claimAttempts {
+		if err = r.client.Watch(ctx, claim, key); !errors.Is(err, goredis.TxFailedErr) {
+			break
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim job %s: %w", jobID, err)
+	}
+
+	return job, nil
+}
+
+// UpdateStatus updates the status of a job along with its timestamps.
+// Jobs reaching a terminal status are removed from the processing set and
+// are no longer active. Updating a job that does not exist or was cancelled
+// is not an error.
+func (r *JobRepository) UpdateStatus(ctx context.Context, jobID string, status domain.JobStatus) error {
+	key := jobKey(jobID)
+
+	err := r.client.Watch(ctx, func(tx *goredis.Tx) error {
+		job, err := r.get(ctx, tx, jobID)
+		if err != nil {
+			return err
+		}
+		if job == nil || job.Status == domain.JobStatusCancelled {
+			return nil
+		}
+
+		applyStatus(job, status)
+		data, err := json.Marshal(ToJobDTO(job))
+		if err != nil {
+			return fmt.Errorf("failed to marshal job: %w", err)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			pipe.Set(ctx, key, data, 0)
+			if job.IsFinished() {
+				pipe.ZRem(ctx, processingKey, jobID)
+				pipe.SRem(ctx, taskActiveJobsKey(job.TaskID), jobID)
+			}
+			return nil
+		})
+		return err
+	}, key)
+	if err != nil {
+		return fmt.Errorf("failed to update job status: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID returns a job by ID, or nil if it does not exist.
+func (r *JobRepository) FindByID(ctx context.Context, jobID string) (*domain.Job, error) {
+	return r.get(ctx, r.client, jobID)
+}
+
+// FindByTaskID returns up to limit jobs of a task, most recently scheduled first.
+func (r *JobRepository) FindByTaskID(ctx context.Context, taskID string, limit int) ([]*domain.Job, error) {
+	jobIDs, err := r.client.ZRevRange(ctx, taskJobsKey(taskID), 0, int64(limit)-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find jobs of task %s: %w", taskID, err)
+	}
+
+	jobs := make([]*domain.Job, 0, len(jobIDs))
+	for _, jobID := range jobIDs {
+		job, err := r.get(ctx, r.client, jobID)
+		if err != nil {
+			return nil, err
+		}
+		if job != nil {
+			jobs = append(jobs, job)
+		}
+	}
+
+	return jobs, nil
+}
+
+// FindActiveByTaskID returns the pending or running jobs of a task, earliest
+// scheduled first.
+func (r *JobRepository) FindActiveByTaskID(ctx context.Context, taskID string) ([]*domain.Job, error) {
+	jobIDs, err := r.client.SMembers(ctx, taskActiveJobsKey(taskID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find active jobs of task %s: %w", taskID, err)
+	}
+
+	jobs := make([]*domain.Job, 0, len(jobIDs))
+	for _, jobID := range jobIDs {
+		job, err := r.get(ctx, r.client, jobID)
+		if err != nil {
+			return nil, err
+		}
+		if job != nil && !job.IsFinished() {
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].ScheduledAt.Before(jobs[j].ScheduledAt)
+	})
+
+	return jobs, nil
+}
+
+// CountPending returns the number of jobs waiting to be dequeued, including
+// jobs in the delayed set that are waiting for a retry.
+func (r *JobRepository) CountPending(ctx context.Context) (int, error) {
+	pipe := r.client.Pipeline()
+	pending := pipe.LLen(ctx, pendingKey)
+	delayed := pipe.ZCard(ctx, delayedKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to count pending jobs: %w", err)
+	}
+	return int(pending.Val() + delayed.Val()), nil
+}
+
+// Cancel marks a pending or running job as cancelled and removes it from
+// every queue. Cancelling a finished or missing job is not an error.
+func (r *JobRepository) Cancel(ctx context.Context, jobID string) error {
+	key := jobKey(jobID)
+
+	err := r.client.Watch(ctx, func(tx *goredis.Tx) error {
+		job, err := r.get(ctx, tx, jobID)
+		if err != nil {
+			return err
+		}
+		if job == nil || job.IsFinished() {
+			return nil
+		}
+
+		job.MarkAsCancelled()
+		data, err := json.Marshal(ToJobDTO(job))
+		if err != nil {
+			return fmt.Errorf("failed to marshal job: %w", err)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			pipe.Set(ctx, key, data, 0)
+			pipe.LRem(ctx, pendingKey, 0, jobID)
+			pipe.ZRem(ctx, delayedKey, jobID)
+			pipe.ZRem(ctx, processingKey, jobID)
+			pipe.SRem(ctx, taskActiveJobsKey(job.TaskID), jobID)
+			return nil
+		})
+		return err
+	}, key)
+	if err != nil {
+		return fmt.Errorf("failed to cancel job: %w", err)
+	}
+
+	return nil
+}
+
+// Requeue stores the retry state of a job and returns it to the queue. Jobs
+// whose AvailableAt is in the future wait in the delayed set until then.
+// Cancelled jobs are left untouched.
+func (r *JobRepository) Requeue(ctx context.Context, job *domain.Job) error {
+	data, err := json.Marshal(ToJobDTO(job))
+	if err != nil {
+		return fmt.Errorf("failed to marshal job: %w", err)
+	}
+
+	keys := []string{jobKey(job.ID), processingKey, delayedKey, pendingKey}
+	if err := retryScript.Run(ctx, r.client, keys, job.ID, data, availableAtScore(job), int(domain.JobStatusCancelled)).Err(); err != nil {
+		return fmt.Errorf("failed to requeue job: %w", err)
+	}
+
+	return nil
+}
+
+// ExtendLease extends the lease of a job in the processing set by the
+// visibility timeout. Extending the lease of a job that is no longer being
+// processed is not an error.
+func (r *JobRepository) ExtendLease(ctx context.Context, jobID string) error {
+	deadline := time.Now().Add(r.visibilityTimeout).UnixMilli()
+	member := goredis.Z{Score: float64(deadline), Member: jobID}
+	if err := r.client.ZAddXX(ctx, processingKey, member).Err(); err != nil {
+		return fmt.Errorf("failed to extend job lease: %w", err)
+	}
+
+	// The processing set is authoritative, so losing a race with another update
+	// of the job only leaves its stored lease one heartbeat behind.
+	if err := r.saveLease(ctx, jobID, time.UnixMilli(deadline)); err != nil && !errors.Is(err, goredis.TxFailedErr) {
+		return err
+	}
+	return nil
+}
+
+// saveLease stores leaseExpiresAt with an unfinished job that already holds a
+// lease, so a heartbeat racing with the job's completion or requeue does not
+// lease it again. A missing job is ignored.
+func (r *JobRepository) saveLease(ctx context.Context, jobID string, leaseExpiresAt time.Time) error {
+	key := jobKey(jobID)
+
+	err := r.client.Watch(ctx, func(tx *goredis.Tx) error {
+		job, err := r.get(ctx, tx, jobID)
+		if err != nil {
+			return err
+		}
+		if job == nil || job.IsFinished() || job.LeaseExpiresAt.IsZero() {
+			return nil
+		}
+
+		job.LeaseExpiresAt = leaseExpiresAt
+		data, err := json.Marshal(ToJobDTO(job))
+		if err != nil {
+			return fmt.Errorf("failed to marshal job: %w", err)
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+			pipe.Set(ctx, key, data, 0)
+			return nil
+		})
+		return err
+	}, key)
+	if err != nil {
+		return fmt.Errorf("failed to save job lease: %w", err)
+	}
+
+	return nil
+}
+
+// FindExpiredLeases returns up to limit jobs in the processing set whose lease
+// expired before now, ordered by lease expiration.
+func (r *JobRepository) FindExpiredLeases(ctx context.Context, now time.Time, limit int) ([]*domain.Job, error) {
+	members, err := r.client.ZRangeByScoreWithScores(ctx, processingKey, &goredis.ZRangeBy{
+		Min:   "-inf",
+		Max:   "(" + strconv.FormatInt(now.UnixMilli(), 10),
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expired leases: %w", err)
+	}
+
+	jobs := make([]*domain.Job, 0, len(members))
+	for _, member := range members {
+		jobID, ok := member.Member.(string)
+		if !ok {
+			continue
+		}
+		job, err := r.get(ctx, r.client, jobID)
+		if err != nil {
+			return nil, err
+		}
+		if job == nil || job.IsFinished() {
+			// Finished or deleted after the lookup
+			continue
+		}
+		job.LeaseExpiresAt = time.UnixMilli(int64(member.Score))
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// ReclaimExpired stores a job updated by the reaper if the stored job is still
+// unfinished and its lease in the processing set expired before now. The
+// processing set is authoritative for leases, so a job whose claim did not
+// complete is reclaimed as well. A job returned to Pending is queued again.
+func (r *JobRepository) ReclaimExpired(ctx context.Context, job *domain.Job, now time.Time) (bool, error) {
+	data, err := json.Marshal(ToJobDTO(job))
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal job: %w", err)
+	}
+
+	requeue := 0
+	if job.Status == domain.JobStatusPending {
+		requeue = 1
+	}
+	keys := []string{jobKey(job.ID), processingKey, delayedKey, pendingKey, taskActiveJobsKey(job.TaskID)}
+	reclaimed, err := reclaimScript.Run(ctx, r.client, keys, job.ID, data, availableAtScore(job), now.UnixMilli(),
+		int(domain.JobStatusPending), int(domain.JobStatusRunning), requeue).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to reclaim job: %w", err)
+	}
+
+	return reclaimed == 1, nil
+}
+
+// SaveResult stores the execution result of a job attempt. Only the most recent
+// maxTaskResults results are kept for listing by task.
+func (r *JobRepository) SaveResult(ctx context.Context, result *domain.JobResult) error {
+	data, err := json.Marshal(ToJobResultDTO(result))
+	if err != nil {
+		return fmt.Errorf("failed to marshal job result: %w", err)
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.RPush(ctx, jobResultsKey(result.JobID), data)
+		pipe.LPush(ctx, taskResultsKey(result.TaskID), data)
+		pipe.LTrim(ctx, taskResultsKey(result.TaskID), 0, maxTaskResults-1)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save job result: %w", err)
+	}
+
+	return nil
+}
+
+// FindResultsByJobID returns the execution results of a job ordered by attempt.
+func (r *JobRepository) FindResultsByJobID(ctx context.Context, jobID string) ([]*domain.JobResult, error) {
+	results, err := r.listResults(ctx, jobResultsKey(jobID), -1)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Attempt < results[j].Attempt
+	})
+	return results, nil
+}
+
+// FindRecentResultsByTaskID returns up to limit execution results of a task,
+// most recently finished first.
+func (r *JobRepository) FindRecentResultsByTaskID(ctx context.Context, taskID string, limit int) ([]*domain.JobResult, error) {
+	results, err := r.listResults(ctx, taskResultsKey(taskID), int64(limit)-1)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].FinishedAt.After(results[j].FinishedAt)
+	})
+	return results, nil
+}
+
+// listResults loads the results stored in a list up to the stop index.
+func (r *JobRepository) listResults(ctx context.Context, key string, stop int64) ([]*domain.JobResult, error) {
+	values, err := r.client.LRange(ctx, key, 0, stop).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list job results: %w", err)
+	}
+
+	results := make([]*domain.JobResult, 0, len(values))
+	for _, value := range values {
+		var dto JobResultDTO
+		if err := json.Unmarshal([]byte(value), &dto); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal job result: %w", err)
+		}
+		results = append(results, dto.ToDomain())
+	}
+	return results, nil
+}
+
+// DeleteFinished deletes up to limit finished jobs that match filter, together
+// with their results and the key marking their task and scheduled time as
+// enqueued, and returns the number of deleted jobs.
+func (r *JobRepository) DeleteFinished(ctx context.Context, filter domain.JobPruneFilter, limit int) (int, error) {
+	statuses := make(map[domain.JobStatus]bool, len(filter.Statuses))
+	for _, status := range filter.Statuses {
+		statuses[status] = true
+	}
+	if len(statuses) == 0 || (filter.FinishedBefore.IsZero() && filter.KeepLatest == 0) {
+		return 0, nil
+	}
+
+	taskIDs, err := r.pruneTargets(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, taskID := range taskIDs {
+		if deleted >= limit {
+			break
+		}
+		jobs, err := r.findPrunable(ctx, taskID, statuses, filter, limit-deleted)
+		if err != nil {
+			return deleted, err
+		}
+		for _, job := range jobs {
+			if err := r.deleteJob(ctx, job); err != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// pruneTargets returns the IDs of the tasks whose jobs filter applies to: the
+// task named by filter.TaskID, or every task with jobs, except the excluded ones.
+func (r *JobRepository) pruneTargets(ctx context.Context, filter domain.JobPruneFilter) ([]string, error) {
+	excluded := make(map[string]bool, len(filter.ExcludeTaskIDs))
+	for _, taskID := range filter.ExcludeTaskIDs {
+		excluded[taskID] = true
+	}
+
+	if filter.TaskID != "" {
+		if excluded[filter.TaskID] {
+			return nil, nil
+		}
+		return []string{filter.TaskID}, nil
+	}
+
+	var taskIDs []string
+	iter := r.client.Scan(ctx, 0, taskJobsKeyPattern, 0).Iterator()
+	for iter.Next(ctx) {
+		taskID := strings.TrimSuffix(strings.TrimPrefix(iter.Val(), keyPrefix+"task:"), ":jobs")
+		if !excluded[taskID] {
+			taskIDs = append(taskIDs, taskID)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan tasks: %w", err)
+	}
+	return taskIDs, nil
+}
+
+// findPrunable returns up to limit finished jobs of a task that match filter,
+// walking the jobs from the most recently scheduled one.
+func (r *JobRepository) findPrunable(ctx context.Context, taskID string, statuses map[domain.JobStatus]bool, filter domain.JobPruneFilter, limit int) ([]*domain.Job, error) {
+	jobIDs, err := r.client.ZRevRange(ctx, taskJobsKey(taskID), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to find jobs of task %s: %w", taskID, err)
+	}
+
+	var prunable []*domain.Job
+	rank := 0
+	for start := 0; start < len(jobIDs) && len(prunable) < limit; start += pruneLoadBatchSize {
+		batch := jobIDs[start:min(start+pruneLoadBatchSize, len(jobIDs))]
+		keys := make([]string, len(batch))
+		for i, jobID := range batch {
+			keys[i] = jobKey(jobID)
+		}
+		values, err := r.client.MGet(ctx, keys...).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load jobs of task %s: %w", taskID, err)
+		}
+
+		for _, value := range values {
+			data, ok := value.(string)
+			if !ok {
+				// Deleted after the lookup
+				continue
+			}
+			var dto JobDTO
+			if err := json.Unmarshal([]byte(data), &dto); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal job: %w", err)
+			}
+			job := dto.ToDomain()
+			// Unfinished jobs are never deleted, whatever the filter says
+			if !job.IsFinished() || !statuses[job.Status] {
+				continue
+			}
+			rank++
+			expired := !filter.FinishedBefore.IsZero() && job.FinishedAt.Before(filter.FinishedBefore)
+			overflowed := filter.KeepLatest > 0 && rank > filter.KeepLatest
+			if !expired && !overflowed {
+				continue
+			}
+			prunable = append(prunable, job)
+			if len(prunable) >= limit {
+				break
+			}
+		}
+	}
+
+	return prunable, nil
+}
+
+// deleteJob deletes a finished job, its results, including those listed under
+// its task, and its schedule key.
+func (r *JobRepository) deleteJob(ctx context.Context, job *domain.Job) error {
+	results, err := r.client.LRange(ctx, jobResultsKey(job.ID), 0, -1).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list job results: %w", err)
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe goredis.Pipeliner) error {
+		pipe.Del(ctx, jobKey(job.ID), jobResultsKey(job.ID), scheduleKey(job))
+		pipe.ZRem(ctx, taskJobsKey(job.TaskID), job.ID)
+		pipe.ZRem(ctx, processingKey, job.ID)
+		for _, result := range results {
+			pipe.LRem(ctx, taskResultsKey(job.TaskID), 0, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete job %s: %w", job.ID, err)
+	}
+
+	return nil
+}
+
+// get loads a job by ID. It returns nil if the job does not exist.
+func (r *JobRepository) get(ctx context.Context, c goredis.Cmdable, jobID string) (*domain.Job, error) {
+	data, err := c.Get(ctx, jobKey(jobID)).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job %s: %w", jobID, err)
+	}
+
+	var dto JobDTO
+	if err := json.Unmarshal(data, &dto); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal job %s: %w", jobID, err)
+	}
+	return dto.ToDomain(), nil
+}
+
+// availableAtScore returns the delayed set score for a job, or 0 if the job
+// can be dequeued immediately.
+func availableAtScore(job *domain.Job) int64 {
+	if !job.AvailableAt.After(time.Now()) {
+		return 0
+	}
+	return job.AvailableAt.UnixMilli()
+}
+
+// applyStatus updates the status of a job along with the matching timestamps.
+func applyStatus(job *domain.Job, status domain.JobStatus) {
+	switch status {
+	case domain.JobStatusRunning:
+		job.MarkAsRunning()
+	case domain.JobStatusSuccess:
+		job.MarkAsSuccess()
+	case domain.JobStatusFailed:
+		job.MarkAsFailed()
+	case domain.JobStatusTimedOut:
+		job.MarkAsTimedOut()
+	default:
+		job.Status = status
+		job.UpdatedAt = time.Now()
+	}
+}
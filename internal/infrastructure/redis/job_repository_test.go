@@ -0,0 +1,610 @@
+package redis_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/redis"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/repotest"
+)
+
+func setupTestRedis(t *testing.T) *goredis.Client {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client, err := redis.NewClient(context.Background(), server.Addr())
+	require.NoError(t, err, "failed to connect to redis")
+	t.Cleanup(func() {
+		if err := client.Close(); err != nil {
+			t.Logf("warning: failed to close redis client: %v", err)
+		}
+	})
+
+	return client
+}
+
+func newPendingJob() *domain.Job {
+	now := time.Now().UTC()
+	return &domain.Job{
+		ID:          uuid.NewString(),
+		TaskID:      uuid.NewString(),
+		ScheduledAt: now,
+		Status:      domain.JobStatusPending,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+func TestJobRepository_EnqueueAndDequeue(t *testing.T) {
+	repo := redis.NewJobRepository(setupTestRedis(t))
+	ctx := context.Background()
+
+	first := newPendingJob()
+	first.TriggerSource = domain.TriggerSourceManual
+	second := newPendingJob()
+	require.NoError(t, repo.Enqueue(ctx, first))
+	require.NoError(t, repo.Enqueue(ctx, second))
+
+	dequeued, err := repo.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, dequeued)
+	assert.Equal(t, first.ID, dequeued.ID)
+	assert.Equal(t, first.TaskID, dequeued.TaskID)
+	assert.True(t, first.ScheduledAt.Equal(dequeued.ScheduledAt))
+	assert.Equal(t, domain.JobStatusRunning, dequeued.Status)
+	assert.Equal(t, domain.TriggerSourceManual, dequeued.TriggerSource)
+
+	// The dequeued job is stored as Running, as in the other backends
+	stored, err := repo.FindByID(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusRunning, stored.Status)
+	assert.False(t, stored.StartedAt.IsZero())
+
+	dequeued, err = repo.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, dequeued)
+	assert.Equal(t, second.ID, dequeued.ID)
+	assert.Equal(t, domain.TriggerSourceCron, dequeued.TriggerSource)
+
+	// Test Dequeue from empty queue
+	dequeued, err = repo.Dequeue(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, dequeued)
+}
+
+func TestJobRepository_Dequeue_FinishedAfterPop(t *testing.T) {
+	client := setupTestRedis(t)
+	repo := redis.NewJobRepository(client)
+	ctx := context.Background()
+
+	// Store the job as cancelled while its ID is still in the pending list, as
+	// if it was cancelled between the pop and the claim
+	job := newPendingJob()
+	require.NoError(t, repo.Enqueue(ctx, job))
+	cancelled := *job
+	cancelled.MarkAsCancelled()
+	data, err := json.Marshal(redis.ToJobDTO(&cancelled))
+	require.NoError(t, err)
+	require.NoError(t, client.Set(ctx, "scheduler:job:"+job.ID, data, 0).Err())
+
+	dequeued, err := repo.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, dequeued)
+
+	processing, err := client.ZCard(ctx, "scheduler:jobs:processing").Result()
+	require.NoError(t, err)
+	assert.Zero(t, processing)
+	stored, err := repo.FindByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusCancelled, stored.Status)
+}
+
+func TestJobRepository_Enqueue_DuplicateID(t *testing.T) {
+	repo := redis.NewJobRepository(setupTestRedis(t))
+	ctx := context.Background()
+
+	job := newPendingJob()
+	require.NoError(t, repo.Enqueue(ctx, job))
+
+	err := repo.Enqueue(ctx, job)
+	assert.ErrorIs(t, err, domain.ErrConstraintViolation)
+}
+
+func TestJobRepository_Enqueue_DuplicateSchedule(t *testing.T) {
+	repo := redis.NewJobRepository(setupTestRedis(t))
+	ctx := context.Background()
+
+	job := newPendingJob()
+	require.NoError(t, repo.Enqueue(ctx, job))
+
+	// Same task and scheduled time with another ID, expressed in another time zone
+	jst := time.FixedZone("JST", 9*60*60)
+	duplicate := newPendingJob()
+	duplicate.TaskID = job.TaskID
+	duplicate.ScheduledAt = job.ScheduledAt.In(jst)
+	err := repo.Enqueue(ctx, duplicate)
+	assert.ErrorIs(t, err, domain.ErrConstraintViolation)
+
+	// Only the first job is queued
+	dequeued, err := repo.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, dequeued)
+	assert.Equal(t, job.ID, dequeued.ID)
+	dequeued, err = repo.Dequeue(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, dequeued)
+}
+
+func TestJobRepository_UpdateStatus(t *testing.T) {
+	client := setupTestRedis(t)
+	repo := redis.NewJobRepository(client)
+	ctx := context.Background()
+
+	job := newPendingJob()
+	require.NoError(t, repo.Enqueue(ctx, job))
+	_, err := repo.Dequeue(ctx)
+	require.NoError(t, err)
+
+	err = repo.UpdateStatus(ctx, job.ID, domain.JobStatusRunning)
+	assert.NoError(t, err)
+	processing, err := client.ZCard(ctx, "scheduler:jobs:processing").Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), processing, "running job should stay in the processing set")
+
+	err = repo.UpdateStatus(ctx, job.ID, domain.JobStatusSuccess)
+	assert.NoError(t, err)
+	processing, err = client.ZCard(ctx, "scheduler:jobs:processing").Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), processing, "finished job should be removed from the processing set")
+
+	// A finished job never has an expired lease
+	expired, err := repo.FindExpiredLeases(ctx, time.Now().Add(time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Empty(t, expired)
+
+	// Test UpdateStatus on non-existent job (should not error)
+	err = repo.UpdateStatus(ctx, "nonexistent", domain.JobStatusSuccess)
+	assert.NoError(t, err)
+}
+
+func TestJobRepository_Lease(t *testing.T) {
+	repo := redis.NewJobRepository(setupTestRedis(t), redis.WithVisibilityTimeout(time.Minute))
+	ctx := context.Background()
+
+	job := newPendingJob()
+	require.NoError(t, repo.Enqueue(ctx, job))
+	dequeued, err := repo.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, dequeued)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), dequeued.LeaseExpiresAt, time.Second)
+	require.NoError(t, repo.UpdateStatus(ctx, job.ID, domain.JobStatusRunning))
+
+	// The lease is stored with the job
+	stored, err := repo.FindByID(ctx, job.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.True(t, dequeued.LeaseExpiresAt.Equal(stored.LeaseExpiresAt))
+
+	// The lease has not expired yet
+	expired, err := repo.FindExpiredLeases(ctx, time.Now(), 10)
+	assert.NoError(t, err)
+	assert.Empty(t, expired)
+
+	// Simulate a crashed executor: the lease is never extended
+	expired, err = repo.FindExpiredLeases(ctx, time.Now().Add(2*time.Minute), 10)
+	assert.NoError(t, err)
+	if assert.Len(t, expired, 1) {
+		assert.Equal(t, job.ID, expired[0].ID)
+		assert.Equal(t, domain.JobStatusRunning, expired[0].Status)
+	}
+
+	// A heartbeat pushes the lease forward
+	time.Sleep(10 * time.Millisecond)
+	require.NoError(t, repo.ExtendLease(ctx, job.ID))
+	expired, err = repo.FindExpiredLeases(ctx, dequeued.LeaseExpiresAt.Add(time.Millisecond), 10)
+	assert.NoError(t, err)
+	assert.Empty(t, expired)
+	stored, err = repo.FindByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.LeaseExpiresAt.After(dequeued.LeaseExpiresAt), "the extended lease is stored with the job")
+
+	// Extending the lease of a job that is not being processed is a no-op
+	assert.NoError(t, repo.ExtendLease(ctx, "nonexistent"))
+	expired, err = repo.FindExpiredLeases(ctx, time.Now().Add(time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Len(t, expired, 1)
+}
+
+func TestJobRepository_Requeue(t *testing.T) {
+	client := setupTestRedis(t)
+	repo := redis.NewJobRepository(client)
+	ctx := context.Background()
+
+	job := newPendingJob()
+	require.NoError(t, repo.Enqueue(ctx, job))
+	dequeued, err := repo.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, dequeued)
+
+	// Requeue with a delay: the job must not be dequeued before AvailableAt
+	dequeued.ScheduleRetry(time.Now().Add(time.Hour))
+	require.NoError(t, repo.Requeue(ctx, dequeued))
+
+	notYet, err := repo.Dequeue(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, notYet)
+
+	// A requeued job is no longer held by an executor
+	expired, err := repo.FindExpiredLeases(ctx, time.Now().Add(time.Hour), 10)
+	assert.NoError(t, err)
+	assert.Empty(t, expired)
+
+	// Requeue with an elapsed delay: the job is dequeued immediately
+	dequeued.ScheduleRetry(time.Now().Add(-time.Second))
+	require.NoError(t, repo.Requeue(ctx, dequeued))
+
+	retried, err := repo.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, retried)
+	assert.Equal(t, job.ID, retried.ID)
+	assert.Equal(t, 2, retried.RetryCount)
+
+	// The job was requeued twice but must be queued only once
+	again, err := repo.Dequeue(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, again)
+	delayed, err := client.ZCard(ctx, "scheduler:jobs:delayed").Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), delayed)
+}
+
+func TestJobRepository_ConcurrentDequeue(t *testing.T) {
+	repo := redis.NewJobRepository(setupTestRedis(t))
+	ctx := context.Background()
+
+	const numJobs = 20
+	for i := 0; i < numJobs; i++ {
+		require.NoError(t, repo.Enqueue(ctx, newPendingJob()))
+	}
+
+	const numWorkers = 5
+	var (
+		mu      sync.Mutex
+		claimed = make(map[string]int)
+		wg      sync.WaitGroup
+	)
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				job, err := repo.Dequeue(ctx)
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+				if job == nil {
+					return
+				}
+				mu.Lock()
+				claimed[job.ID]++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, claimed, numJobs, "every job should be claimed")
+	for id, count := range claimed {
+		assert.Equal(t, 1, count, "job %s should be claimed exactly once", id)
+	}
+}
+
+func TestJobRepository_Results(t *testing.T) {
+	repo := redis.NewJobRepository(setupTestRedis(t))
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+
+	first := &domain.JobResult{
+		JobID:           "job1",
+		TaskID:          "task1",
+		Attempt:         1,
+		StatusCode:      500,
+		ResponseHeaders: map[string]string{"Content-Type": "text/plain"},
+		ResponseBody:    []byte("internal error"),
+		Error:           "unexpected status code: 500",
+		StartedAt:       now.Add(-2*time.Minute - time.Second),
+		FinishedAt:      now.Add(-2 * time.Minute),
+		Duration:        time.Second,
+	}
+	second := &domain.JobResult{JobID: "job1", TaskID: "task1", Attempt: 2, StatusCode: 200, FinishedAt: now.Add(-time.Minute)}
+	other := &domain.JobResult{JobID: "job2", TaskID: "task1", Attempt: 1, StatusCode: 200, FinishedAt: now}
+	for _, result := range []*domain.JobResult{first, second, other} {
+		require.NoError(t, repo.SaveResult(ctx, result))
+	}
+
+	// Results of a job are ordered by attempt
+	results, err := repo.FindResultsByJobID(ctx, "job1")
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, first.StatusCode, results[0].StatusCode)
+	assert.Equal(t, first.ResponseHeaders, results[0].ResponseHeaders)
+	assert.Equal(t, first.ResponseBody, results[0].ResponseBody)
+	assert.Equal(t, first.Error, results[0].Error)
+	assert.Equal(t, first.Duration, results[0].Duration)
+	assert.True(t, first.FinishedAt.Equal(results[0].FinishedAt))
+	assert.Equal(t, 2, results[1].Attempt)
+
+	// Recent results of a task are ordered from the most recently finished
+	results, err = repo.FindRecentResultsByTaskID(ctx, "task1", 2)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "job2", results[0].JobID)
+	assert.Equal(t, 2, results[1].Attempt)
+
+	results, err = repo.FindResultsByJobID(ctx, "nonexistent")
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestJobRepository_FindByIDAndTaskID(t *testing.T) {
+	repo := redis.NewJobRepository(setupTestRedis(t))
+	ctx := context.Background()
+
+	older := newPendingJob()
+	older.ScheduledAt = older.ScheduledAt.Add(-time.Minute)
+	newer := newPendingJob()
+	newer.TaskID = older.TaskID
+	require.NoError(t, repo.Enqueue(ctx, older))
+	require.NoError(t, repo.Enqueue(ctx, newer))
+	require.NoError(t, repo.Enqueue(ctx, newPendingJob()))
+
+	job, err := repo.FindByID(ctx, older.ID)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	assert.Equal(t, older.TaskID, job.TaskID)
+
+	job, err = repo.FindByID(ctx, "nonexistent")
+	require.NoError(t, err)
+	assert.Nil(t, job)
+
+	// Jobs of a task are ordered from the most recently scheduled
+	jobs, err := repo.FindByTaskID(ctx, older.TaskID, 10)
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, newer.ID, jobs[0].ID)
+	assert.Equal(t, older.ID, jobs[1].ID)
+
+	jobs, err = repo.FindByTaskID(ctx, older.TaskID, 1)
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1)
+}
+
+func TestJobRepository_Cancel(t *testing.T) {
+	repo := redis.NewJobRepository(setupTestRedis(t))
+	ctx := context.Background()
+
+	running := newPendingJob()
+	running.ScheduledAt = running.ScheduledAt.Add(-2 * time.Minute)
+	pending := newPendingJob()
+	pending.TaskID = running.TaskID
+	pending.ScheduledAt = pending.ScheduledAt.Add(-time.Minute)
+	delayed := newPendingJob()
+	delayed.TaskID = running.TaskID
+	delayed.AvailableAt = time.Now().Add(time.Hour)
+	finished := newPendingJob()
+	finished.TaskID = running.TaskID
+	finished.ScheduledAt = finished.ScheduledAt.Add(-3 * time.Minute)
+	for _, job := range []*domain.Job{running, finished, pending, delayed} {
+		require.NoError(t, repo.Enqueue(ctx, job))
+	}
+
+	dequeued, err := repo.Dequeue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, running.ID, dequeued.ID)
+	require.NoError(t, repo.UpdateStatus(ctx, running.ID, domain.JobStatusRunning))
+	dequeued, err = repo.Dequeue(ctx)
+	require.NoError(t, err)
+	require.Equal(t, finished.ID, dequeued.ID)
+	require.NoError(t, repo.UpdateStatus(ctx, finished.ID, domain.JobStatusSuccess))
+
+	// Only unfinished jobs are active, earliest scheduled first
+	active, err := repo.FindActiveByTaskID(ctx, running.TaskID)
+	require.NoError(t, err)
+	require.Len(t, active, 3)
+	assert.Equal(t, running.ID, active[0].ID)
+	assert.Equal(t, pending.ID, active[1].ID)
+	assert.Equal(t, delayed.ID, active[2].ID)
+
+	for _, id := range []string{running.ID, pending.ID, delayed.ID, finished.ID, "nonexistent"} {
+		require.NoError(t, repo.Cancel(ctx, id))
+	}
+
+	active, err = repo.FindActiveByTaskID(ctx, running.TaskID)
+	require.NoError(t, err)
+	assert.Empty(t, active)
+
+	// Cancelled jobs are removed from every queue
+	dequeued, err = repo.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, dequeued)
+	expired, err := repo.FindExpiredLeases(ctx, time.Now().Add(time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, expired)
+
+	// Finished jobs are not cancelled
+	job, err := repo.FindByID(ctx, finished.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusSuccess, job.Status)
+
+	// The status of a cancelled job does not change afterwards
+	require.NoError(t, repo.UpdateStatus(ctx, running.ID, domain.JobStatusSuccess))
+	job, err = repo.FindByID(ctx, running.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusCancelled, job.Status)
+	assert.False(t, job.FinishedAt.IsZero())
+
+	job.ScheduleRetry(time.Time{})
+	require.NoError(t, repo.Requeue(ctx, job))
+	job, err = repo.FindByID(ctx, running.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusCancelled, job.Status)
+	dequeued, err = repo.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, dequeued)
+}
+
+func TestJobRepository_CountPending(t *testing.T) {
+	repo := redis.NewJobRepository(setupTestRedis(t))
+	ctx := context.Background()
+
+	first := newPendingJob()
+	second := newPendingJob()
+	require.NoError(t, repo.Enqueue(ctx, first))
+	require.NoError(t, repo.Enqueue(ctx, second))
+	count, err := repo.CountPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	// A dequeued job is no longer pending, but it is counted again while waiting for a retry
+	dequeued, err := repo.Dequeue(ctx)
+	require.NoError(t, err)
+	count, err = repo.CountPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	dequeued.ScheduleRetry(time.Now().Add(time.Hour))
+	require.NoError(t, repo.Requeue(ctx, dequeued))
+	count, err = repo.CountPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestJobRepository_DeleteFinished(t *testing.T) {
+	client := setupTestRedis(t)
+	repo := redis.NewJobRepository(client)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	newFinishedJob := func(taskID string, age time.Duration, status domain.JobStatus) *domain.Job {
+		job := newPendingJob()
+		job.TaskID = taskID
+		job.ScheduledAt = now.Add(-age)
+		job.Status = status
+		job.FinishedAt = now.Add(-age)
+		require.NoError(t, repo.Enqueue(ctx, job))
+		return job
+	}
+
+	taskA, taskB := uuid.NewString(), uuid.NewString()
+	oldSuccess := newFinishedJob(taskA, 3*time.Hour, domain.JobStatusSuccess)
+	recentSuccess := newFinishedJob(taskA, time.Minute, domain.JobStatusSuccess)
+	oldFailure := newFinishedJob(taskA, 150*time.Minute, domain.JobStatusFailed)
+	otherTask := newFinishedJob(taskB, 3*time.Hour, domain.JobStatusSuccess)
+	pending := newPendingJob()
+	pending.TaskID = taskA
+	pending.ScheduledAt = now.Add(-4 * time.Hour)
+	require.NoError(t, repo.Enqueue(ctx, pending))
+	require.NoError(t, repo.SaveResult(ctx, &domain.JobResult{
+		JobID: oldSuccess.ID, TaskID: taskA, Attempt: 1, StartedAt: now, FinishedAt: now,
+	}))
+	require.NoError(t, repo.SaveResult(ctx, &domain.JobResult{
+		JobID: recentSuccess.ID, TaskID: taskA, Attempt: 1, StartedAt: now, FinishedAt: now,
+	}))
+
+	// Only the old completed job of task A is older than an hour
+	filter := domain.NewJobPruneFilter(domain.RetentionRule{MaxAge: time.Hour}, domain.CompletedJobStatuses, now)
+	filter.TaskID = taskA
+	deleted, err := repo.DeleteFinished(ctx, filter, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	for _, job := range []*domain.Job{recentSuccess, oldFailure, otherTask, pending} {
+		found, err := repo.FindByID(ctx, job.ID)
+		require.NoError(t, err)
+		assert.NotNil(t, found, "job %s should be kept", job.ID)
+	}
+	found, err := repo.FindByID(ctx, oldSuccess.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+	jobs, err := repo.FindByTaskID(ctx, taskA, 10)
+	require.NoError(t, err)
+	assert.Len(t, jobs, 3)
+
+	results, err := repo.FindResultsByJobID(ctx, oldSuccess.ID)
+	require.NoError(t, err)
+	assert.Empty(t, results, "results of a deleted job are deleted with it")
+	results, err = repo.FindRecentResultsByTaskID(ctx, taskA, 10)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, recentSuccess.ID, results[0].JobID)
+
+	// The schedule key is deleted with the job, so it does not stay in Redis forever
+	scheduleKeys, err := client.Keys(ctx, "scheduler:schedule:*").Result()
+	require.NoError(t, err)
+	assert.Len(t, scheduleKeys, 4)
+
+	// Keeping the latest job of every task except task A deletes nothing, since task B has a single job
+	filter = domain.NewJobPruneFilter(domain.RetentionRule{MaxJobs: 1}, domain.CompletedJobStatuses, now)
+	filter.ExcludeTaskIDs = []string{taskA}
+	deleted, err = repo.DeleteFinished(ctx, filter, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 0, deleted)
+}
+
+func TestJobRepository_ReclaimExpired_Shared(t *testing.T) {
+	repotest.TestReclaimExpired(t, func(t *testing.T) domain.JobRepository {
+		return redis.NewJobRepository(setupTestRedis(t))
+	})
+}
+
+func TestJobRepository_DeleteFinished_Shared(t *testing.T) {
+	repotest.TestDeleteFinished(t, func(t *testing.T) repotest.JobHistoryRepository {
+		return redis.NewJobRepository(setupTestRedis(t))
+	})
+}
+
+func TestJobRepository_DeleteFinished_KeepLatestInBatches(t *testing.T) {
+	repo := redis.NewJobRepository(setupTestRedis(t))
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	taskID := uuid.NewString()
+	var jobs []*domain.Job
+	for i := range 5 {
+		job := newPendingJob()
+		job.TaskID = taskID
+		job.ScheduledAt = now.Add(time.Duration(i) * time.Minute)
+		job.Status = domain.JobStatusFailed
+		job.FinishedAt = now
+		require.NoError(t, repo.Enqueue(ctx, job))
+		jobs = append(jobs, job)
+	}
+
+	filter := domain.NewJobPruneFilter(domain.RetentionRule{MaxJobs: 2}, domain.FailedJobStatuses, now)
+	deleted, err := repo.DeleteFinished(ctx, filter, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+	deleted, err = repo.DeleteFinished(ctx, filter, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	remaining, err := repo.FindByTaskID(ctx, taskID, 10)
+	require.NoError(t, err)
+	require.Len(t, remaining, 2)
+	assert.Equal(t, jobs[4].ID, remaining[0].ID)
+	assert.Equal(t, jobs[3].ID, remaining[1].ID)
+
+	// A pruned scheduled time is no longer marked as enqueued
+	jobs[0].ID = uuid.NewString()
+	assert.NoError(t, repo.Enqueue(ctx, jobs[0]))
+}
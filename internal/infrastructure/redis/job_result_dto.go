@@ -0,0 +1,56 @@
+package redis
+
+import (
+	"time"
+
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+)
+
+// JobResultDTO represents the JSON structure of a JobResult stored in Redis.
+type JobResultDTO struct {
+	JobID           string            `json:"job_id"`
+	TaskID          string            `json:"task_id"`
+	Attempt         int               `json:"attempt"`
+	StatusCode      int               `json:"status_code"`
+	ResponseHeaders map[string]string `json:"response_headers"`
+	ResponseBody    []byte            `json:"response_body"` // base64-encoded by encoding/json
+	BodyTruncated   bool              `json:"body_truncated"`
+	Error           string            `json:"error"`
+	StartedAt       time.Time         `json:"started_at"`
+	FinishedAt      time.Time         `json:"finished_at"`
+	DurationMS      int64             `json:"duration_ms"`
+}
+
+// ToJobResultDTO converts a domain JobResult to a JobResultDTO.
+func ToJobResultDTO(result *domain.JobResult) *JobResultDTO {
+	return &JobResultDTO{
+		JobID:           result.JobID,
+		TaskID:          result.TaskID,
+		Attempt:         result.Attempt,
+		StatusCode:      result.StatusCode,
+		ResponseHeaders: result.ResponseHeaders,
+		ResponseBody:    result.ResponseBody,
+		BodyTruncated:   result.BodyTruncated,
+		Error:           result.Error,
+		StartedAt:       result.StartedAt,
+		FinishedAt:      result.FinishedAt,
+		DurationMS:      result.Duration.Milliseconds(),
+	}
+}
+
+// ToDomain converts a JobResultDTO to a domain JobResult.
+func (dto *JobResultDTO) ToDomain() *domain.JobResult {
+	return &domain.JobResult{
+		JobID:           dto.JobID,
+		TaskID:          dto.TaskID,
+		Attempt:         dto.Attempt,
+		StatusCode:      dto.StatusCode,
+		ResponseHeaders: dto.ResponseHeaders,
+		ResponseBody:    dto.ResponseBody,
+		BodyTruncated:   dto.BodyTruncated,
+		Error:           dto.Error,
+		StartedAt:       dto.StartedAt,
+		FinishedAt:      dto.FinishedAt,
+		Duration:        time.Duration(dto.DurationMS) * time.Millisecond,
+	}
+}
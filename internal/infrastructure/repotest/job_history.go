@@ -0,0 +1,93 @@
+// Package repotest provides tests shared by every storage backend, so that the
+// backends behave the same for the same input.
+package repotest
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+)
+
+// JobHistoryRepository is a job repository that also deletes the history of finished jobs.
+type JobHistoryRepository interface {
+	domain.JobRepository
+	domain.JobHistoryRepository
+}
+
+// TestDeleteFinished checks which finished jobs DeleteFinished deletes for each
+// combination of TaskID and ExcludeTaskIDs. newRepo must return an empty repository.
+func TestDeleteFinished(t *testing.T, newRepo func(t *testing.T) JobHistoryRepository) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	taskA, taskB := uuid.NewString(), uuid.NewString()
+
+	// setup stores two jobs of each task, one finished three hours ago and one a minute ago
+	setup := func(t *testing.T) (JobHistoryRepository, map[string][]string) {
+		t.Helper()
+
+		repo := newRepo(t)
+		jobIDs := make(map[string][]string)
+		for _, taskID := range []string{taskA, taskB} {
+			for _, age := range []time.Duration{3 * time.Hour, time.Minute} {
+				job := &domain.Job{
+					ID:          uuid.NewString(),
+					TaskID:      taskID,
+					ScheduledAt: now.Add(-age),
+					Status:      domain.JobStatusSuccess,
+					FinishedAt:  now.Add(-age),
+					CreatedAt:   now,
+					UpdatedAt:   now,
+				}
+				require.NoError(t, repo.Enqueue(context.Background(), job))
+				jobIDs[taskID] = append(jobIDs[taskID], job.ID)
+			}
+		}
+		return repo, jobIDs
+	}
+
+	tests := []struct {
+		name           string
+		taskID         string
+		excludeTaskIDs []string
+		// deletedTasks are the tasks whose old job is deleted
+		deletedTasks []string
+	}{
+		{name: "every task", deletedTasks: []string{taskA, taskB}},
+		{name: "one task", taskID: taskA, deletedTasks: []string{taskA}},
+		{name: "every task except excluded", excludeTaskIDs: []string{taskA}, deletedTasks: []string{taskB}},
+		{name: "excluded task", taskID: taskA, excludeTaskIDs: []string{taskA}},
+		{name: "one task with another excluded", taskID: taskA, excludeTaskIDs: []string{taskB}, deletedTasks: []string{taskA}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo, jobIDs := setup(t)
+
+			filter := domain.NewJobPruneFilter(domain.RetentionRule{MaxAge: time.Hour}, domain.CompletedJobStatuses, now)
+			filter.TaskID = tt.taskID
+			filter.ExcludeTaskIDs = tt.excludeTaskIDs
+			deleted, err := repo.DeleteFinished(ctx, filter, 100)
+			require.NoError(t, err)
+			assert.Equal(t, len(tt.deletedTasks), deleted)
+
+			for _, taskID := range []string{taskA, taskB} {
+				old, err := repo.FindByID(ctx, jobIDs[taskID][0])
+				require.NoError(t, err)
+				if slices.Contains(tt.deletedTasks, taskID) {
+					assert.Nil(t, old, "the old job of task %s should be deleted", taskID)
+				} else {
+					assert.NotNil(t, old, "the old job of task %s should be kept", taskID)
+				}
+
+				recent, err := repo.FindByID(ctx, jobIDs[taskID][1])
+				require.NoError(t, err)
+				assert.NotNil(t, recent, "recent jobs are kept")
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+)
+
+// TestReclaimExpired checks that ReclaimExpired stores a job updated by the
+// reaper only while the stored job is still running with an expired lease.
+// newRepo must return an empty repository using the default visibility timeout.
+func TestReclaimExpired(t *testing.T, newRepo func(t *testing.T) domain.JobRepository) {
+	// dequeue enqueues a job and dequeues it, leaving it running with a lease
+	dequeue := func(t *testing.T, repo domain.JobRepository) *domain.Job {
+		t.Helper()
+
+		now := time.Now().UTC().Truncate(time.Millisecond)
+		job := &domain.Job{
+			ID:          uuid.NewString(),
+			TaskID:      uuid.NewString(),
+			ScheduledAt: now,
+			Status:      domain.JobStatusPending,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		ctx := context.Background()
+		require.NoError(t, repo.Enqueue(ctx, job))
+		dequeued, err := repo.Dequeue(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, dequeued)
+		require.Equal(t, job.ID, dequeued.ID)
+		return dequeued
+	}
+	expiredAt := time.Now().Add(time.Hour)
+
+	t.Run("retry", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		job := dequeue(t, repo)
+
+		job.ScheduleRetry(time.Time{})
+		reclaimed, err := repo.ReclaimExpired(ctx, job, expiredAt)
+		require.NoError(t, err)
+		assert.True(t, reclaimed)
+
+		stored, err := repo.FindByID(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.JobStatusPending, stored.Status)
+		assert.Equal(t, 1, stored.RetryCount)
+		assert.True(t, stored.LeaseExpiresAt.IsZero())
+		expired, err := repo.FindExpiredLeases(ctx, expiredAt, 10)
+		require.NoError(t, err)
+		assert.Empty(t, expired)
+
+		retried, err := repo.Dequeue(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, retried)
+		assert.Equal(t, job.ID, retried.ID)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		job := dequeue(t, repo)
+
+		job.MarkAsFailed()
+		job.LeaseExpiresAt = time.Time{}
+		reclaimed, err := repo.ReclaimExpired(ctx, job, expiredAt)
+		require.NoError(t, err)
+		assert.True(t, reclaimed)
+
+		stored, err := repo.FindByID(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.JobStatusFailed, stored.Status)
+		active, err := repo.FindActiveByTaskID(ctx, job.TaskID)
+		require.NoError(t, err)
+		assert.Empty(t, active)
+		expired, err := repo.FindExpiredLeases(ctx, expiredAt, 10)
+		require.NoError(t, err)
+		assert.Empty(t, expired)
+	})
+
+	t.Run("finished after the lookup", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		job := dequeue(t, repo)
+		require.NoError(t, repo.UpdateStatus(ctx, job.ID, domain.JobStatusSuccess))
+
+		job.ScheduleRetry(time.Time{})
+		reclaimed, err := repo.ReclaimExpired(ctx, job, expiredAt)
+		require.NoError(t, err)
+		assert.False(t, reclaimed)
+
+		stored, err := repo.FindByID(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.JobStatusSuccess, stored.Status)
+		assert.Equal(t, 0, stored.RetryCount)
+		dequeued, err := repo.Dequeue(ctx)
+		require.NoError(t, err)
+		assert.Nil(t, dequeued)
+	})
+
+	t.Run("lease not expired", func(t *testing.T) {
+		ctx := context.Background()
+		repo := newRepo(t)
+		job := dequeue(t, repo)
+
+		job.MarkAsFailed()
+		reclaimed, err := repo.ReclaimExpired(ctx, job, time.Now())
+		require.NoError(t, err)
+		assert.False(t, reclaimed)
+
+		stored, err := repo.FindByID(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.JobStatusRunning, stored.Status)
+	})
+}
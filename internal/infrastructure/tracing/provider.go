@@ -0,0 +1,102 @@
+// Package tracing sets up the OpenTelemetry SDK that records the scheduler's
+// spans and exports them to an OTLP collector.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const (
+	// DefaultServiceName is the service.name reported unless WithServiceName is given.
+	DefaultServiceName = "go-dist-scheduler"
+	// defaultURLPath is where spans are sent when the endpoint has no path.
+	defaultURLPath = "/v1/traces"
+)
+
+// providerOptions holds the settings applied by Option.
+type providerOptions struct {
+	sampleRatio float64
+	serviceName string
+}
+
+// Option configures the tracer provider created by NewTracerProvider.
+type Option func(*providerOptions)
+
+// WithSampleRatio sets the fraction of new traces that are sampled, from 0 to 1.
+// The default is 1, which samples every trace.
+func WithSampleRatio(ratio float64) Option {
+	return func(o *providerOptions) {
+		o.sampleRatio = ratio
+	}
+}
+
+// WithServiceName sets the service.name resource attribute of the exported spans.
+func WithServiceName(name string) Option {
+	return func(o *providerOptions) {
+		o.serviceName = name
+	}
+}
+
+// NewTracerProvider creates a tracer provider that exports spans in batches
+// over OTLP/HTTP to endpoint, a URL such as "http://localhost:4318". An
+// endpoint without a path sends spans to /v1/traces, and an http:// endpoint
+// is used without TLS. Traces continued from a remote parent follow the
+// parent's sampling decision. The caller must shut the provider down to flush
+// the spans that have not been exported yet.
+func NewTracerProvider(ctx context.Context, endpoint string, opts ...Option) (*sdktrace.TracerProvider, error) {
+	o := providerOptions{
+		sampleRatio: 1,
+		serviceName: DefaultServiceName,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	exporterOpts, err := exporterOptions(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	exporter, err := otlptracehttp.New(ctx, exporterOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create span exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(semconv.ServiceName(o.serviceName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(o.sampleRatio))),
+	), nil
+}
+
+// exporterOptions converts the endpoint URL into OTLP/HTTP exporter options.
+func exporterOptions(endpoint string) ([]otlptracehttp.Option, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint %q: must be an http or https URL", endpoint)
+	}
+
+	path := u.Path
+	if path == "" || path == "/" {
+		path = defaultURLPath
+	}
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(u.Host),
+		otlptracehttp.WithURLPath(path),
+	}
+	if u.Scheme == "http" {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	return opts, nil
+}
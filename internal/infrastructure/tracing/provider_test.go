@@ -0,0 +1,85 @@
+package tracing_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/tracing"
+)
+
+// collector records the paths of the OTLP export requests it receives.
+type collector struct {
+	mu    sync.Mutex
+	paths []string
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	c.paths = append(c.paths, r.URL.Path)
+	c.mu.Unlock()
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.WriteHeader(http.StatusOK)
+}
+
+func (c *collector) received() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.paths...)
+}
+
+func TestNewTracerProvider_ExportsSpans(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		wantPath string
+	}{
+		{name: "default path", path: "", wantPath: "/v1/traces"},
+		{name: "custom path", path: "/otlp/v1/traces", wantPath: "/otlp/v1/traces"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c := &collector{}
+			server := httptest.NewServer(c)
+			defer server.Close()
+
+			tp, err := tracing.NewTracerProvider(ctx, server.URL+tt.path, tracing.WithServiceName("test"))
+			require.NoError(t, err)
+
+			_, span := tp.Tracer("test").Start(ctx, "schedule job")
+			span.End()
+			require.NoError(t, tp.Shutdown(ctx))
+
+			assert.Equal(t, []string{tt.wantPath}, c.received())
+		})
+	}
+}
+
+func TestNewTracerProvider_SampleRatio(t *testing.T) {
+	ctx := context.Background()
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	tp, err := tracing.NewTracerProvider(ctx, server.URL, tracing.WithSampleRatio(0))
+	require.NoError(t, err)
+
+	_, span := tp.Tracer("test").Start(ctx, "schedule job")
+	assert.False(t, span.SpanContext().IsSampled())
+	span.End()
+	require.NoError(t, tp.Shutdown(ctx))
+
+	assert.Empty(t, c.received(), "unsampled spans are not exported")
+}
+
+func TestNewTracerProvider_InvalidEndpoint(t *testing.T) {
+	for _, endpoint := range []string{"", "localhost:4318", "grpc://localhost:4317", "http://"} {
+		_, err := tracing.NewTracerProvider(context.Background(), endpoint)
+		assert.Error(t, err, endpoint)
+	}
+}
@@ -1,48 +1,442 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/yourname/go-dist-scheduler/internal/domain"
+	"github.com/yourname/go-dist-scheduler/pkg/webhook"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// HTTPClient は、ジョブのHTTPリクエストを送信するクライアントのインターフェースです。
+// *http.Client はこのインターフェースを満たします。
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
 // Executor は、ペンディング中のジョブを実行する責務を担当します。
 type Executor struct {
-	jobRepo domain.JobRepository
+	taskRepo   domain.TaskRepository
+	jobRepo    domain.JobRepository
+	httpClient HTTPClient
+	// random は、リトライ間隔のジッター計算に使用する [0.0, 1.0) の乱数を返します。
+	random func() float64
+	// heartbeatInterval は、実行中のジョブのリースを延長する間隔です。
+	heartbeatInterval time.Duration
+	observer          ExecutorObserver
+	logger            *slog.Logger
+	tracer            trace.Tracer
+	// running は、実行中のジョブの数です。
+	running atomic.Int64
+}
+
+// ExecutorOption は、Executor の設定を変更するオプションです。
+type ExecutorOption func(*Executor)
+
+// WithHeartbeatInterval は、実行中のジョブのリースを延長する間隔を設定します。
+// リポジトリの可視性タイムアウトより十分短い値を指定してください。
+func WithHeartbeatInterval(d time.Duration) ExecutorOption {
+	return func(e *Executor) {
+		e.heartbeatInterval = d
+	}
+}
+
+// WithExecutorObserver は、Executor の処理を観測するフックを設定します。
+func WithExecutorObserver(o ExecutorObserver) ExecutorOption {
+	return func(e *Executor) {
+		e.observer = o
+	}
+}
+
+// WithExecutorLogger は、Executor がログを出力するロガーを設定します。
+func WithExecutorLogger(l *slog.Logger) ExecutorOption {
+	return func(e *Executor) {
+		e.logger = l
+	}
+}
+
+// WithExecutorTracerProvider は、ジョブの実行のトレースを記録する TracerProvider を設定します。
+func WithExecutorTracerProvider(tp trace.TracerProvider) ExecutorOption {
+	return func(e *Executor) {
+		e.tracer = tp.Tracer(tracerName)
+	}
 }
 
 // NewExecutor は新しいExecutorインスタンスを生成します。
-func NewExecutor(jobRepo domain.JobRepository) *Executor {
-	return &Executor{
-		jobRepo: jobRepo,
+// ハートビート間隔のデフォルトは domain.DefaultVisibilityTimeout の1/3で、
+// ログはデフォルトで slog.Default() に、トレースはデフォルトでグローバルな TracerProvider（otel.GetTracerProvider）に記録します。
+func NewExecutor(taskRepo domain.TaskRepository, jobRepo domain.JobRepository, httpClient HTTPClient, opts ...ExecutorOption) *Executor {
+	e := &Executor{
+		taskRepo:          taskRepo,
+		jobRepo:           jobRepo,
+		httpClient:        httpClient,
+		random:            rand.Float64,
+		heartbeatInterval: domain.DefaultVisibilityTimeout / 3,
+		observer:          nopObserver{},
+		logger:            slog.Default(),
+		tracer:            otel.GetTracerProvider().Tracer(tracerName),
+	}
+	for _, opt := range opts {
+		opt(e)
 	}
+	return e
 }
 
 // RunPendingJob は、キューから1つのジョブをデキューして実行します。
+// ジョブに紐づくタスクのHTTPリクエストを送信し、レスポンスのステータスコードが2xxであれば
+// Success、それ以外（送信エラーを含む）であればFailedとしてジョブのステータスを更新します。
+// タスクの Timeout、または HTTPClient のタイムアウトまでに終わらなかった実行は失敗として扱い、
+// リトライが残っていなければ Failed ではなく TimedOut とします。
+// 各試行の実行結果（レスポンスやエラー、所要時間）は JobResult として保存します。
+// デキュー・実行・HTTPリクエストはジョブに保存されたトレースのスパンとして記録し、
+// HTTPリクエストには traceparent ヘッダーを付与します。
+// 失敗時、タスクのリトライポリシーで再試行が許可されていれば、バックオフ後に再実行されるよう
+// ジョブをキューへ戻します。
+// ctx が domain.ErrJobInterrupted を原因としてキャンセルされた場合は実行を中断し、
+// リトライ回数を加算せずにジョブを Pending へ戻します。
 func (e *Executor) RunPendingJob(ctx context.Context) error {
+	_, err := e.RunNext(ctx)
+	return err
+}
+
+// RunNext は RunPendingJob と同様に1つのジョブを実行し、ジョブをデキューできたかを併せて返します。
+// キューが空の場合は false を返します。
+func (e *Executor) RunNext(ctx context.Context) (bool, error) {
+	dequeuedAt := time.Now()
 	job, err := e.jobRepo.Dequeue(ctx)
 	if err != nil {
-		return err
+		return false, err
 	}
 	if job == nil {
-		return nil
+		return false, nil
 	}
 
+	// デキューするまでどのジョブのトレースか分からないため、デキューのスパンは後から記録する
+	ctx = jobTraceContext(ctx, job)
+	_, span := e.tracer.Start(ctx, "dequeue job", trace.WithTimestamp(dequeuedAt), trace.WithAttributes(jobAttributes(job)...))
+	span.End()
+
+	return true, e.run(ctx, job)
+}
+
+// Running は、この Executor で実行中のジョブの数を返します。
+func (e *Executor) Running() int {
+	return int(e.running.Load())
+}
+
+// run は、デキューしたジョブを実行し、結果に応じてステータスを更新します。
+func (e *Executor) run(ctx context.Context, job *domain.Job) error {
+	ctx, span := e.tracer.Start(ctx, "execute job", trace.WithAttributes(jobAttributes(job)...))
+	defer span.End()
+	logger := jobLogger(e.logger, job)
+	e.running.Add(1)
+	defer e.running.Add(-1)
+
 	// Update status to Running
 	if err := e.jobRepo.UpdateStatus(ctx, job.ID, domain.JobStatusRunning); err != nil {
 		return err
 	}
 
-	log.Printf("Executing Job ID: %s", job.ID)
-	time.Sleep(10 * time.Millisecond) // Simulate work
+	logger.Info("executing job")
+	// ジョブが別の実行に置き換えられた場合は、runCtx をキャンセルしてHTTPリクエストを中断する
+	runCtx, cancelRun := context.WithCancelCause(ctx)
+	defer cancelRun(nil)
+	stopHeartbeat := e.startHeartbeat(runCtx, logger, job.ID, cancelRun)
+	startedAt := time.Now()
+	e.observer.JobStarted(job, startedAt)
+	var resp *response
+	task, err := e.findTask(runCtx, job.TaskID)
+	if err == nil {
+		resp, err = e.execute(runCtx, task)
+	}
+	stopHeartbeat()
+	recordSpanError(span, err)
+	if errors.Is(context.Cause(runCtx), domain.ErrJobCancelled) {
+		// ステータスはキャンセル時に更新済みのため、実行結果のみ保存する
+		e.finish(ctx, job, startedAt, resp, domain.ErrJobCancelled)
+		logger.Info("job was cancelled")
+		return nil
+	}
+	if errors.Is(context.Cause(ctx), domain.ErrJobInterrupted) {
+		// シャットダウンで中断された場合、ctx はキャンセル済みのため、ジョブの状態の更新にはキャンセルされないコンテキストを使用する
+		ctx = context.WithoutCancel(ctx)
+		if err != nil {
+			e.finish(ctx, job, startedAt, resp, domain.ErrJobInterrupted)
+			return e.release(ctx, logger, job)
+		}
+	}
+	e.finish(ctx, job, startedAt, resp, err)
+	if err == nil {
+		if err := e.jobRepo.UpdateStatus(ctx, job.ID, domain.JobStatusSuccess); err != nil {
+			logger.Error("failed to update job status", slog.String("status", "Success"), slog.Any("error", err))
+			return err
+		}
+		logger.Info("job succeeded")
+		return nil
+	}
 
-	// Update status to Success
-	if err := e.jobRepo.UpdateStatus(ctx, job.ID, domain.JobStatusSuccess); err != nil {
-		log.Printf("failed to update job %s to Success status: %v", job.ID, err)
+	logger.Warn("job failed", slog.Any("error", err))
+	if task != nil && task.RetryPolicy.ShouldRetry(job.RetryCount) {
+		return e.retry(ctx, logger, job, task.RetryPolicy)
+	}
+
+	// タイムアウトしたジョブは、通常の失敗と区別できるよう TimedOut とする
+	status, statusName := domain.JobStatusFailed, "Failed"
+	if errors.Is(err, domain.ErrJobTimedOut) {
+		status, statusName = domain.JobStatusTimedOut, "TimedOut"
+	}
+	if err := e.jobRepo.UpdateStatus(ctx, job.ID, status); err != nil {
+		logger.Error("failed to update job status", slog.String("status", statusName), slog.Any("error", err))
 		return err
 	}
 
 	return nil
 }
+
+// startHeartbeat は、ジョブの実行中に一定間隔でリースを延長するgoroutineを開始します。
+// あわせてジョブがキャンセルされていないかを確認し、キャンセルされていれば domain.ErrJobCancelled を原因として cancel を呼び出します。
+// 返された関数を呼び出すとハートビートを停止し、goroutineの終了を待ちます。
+func (e *Executor) startHeartbeat(ctx context.Context, logger *slog.Logger, jobID string, cancel context.CancelCauseFunc) func() {
+	if e.heartbeatInterval <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(e.heartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := e.jobRepo.ExtendLease(ctx, jobID); err != nil {
+					logger.Error("failed to extend lease", slog.Any("error", err))
+				}
+				if e.isCancelled(ctx, logger, jobID) {
+					cancel(domain.ErrJobCancelled)
+					return
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// isCancelled は、ジョブがキャンセルされたかをリポジトリで確認します。確認に失敗した場合は false を返します。
+func (e *Executor) isCancelled(ctx context.Context, logger *slog.Logger, jobID string) bool {
+	job, err := e.jobRepo.FindByID(ctx, jobID)
+	if err != nil {
+		logger.Error("failed to check cancellation", slog.Any("error", err))
+		return false
+	}
+	return job != nil && job.Status == domain.JobStatusCancelled
+}
+
+// retry は、リトライポリシーに従って算出した待機時間の後に再実行されるよう、ジョブをキューへ戻します。
+func (e *Executor) retry(ctx context.Context, logger *slog.Logger, job *domain.Job, policy domain.RetryPolicy) error {
+	delay := policy.NextDelay(job.RetryCount, e.random)
+	job.ScheduleRetry(time.Now().Add(delay))
+
+	if err := e.jobRepo.Requeue(ctx, job); err != nil {
+		logger.Error("failed to requeue job for retry", slog.Any("error", err))
+		return err
+	}
+
+	logger.Info("job will be retried",
+		slog.Duration("retry_in", delay), slog.Int("retry", job.RetryCount), slog.Int("max_retries", policy.MaxRetries))
+	return nil
+}
+
+// release は、シャットダウンにより実行を中断したジョブを、他のインスタンスが直ちに取り出せるよう Pending に戻します。
+func (e *Executor) release(ctx context.Context, logger *slog.Logger, job *domain.Job) error {
+	job.Release()
+	if err := e.jobRepo.Requeue(ctx, job); err != nil {
+		logger.Error("failed to release interrupted job", slog.Any("error", err))
+		return err
+	}
+
+	logger.Info("job was interrupted by shutdown and returned to pending")
+	return nil
+}
+
+// findTask は、ジョブに紐づくタスクを取得します。タスクが存在しない場合はエラーを返します。
+func (e *Executor) findTask(ctx context.Context, taskID string) (*domain.Task, error) {
+	task, err := e.taskRepo.FindByID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find task %s: %w", taskID, err)
+	}
+	if task == nil {
+		return nil, fmt.Errorf("task %s not found", taskID)
+	}
+	return task, nil
+}
+
+// finish は、ジョブの今回の試行の実行結果を保存し、試行が終わったことを observer に通知します。
+// 実行結果の保存に失敗してもジョブの実行自体は失敗としないため、エラーはログに記録するのみです。
+func (e *Executor) finish(ctx context.Context, job *domain.Job, startedAt time.Time, resp *response, err error) {
+	result := domain.NewJobResult(job, startedAt, time.Now())
+	if resp != nil {
+		result.StatusCode = resp.statusCode
+		result.ResponseHeaders = resp.headers
+		result.ResponseBody = resp.body
+		result.BodyTruncated = resp.truncated
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	if saveErr := e.jobRepo.SaveResult(ctx, result); saveErr != nil {
+		jobLogger(e.logger, job).Error("failed to save job result", slog.Any("error", saveErr))
+	}
+	e.observer.JobFinished(job, result, err)
+}
+
+// execute は、タスクの Timeout を期限としてHTTPリクエストを送信し、受け取ったレスポンスを返します。
+// 期限までにレスポンスボディを読み終えられなかった場合や、HTTPClient 自体のタイムアウト
+// （http.Client の Timeout など）で送信に失敗した場合は、domain.ErrJobTimedOut をラップしたエラーを返します。
+func (e *Executor) execute(ctx context.Context, task *domain.Task) (*response, error) {
+	if task.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, task.Timeout)
+		defer cancel()
+	}
+
+	resp, err := e.send(ctx, task)
+	switch {
+	case err == nil:
+		return resp, nil
+	case task.Timeout > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return resp, fmt.Errorf("%w after %s: %v", domain.ErrJobTimedOut, task.Timeout, err)
+	case isTimeout(err):
+		return resp, fmt.Errorf("%w: %v", domain.ErrJobTimedOut, err)
+	}
+	return resp, err
+}
+
+// isTimeout は、err がタイムアウトによるエラーかを返します。
+// キャンセル（シャットダウンによる中断やジョブの置き換え）はタイムアウトとして扱いません。
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// response は、ジョブの実行結果として保存するHTTPレスポンスの内容です。
+type response struct {
+	statusCode int
+	headers    map[string]string
+	body       []byte
+	truncated  bool
+}
+
+// send は、タスクのHTTPリクエストを送信し、受け取ったレスポンスを返します。
+// レスポンスのステータスコードが2xx以外の場合は、レスポンスとともにエラーを返します。
+func (e *Executor) send(ctx context.Context, task *domain.Task) (resp *response, err error) {
+	ctx, span := e.tracer.Start(ctx, "HTTP request", trace.WithSpanKind(trace.SpanKindClient))
+	defer func() {
+		if resp != nil {
+			span.SetAttributes(attribute.Int("http.response.status_code", resp.statusCode))
+		}
+		recordSpanError(span, err)
+		span.End()
+	}()
+
+	req, err := newHTTPRequest(ctx, task.Payload, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	span.SetAttributes(
+		attribute.String("http.request.method", req.Method),
+		attribute.String("url.full", req.URL.Redacted()),
+	)
+	// 送信先のサービスが同じトレースに参加できるよう、traceparent ヘッダーを付与する
+	traceContextPropagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	httpResp, err := e.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() {
+		_ = httpResp.Body.Close()
+	}()
+
+	resp = &response{
+		statusCode: httpResp.StatusCode,
+		headers:    make(map[string]string, len(httpResp.Header)),
+	}
+	for key, values := range httpResp.Header {
+		resp.headers[key] = strings.Join(values, ", ")
+	}
+	// 保存するのは MaxResponseBodySize までとし、超えた分は読み捨てる
+	resp.body, err = io.ReadAll(io.LimitReader(httpResp.Body, domain.MaxResponseBodySize+1))
+	if len(resp.body) > domain.MaxResponseBodySize {
+		resp.body = resp.body[:domain.MaxResponseBodySize]
+		resp.truncated = true
+	}
+	if err != nil {
+		return resp, fmt.Errorf("failed to read response body: %w", err)
+	}
+	// コネクションを再利用できるよう、残りのレスポンスボディを読み捨てる
+	_, _ = io.Copy(io.Discard, httpResp.Body)
+
+	if resp.statusCode < 200 || resp.statusCode >= 300 {
+		return resp, fmt.Errorf("unexpected status code: %d", resp.statusCode)
+	}
+
+	return resp, nil
+}
+
+// newHTTPRequest は、HTTPRequestInfo から送信用の *http.Request を生成します。
+// Method が空の場合は GET として扱います。署名が有効な場合は、now の時刻で署名したヘッダーを付与します。
+func newHTTPRequest(ctx context.Context, info domain.HTTPRequestInfo, now time.Time) (*http.Request, error) {
+	method := info.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	var body io.Reader
+	if len(info.Body) > 0 {
+		body = bytes.NewReader(info.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, info.URL, body)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range info.Headers {
+		req.Header.Set(key, value)
+	}
+
+	// 署名は試行ごとに計算し直すため、リトライしたリクエストも受信側の許容時間内のタイムスタンプを持つ
+	if info.Signing.Enabled() {
+		req.Header.Set(info.Signing.TimestampHeaderName(), webhook.TimestampHeaderValue(now))
+		req.Header.Set(info.Signing.SignatureHeaderName(), webhook.SignatureHeaderValue(info.Signing.Secrets, now, info.Body))
+	}
+
+	return req, nil
+}
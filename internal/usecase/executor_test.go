@@ -1,36 +1,706 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/yourname/go-dist-scheduler/internal/domain"
 	"github.com/yourname/go-dist-scheduler/internal/infrastructure/memory"
+	"github.com/yourname/go-dist-scheduler/pkg/webhook"
 )
 
+// setupTask は、指定したHTTPサーバーへリクエストを送信するタスクを保存して返します。
+func setupTask(t *testing.T, taskRepo domain.TaskRepository, server *httptest.Server) *domain.Task {
+	t.Helper()
+
+	task := &domain.Task{
+		ID:             uuid.NewString(),
+		Name:           "Test Task",
+		CronExpression: "* * * * *",
+		Payload: domain.HTTPRequestInfo{
+			URL:     server.URL + "/webhook",
+			Method:  http.MethodPost,
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Body:    []byte(`{"key":"value"}`),
+		},
+		Status:    domain.TaskStatusActive,
+		CreatedAt: time.Now(),
+	}
+	require.NoError(t, taskRepo.Save(context.Background(), task))
+	return task
+}
+
+// enqueuePendingJob は、指定したタスクのペンディングジョブをエンキューして返します。
+func enqueuePendingJob(t *testing.T, jobRepo domain.JobRepository, taskID string) *domain.Job {
+	t.Helper()
+
+	job := &domain.Job{
+		ID:          uuid.NewString(),
+		TaskID:      taskID,
+		ScheduledAt: time.Now(),
+		Status:      domain.JobStatusPending,
+	}
+	require.NoError(t, jobRepo.Enqueue(context.Background(), job))
+	return job
+}
+
+// recordingJobRepository は、UpdateStatus で渡されたステータスを記録します。
+type recordingJobRepository struct {
+	memory.InMemoryJobRepository
+	statuses []domain.JobStatus
+}
+
+func (r *recordingJobRepository) UpdateStatus(ctx context.Context, jobID string, status domain.JobStatus) error {
+	r.statuses = append(r.statuses, status)
+	return r.InMemoryJobRepository.UpdateStatus(ctx, jobID, status)
+}
+
+func newRecordingJobRepository() *recordingJobRepository {
+	return &recordingJobRepository{
+		InMemoryJobRepository: *memory.NewInMemoryJobRepository(),
+	}
+}
+
 func TestExecutor_RunPendingJob_Success(t *testing.T) {
 	ctx := context.Background()
+
+	var (
+		gotMethod string
+		gotPath   string
+		gotHeader string
+		gotBody   []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotPath = r.URL.Path
+		gotHeader = r.Header.Get("Content-Type")
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := newRecordingJobRepository()
+	executor := NewExecutor(taskRepo, jobRepo, server.Client())
+
+	task := setupTask(t, taskRepo, server)
+	enqueuePendingJob(t, jobRepo, task.ID)
+
+	// Run pending jobs
+	err := executor.RunPendingJob(ctx)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.MethodPost, gotMethod)
+	assert.Equal(t, "/webhook", gotPath)
+	assert.Equal(t, "application/json", gotHeader)
+	assert.Equal(t, `{"key":"value"}`, string(gotBody))
+	assert.Equal(t, []domain.JobStatus{domain.JobStatusRunning, domain.JobStatusSuccess}, jobRepo.statuses)
+}
+
+func TestExecutor_RunPendingJob_SignsRequest(t *testing.T) {
+	ctx := context.Background()
+
+	// 受信側は新しい鍵だけを知っている。ローテーション中のタスクは新旧両方の鍵で署名する
+	verifier := webhook.NewVerifier([]string{"new-secret"},
+		webhook.WithSignatureHeader("X-Signature"),
+		webhook.WithTimestampHeader("X-Timestamp"),
+	)
+	var gotBody []byte
+	server := httptest.NewServer(verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+	})))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := newRecordingJobRepository()
+	executor := NewExecutor(taskRepo, jobRepo, server.Client())
+
+	task := setupTask(t, taskRepo, server)
+	task.Payload.Signing = domain.SigningPolicy{
+		Secrets:         []string{"old-secret", "new-secret"},
+		SignatureHeader: "X-Signature",
+		TimestampHeader: "X-Timestamp",
+	}
+	require.NoError(t, taskRepo.Save(ctx, task))
+	enqueuePendingJob(t, jobRepo, task.ID)
+
+	require.NoError(t, executor.RunPendingJob(ctx))
+	assert.Equal(t, `{"key":"value"}`, string(gotBody))
+	assert.Equal(t, []domain.JobStatus{domain.JobStatusRunning, domain.JobStatusSuccess}, jobRepo.statuses)
+}
+
+func TestExecutor_RunPendingJob_UnsignedByDefault(t *testing.T) {
+	ctx := context.Background()
+
+	var gotHeader http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
 	jobRepo := memory.NewInMemoryJobRepository()
-	executor := NewExecutor(jobRepo)
+	executor := NewExecutor(taskRepo, jobRepo, server.Client())
 
-	// Enqueue a pending job
-	jobID := uuid.NewString()
-	pendingJob := &domain.Job{
-		ID:          jobID,
-		TaskID:      uuid.NewString(),
-		ScheduledAt: time.Now(),
-		Status:      domain.JobStatusPending,
+	task := setupTask(t, taskRepo, server)
+	enqueuePendingJob(t, jobRepo, task.ID)
+
+	require.NoError(t, executor.RunPendingJob(ctx))
+	assert.Empty(t, gotHeader.Get(webhook.DefaultSignatureHeader))
+	assert.Empty(t, gotHeader.Get(webhook.DefaultTimestampHeader))
+}
+
+// recordingObserver は、Executor・Scheduler・Pruner から通知されたイベントを記録します。
+type recordingObserver struct {
+	started  []*domain.Job
+	finished []error
+	results  []*domain.JobResult
+	checks   []error
+	pruned   []int
+}
+
+func (o *recordingObserver) JobStarted(job *domain.Job, startedAt time.Time) {
+	o.started = append(o.started, job)
+}
+
+func (o *recordingObserver) JobFinished(job *domain.Job, result *domain.JobResult, err error) {
+	o.finished = append(o.finished, err)
+	o.results = append(o.results, result)
+}
+
+func (o *recordingObserver) CheckCompleted(duration time.Duration, err error) {
+	o.checks = append(o.checks, err)
+}
+
+func (o *recordingObserver) JobsPruned(count int) {
+	o.pruned = append(o.pruned, count)
+}
+
+func TestExecutor_RunPendingJob_Observer(t *testing.T) {
+	ctx := context.Background()
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := memory.NewInMemoryJobRepository()
+	observer := &recordingObserver{}
+	executor := NewExecutor(taskRepo, jobRepo, server.Client(), WithExecutorObserver(observer))
+
+	task := setupTask(t, taskRepo, server)
+	task.RetryPolicy = domain.RetryPolicy{MaxRetries: 1}
+	require.NoError(t, taskRepo.Save(ctx, task))
+	job := enqueuePendingJob(t, jobRepo, task.ID)
+
+	require.NoError(t, executor.RunPendingJob(ctx))
+	require.NoError(t, executor.RunPendingJob(ctx))
+
+	require.Len(t, observer.started, 2)
+	assert.Equal(t, job.ID, observer.started[0].ID)
+	require.Len(t, observer.finished, 2)
+	assert.Error(t, observer.finished[0])
+	assert.NoError(t, observer.finished[1])
+	assert.Equal(t, 1, observer.results[0].Attempt)
+	assert.Equal(t, 2, observer.results[1].Attempt)
+}
+
+// newTestLogger は、JSON形式で buf に書き込むロガーを返します。
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+// parseLogLines は、newTestLogger で書き込まれたログを1行ずつ解析して返します。
+func parseLogLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var lines []map[string]any
+	for _, raw := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		var line map[string]any
+		require.NoError(t, json.Unmarshal(raw, &line))
+		lines = append(lines, line)
 	}
-	err := jobRepo.Enqueue(ctx, pendingJob)
+	return lines
+}
+
+func TestExecutor_RunPendingJob_Logging(t *testing.T) {
+	ctx := context.Background()
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := memory.NewInMemoryJobRepository()
+	executor := NewExecutor(taskRepo, jobRepo, server.Client(), WithExecutorLogger(newTestLogger(&buf)))
+
+	task := setupTask(t, taskRepo, server)
+	task.RetryPolicy = domain.RetryPolicy{MaxRetries: 1}
+	require.NoError(t, taskRepo.Save(ctx, task))
+	job := enqueuePendingJob(t, jobRepo, task.ID)
+
+	require.NoError(t, executor.RunPendingJob(ctx))
+	require.NoError(t, executor.RunPendingJob(ctx))
+
+	lines := parseLogLines(t, &buf)
+	require.NotEmpty(t, lines)
+	var messages []string
+	for _, line := range lines {
+		// すべての行にジョブとタスクを識別する属性が付与される
+		assert.Equal(t, task.ID, line["task_id"], line["msg"])
+		assert.Equal(t, job.ID, line["job_id"], line["msg"])
+		assert.Equal(t, job.ScheduledAt.Format(time.RFC3339Nano), line["scheduled_at"], line["msg"])
+		assert.Contains(t, line, "attempt", line["msg"])
+		messages = append(messages, line["msg"].(string))
+	}
+	assert.Equal(t, []string{"executing job", "job failed", "job will be retried", "executing job", "job succeeded"}, messages)
+	assert.Equal(t, float64(1), lines[0]["attempt"])
+	assert.Equal(t, float64(2), lines[len(lines)-1]["attempt"])
+	assert.Equal(t, "unexpected status code: 500", lines[1]["error"])
+}
+
+func TestExecutor_RunPendingJob_Failed(t *testing.T) {
+	testCases := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{
+			name: "server error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			},
+		},
+		{
+			name: "client error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := httptest.NewServer(tc.handler)
+			defer server.Close()
+
+			taskRepo := memory.NewInMemoryTaskRepository()
+			jobRepo := newRecordingJobRepository()
+			executor := NewExecutor(taskRepo, jobRepo, server.Client())
+
+			task := setupTask(t, taskRepo, server)
+			enqueuePendingJob(t, jobRepo, task.ID)
+
+			err := executor.RunPendingJob(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, []domain.JobStatus{domain.JobStatusRunning, domain.JobStatusFailed}, jobRepo.statuses)
+		})
+	}
+}
+
+func TestExecutor_RunPendingJob_RequestError(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := newRecordingJobRepository()
+	executor := NewExecutor(taskRepo, jobRepo, server.Client())
+
+	task := setupTask(t, taskRepo, server)
+	enqueuePendingJob(t, jobRepo, task.ID)
+
+	// Close the server so that the request cannot be delivered
+	server.Close()
+
+	err := executor.RunPendingJob(ctx)
 	assert.NoError(t, err)
+	assert.Equal(t, []domain.JobStatus{domain.JobStatusRunning, domain.JobStatusFailed}, jobRepo.statuses)
+}
 
-	// Run pending jobs
+func TestExecutor_RunPendingJob_TaskNotFound(t *testing.T) {
+	ctx := context.Background()
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := newRecordingJobRepository()
+	executor := NewExecutor(taskRepo, jobRepo, http.DefaultClient)
+
+	enqueuePendingJob(t, jobRepo, uuid.NewString())
+
+	err := executor.RunPendingJob(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.JobStatus{domain.JobStatusRunning, domain.JobStatusFailed}, jobRepo.statuses)
+}
+
+func TestExecutor_RunPendingJob_RetryUntilMaxRetries(t *testing.T) {
+	ctx := context.Background()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := newRecordingJobRepository()
+	executor := NewExecutor(taskRepo, jobRepo, server.Client())
+
+	task := setupTask(t, taskRepo, server)
+	task.RetryPolicy = domain.RetryPolicy{MaxRetries: 2, Backoff: domain.BackoffFixed}
+	require.NoError(t, taskRepo.Save(ctx, task))
+	enqueuePendingJob(t, jobRepo, task.ID)
+
+	// The first attempt and two retries are executed, then the job is terminally Failed
+	for i := 0; i < 4; i++ {
+		err := executor.RunPendingJob(ctx)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, []domain.JobStatus{
+		domain.JobStatusRunning,
+		domain.JobStatusRunning,
+		domain.JobStatusRunning,
+		domain.JobStatusFailed,
+	}, jobRepo.statuses)
+}
+
+func TestExecutor_RunPendingJob_Timeout(t *testing.T) {
+	ctx := context.Background()
+
+	release := make(chan struct{})
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = io.Copy(io.Discard, r.Body)
+		// The endpoint hangs until the client gives up or the test finishes
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := newRecordingJobRepository()
+	executor := NewExecutor(taskRepo, jobRepo, server.Client())
+
+	task := setupTask(t, taskRepo, server)
+	task.Timeout = 50 * time.Millisecond
+	task.RetryPolicy = domain.RetryPolicy{MaxRetries: 1, Backoff: domain.BackoffFixed}
+	require.NoError(t, taskRepo.Save(ctx, task))
+	job := enqueuePendingJob(t, jobRepo, task.ID)
+
+	// The timed out attempt is retried, then the job is terminally TimedOut
+	for i := 0; i < 2; i++ {
+		startedAt := time.Now()
+		err := executor.RunPendingJob(ctx)
+		assert.NoError(t, err)
+		assert.Less(t, time.Since(startedAt), time.Second, "the attempt should be aborted at the timeout")
+	}
+
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Equal(t, []domain.JobStatus{
+		domain.JobStatusRunning,
+		domain.JobStatusRunning,
+		domain.JobStatusTimedOut,
+	}, jobRepo.statuses)
+
+	timedOut, err := jobRepo.FindByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusTimedOut, timedOut.Status)
+	assert.True(t, timedOut.IsFinished())
+
+	results, err := jobRepo.FindResultsByJobID(ctx, job.ID)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	for _, result := range results {
+		assert.Contains(t, result.Error, domain.ErrJobTimedOut.Error())
+	}
+}
+
+func TestExecutor_RunPendingJob_ClientTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	// The task has no timeout of its own, but the HTTP client gives up
+	client := server.Client()
+	client.Timeout = 50 * time.Millisecond
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := newRecordingJobRepository()
+	executor := NewExecutor(taskRepo, jobRepo, client)
+
+	task := setupTask(t, taskRepo, server)
+	job := enqueuePendingJob(t, jobRepo, task.ID)
+
+	require.NoError(t, executor.RunPendingJob(ctx))
+	assert.Equal(t, []domain.JobStatus{domain.JobStatusRunning, domain.JobStatusTimedOut}, jobRepo.statuses,
+		"a client timeout ends with the same status as the task timeout")
+
+	results, err := jobRepo.FindResultsByJobID(ctx, job.ID)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Contains(t, results[0].Error, domain.ErrJobTimedOut.Error())
+}
+
+func TestExecutor_RunPendingJob_FailureWithinTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := newRecordingJobRepository()
+	executor := NewExecutor(taskRepo, jobRepo, server.Client())
+
+	task := setupTask(t, taskRepo, server)
+	task.Timeout = time.Minute
+	require.NoError(t, taskRepo.Save(ctx, task))
+	enqueuePendingJob(t, jobRepo, task.ID)
+
+	err := executor.RunPendingJob(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.JobStatus{domain.JobStatusRunning, domain.JobStatusFailed}, jobRepo.statuses,
+		"an ordinary failure is not reported as a timeout")
+}
+
+func TestExecutor_RunPendingJob_RetryWithBackoff(t *testing.T) {
+	ctx := context.Background()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := newRecordingJobRepository()
+	executor := NewExecutor(taskRepo, jobRepo, server.Client())
+
+	task := setupTask(t, taskRepo, server)
+	task.RetryPolicy = domain.RetryPolicy{MaxRetries: 3, Backoff: domain.BackoffExponential, InitialInterval: time.Hour}
+	require.NoError(t, taskRepo.Save(ctx, task))
+	enqueuePendingJob(t, jobRepo, task.ID)
+
+	err := executor.RunPendingJob(ctx)
+	assert.NoError(t, err)
+
+	// The retry is delayed by the backoff, so nothing is executed yet
 	err = executor.RunPendingJob(ctx)
 	assert.NoError(t, err)
+
+	assert.Equal(t, 1, requests)
+	assert.Equal(t, []domain.JobStatus{domain.JobStatusRunning}, jobRepo.statuses)
+}
+
+func TestExecutor_RunPendingJob_SavesResults(t *testing.T) {
+	ctx := context.Background()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("X-Request-Count", strconv.Itoa(requests))
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte("try again later"))
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := newRecordingJobRepository()
+	executor := NewExecutor(taskRepo, jobRepo, server.Client())
+
+	task := setupTask(t, taskRepo, server)
+	task.RetryPolicy = domain.RetryPolicy{MaxRetries: 1, Backoff: domain.BackoffFixed}
+	require.NoError(t, taskRepo.Save(ctx, task))
+	job := enqueuePendingJob(t, jobRepo, task.ID)
+
+	// The first attempt fails and the retry succeeds
+	for i := 0; i < 2; i++ {
+		err := executor.RunPendingJob(ctx)
+		assert.NoError(t, err)
+	}
+
+	results, err := jobRepo.FindResultsByJobID(ctx, job.ID)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.Equal(t, 1, results[0].Attempt)
+	assert.Equal(t, task.ID, results[0].TaskID)
+	assert.Equal(t, http.StatusServiceUnavailable, results[0].StatusCode)
+	assert.Equal(t, "1", results[0].ResponseHeaders["X-Request-Count"])
+	assert.Equal(t, []byte("try again later"), results[0].ResponseBody)
+	assert.Equal(t, "unexpected status code: 503", results[0].Error)
+	assert.False(t, results[0].FinishedAt.Before(results[0].StartedAt))
+
+	assert.Equal(t, 2, results[1].Attempt)
+	assert.Equal(t, http.StatusOK, results[1].StatusCode)
+	assert.Equal(t, []byte("ok"), results[1].ResponseBody)
+	assert.True(t, results[1].IsSuccess())
+
+	recent, err := jobRepo.FindRecentResultsByTaskID(ctx, task.ID, 10)
+	require.NoError(t, err)
+	assert.Len(t, recent, 2)
+}
+
+func TestExecutor_RunPendingJob_TruncatesResponseBody(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(bytes.Repeat([]byte("a"), domain.MaxResponseBodySize+100))
+	}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := newRecordingJobRepository()
+	executor := NewExecutor(taskRepo, jobRepo, server.Client())
+
+	task := setupTask(t, taskRepo, server)
+	job := enqueuePendingJob(t, jobRepo, task.ID)
+
+	err := executor.RunPendingJob(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.JobStatus{domain.JobStatusRunning, domain.JobStatusSuccess}, jobRepo.statuses)
+
+	results, err := jobRepo.FindResultsByJobID(ctx, job.ID)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Len(t, results[0].ResponseBody, domain.MaxResponseBodySize)
+	assert.True(t, results[0].BodyTruncated)
+}
+
+func TestExecutor_RunPendingJob_SavesResultWithoutResponse(t *testing.T) {
+	ctx := context.Background()
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := newRecordingJobRepository()
+	executor := NewExecutor(taskRepo, jobRepo, http.DefaultClient)
+
+	job := enqueuePendingJob(t, jobRepo, uuid.NewString())
+
+	err := executor.RunPendingJob(ctx)
+	assert.NoError(t, err)
+
+	results, err := jobRepo.FindResultsByJobID(ctx, job.ID)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, 0, results[0].StatusCode, "no response was received")
+	assert.Contains(t, results[0].Error, "not found")
+}
+
+func TestExecutor_RunPendingJob_HeartbeatExtendsLease(t *testing.T) {
+	ctx := context.Background()
+
+	jobRepo := memory.NewInMemoryJobRepository(memory.WithVisibilityTimeout(50 * time.Millisecond))
+	var expiredDuringRequest []*domain.Job
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The request outlives the visibility timeout several times over
+		time.Sleep(200 * time.Millisecond)
+		expired, err := jobRepo.FindExpiredLeases(r.Context(), time.Now(), 10)
+		assert.NoError(t, err)
+		expiredDuringRequest = expired
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	executor := NewExecutor(taskRepo, jobRepo, server.Client(), WithHeartbeatInterval(10*time.Millisecond))
+
+	task := setupTask(t, taskRepo, server)
+	enqueuePendingJob(t, jobRepo, task.ID)
+
+	err := executor.RunPendingJob(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, expiredDuringRequest, "the lease of a running job should be kept alive by heartbeats")
+}
+
+func TestExecutor_RunPendingJob_Cancelled(t *testing.T) {
+	ctx := context.Background()
+
+	jobRepo := memory.NewInMemoryJobRepository()
+	var job *domain.Job
+	requestAborted := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Another process replaces the job while the request is in flight
+		_, _ = io.Copy(io.Discard, r.Body)
+		assert.NoError(t, jobRepo.Cancel(context.Background(), job.ID))
+		select {
+		case <-r.Context().Done():
+			close(requestAborted)
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	executor := NewExecutor(taskRepo, jobRepo, server.Client(), WithHeartbeatInterval(10*time.Millisecond))
+
+	task := setupTask(t, taskRepo, server)
+	task.RetryPolicy = domain.RetryPolicy{MaxRetries: 3}
+	require.NoError(t, taskRepo.Save(ctx, task))
+	job = enqueuePendingJob(t, jobRepo, task.ID)
+
+	err := executor.RunPendingJob(ctx)
+	assert.NoError(t, err)
+
+	select {
+	case <-requestAborted:
+	case <-time.After(time.Second):
+		t.Fatal("the request of a cancelled job should be aborted")
+	}
+
+	// The job is neither retried nor marked as failed
+	cancelled, err := jobRepo.FindByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusCancelled, cancelled.Status)
+	assert.Equal(t, 0, cancelled.RetryCount)
+
+	results, err := jobRepo.FindResultsByJobID(ctx, job.ID)
+	require.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, domain.ErrJobCancelled.Error(), results[0].Error)
+	}
 }
 
 type dequeueErrorJobRepository struct {
@@ -50,7 +720,7 @@ func newDequeueErrorJobRepository() *dequeueErrorJobRepository {
 func TestExecutor_RunPendingJob_DequeueError(t *testing.T) {
 	ctx := context.Background()
 	jobRepo := newDequeueErrorJobRepository()
-	executor := NewExecutor(jobRepo)
+	executor := NewExecutor(memory.NewInMemoryTaskRepository(), jobRepo, http.DefaultClient)
 
 	err := executor.RunPendingJob(ctx)
 	assert.Error(t, err)
@@ -59,7 +729,7 @@ func TestExecutor_RunPendingJob_DequeueError(t *testing.T) {
 func TestExecutor_RunPendingJob_NoPendingJobs(t *testing.T) {
 	ctx := context.Background()
 	jobRepo := memory.NewInMemoryJobRepository()
-	executor := NewExecutor(jobRepo)
+	executor := NewExecutor(memory.NewInMemoryTaskRepository(), jobRepo, http.DefaultClient)
 
 	err := executor.RunPendingJob(ctx)
 	assert.NoError(t, err)
@@ -88,7 +758,7 @@ func newUpdateStatusErrorJobRepository() *updateStatusErrorJobRepository {
 func TestExecutor_RunPendingJob_UpdateStatusToRunningError(t *testing.T) {
 	ctx := context.Background()
 	jobRepo := newUpdateStatusErrorJobRepository()
-	executor := NewExecutor(jobRepo)
+	executor := NewExecutor(memory.NewInMemoryTaskRepository(), jobRepo, http.DefaultClient)
 
 	// Enqueue a pending job
 	jobID := uuid.NewString()
@@ -128,21 +798,20 @@ func newUpdateStatusToSuccessErrorJobRepository() *updateStatusToSuccessErrorJob
 
 func TestExecutor_RunPendingJob_UpdateStatusToSuccessError(t *testing.T) {
 	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
 	jobRepo := newUpdateStatusToSuccessErrorJobRepository()
-	executor := NewExecutor(jobRepo)
+	executor := NewExecutor(taskRepo, jobRepo, server.Client())
 
-	// Enqueue a pending job
-	jobID := uuid.NewString()
-	pendingJob := &domain.Job{
-		ID:          jobID,
-		TaskID:      uuid.NewString(),
-		ScheduledAt: time.Now(),
-		Status:      domain.JobStatusPending,
-	}
-	err := jobRepo.Enqueue(ctx, pendingJob)
-	assert.NoError(t, err)
+	// Enqueue a pending job for a task whose request succeeds
+	task := setupTask(t, taskRepo, server)
+	enqueuePendingJob(t, jobRepo, task.ID)
 
-	err = executor.RunPendingJob(ctx)
+	err := executor.RunPendingJob(ctx)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to update status to Success")
 }
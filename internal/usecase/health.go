@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultHealthCheckTimeout は、1つの HealthCheck の応答を待つ時間のデフォルトです。
+	DefaultHealthCheckTimeout = 2 * time.Second
+	// loopCheckName は、スケジューリングループの確認結果の名前です。
+	loopCheckName = "scheduler_loop"
+)
+
+// HealthCheck は、依存するコンポーネント（PostgreSQL や Redis など）に到達できるかを確認する関数です。
+// 到達できない場合はエラーを返します。
+type HealthCheck func(ctx context.Context) error
+
+// LoopMonitor は、スケジューリングループが最後に1回のティックを完了した時刻を記録します。
+// ループが停止・停滞していないかを Health で判定するために使用します。
+type LoopMonitor struct {
+	lastTick atomic.Int64
+}
+
+// NewLoopMonitor は新しいLoopMonitorインスタンスを生成します。
+func NewLoopMonitor() *LoopMonitor {
+	return &LoopMonitor{}
+}
+
+// Tick は、スケジューリングループが now の時点で1回のティックを完了したことを記録します。
+func (m *LoopMonitor) Tick(now time.Time) {
+	m.lastTick.Store(now.UnixNano())
+}
+
+// LastTick は、最後にティックを完了した時刻を返します。まだ一度も完了していない場合はゼロ値を返します。
+func (m *LoopMonitor) LastTick() time.Time {
+	nanos := m.lastTick.Load()
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// Health は、このインスタンスがリクエストやジョブを処理できる状態にあるか（レディネス）を判定するユースケースです。
+type Health struct {
+	checks       []namedHealthCheck
+	checkTimeout time.Duration
+	loop         *LoopMonitor
+	staleAfter   time.Duration
+	elector      *LeaderElector
+	workerPool   *WorkerPool
+}
+
+type namedHealthCheck struct {
+	name  string
+	check HealthCheck
+}
+
+// HealthOption は、Health の設定を変更するオプションです。
+type HealthOption func(*Health)
+
+// WithHealthCheck は、レディネスの判定に含める依存コンポーネントの確認を追加します。
+func WithHealthCheck(name string, check HealthCheck) HealthOption {
+	return func(h *Health) {
+		h.checks = append(h.checks, namedHealthCheck{name: name, check: check})
+	}
+}
+
+// WithHealthCheckTimeout は、1つの HealthCheck の応答を待つ時間を設定します。
+func WithHealthCheckTimeout(d time.Duration) HealthOption {
+	return func(h *Health) {
+		h.checkTimeout = d
+	}
+}
+
+// WithLoopMonitor は、スケジューリングループが staleAfter より長くティックを完了していない場合に
+// レディネスを失敗とするよう設定します。
+func WithLoopMonitor(m *LoopMonitor, staleAfter time.Duration) HealthOption {
+	return func(h *Health) {
+		h.loop = m
+		h.staleAfter = staleAfter
+	}
+}
+
+// WithLeaderElector は、このインスタンスがリーダーであるかをレディネスの結果に含めます。
+func WithLeaderElector(e *LeaderElector) HealthOption {
+	return func(h *Health) {
+		h.elector = e
+	}
+}
+
+// WithWorkerPool は、ワーカーの稼働状況をレディネスの結果に含めます。
+func WithWorkerPool(p *WorkerPool) HealthOption {
+	return func(h *Health) {
+		h.workerPool = p
+	}
+}
+
+// NewHealth は新しいHealthインスタンスを生成します。
+// HealthCheck の応答を待つ時間のデフォルトは DefaultHealthCheckTimeout です。
+func NewHealth(opts ...HealthOption) *Health {
+	h := &Health{
+		checkTimeout: DefaultHealthCheckTimeout,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// CheckResult は、1つのコンポーネントの確認結果です。Err が nil であれば正常です。
+type CheckResult struct {
+	Name string
+	Err  error
+}
+
+// ReadinessReport は、レディネスの判定結果です。
+// Leader・BusyWorkers・Workers は参考情報で、Ready の判定には影響しません。
+type ReadinessReport struct {
+	// Ready は、すべての確認が正常であったかです。
+	Ready bool
+	// Checks は、依存コンポーネントとスケジューリングループの確認結果です。
+	Checks []CheckResult
+	// LastTickAt は、スケジューリングループが最後にティックを完了した時刻です。
+	LastTickAt time.Time
+	// Leader は、このインスタンスがリーダーであるかです。
+	Leader bool
+	// BusyWorkers は、ジョブを実行中のワーカーの数です。
+	BusyWorkers int
+	// Workers は、ワーカーの総数です。
+	Workers int
+}
+
+// Readiness は、登録されたすべての依存コンポーネントを並行して確認し、スケジューリングループが
+// 停滞していないかとあわせてレディネスを判定します。
+func (h *Health) Readiness(ctx context.Context, now time.Time) *ReadinessReport {
+	report := &ReadinessReport{
+		Ready:  true,
+		Checks: make([]CheckResult, len(h.checks), len(h.checks)+1),
+	}
+
+	var wg sync.WaitGroup
+	for i, c := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Checks[i] = CheckResult{Name: c.name, Err: h.runCheck(ctx, c.check)}
+		}()
+	}
+	wg.Wait()
+
+	if h.loop != nil {
+		report.LastTickAt = h.loop.LastTick()
+		report.Checks = append(report.Checks, CheckResult{Name: loopCheckName, Err: h.checkLoop(report.LastTickAt, now)})
+	}
+	for _, c := range report.Checks {
+		if c.Err != nil {
+			report.Ready = false
+		}
+	}
+
+	if h.elector != nil {
+		report.Leader = h.elector.IsLeader()
+	}
+	if h.workerPool != nil {
+		report.BusyWorkers = h.workerPool.Busy()
+		report.Workers = h.workerPool.Size()
+	}
+	return report
+}
+
+// runCheck は、checkTimeout を期限として HealthCheck を実行します。
+func (h *Health) runCheck(ctx context.Context, check HealthCheck) error {
+	ctx, cancel := context.WithTimeout(ctx, h.checkTimeout)
+	defer cancel()
+	return check(ctx)
+}
+
+// checkLoop は、スケジューリングループが staleAfter 以内にティックを完了しているかを確認します。
+func (h *Health) checkLoop(lastTick, now time.Time) error {
+	if lastTick.IsZero() {
+		return errors.New("no tick has completed yet")
+	}
+	if elapsed := now.Sub(lastTick); elapsed > h.staleAfter {
+		return fmt.Errorf("no tick has completed for %s (threshold %s)", elapsed.Round(time.Millisecond), h.staleAfter)
+	}
+	return nil
+}
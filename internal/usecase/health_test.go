@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/memory"
+)
+
+// findCheck は、名前が name の確認結果を返します。
+func findCheck(t *testing.T, report *ReadinessReport, name string) CheckResult {
+	t.Helper()
+
+	for _, c := range report.Checks {
+		if c.Name == name {
+			return c
+		}
+	}
+	require.Failf(t, "check not found", "no check named %q", name)
+	return CheckResult{}
+}
+
+func TestHealth_Readiness_DependencyChecks(t *testing.T) {
+	ctx := context.Background()
+	ok := func(ctx context.Context) error { return nil }
+	unreachable := func(ctx context.Context) error { return errors.New("connection refused") }
+
+	report := NewHealth(WithHealthCheck("postgres", ok), WithHealthCheck("redis", ok)).Readiness(ctx, time.Now())
+	assert.True(t, report.Ready)
+	assert.NoError(t, findCheck(t, report, "postgres").Err)
+	assert.NoError(t, findCheck(t, report, "redis").Err)
+
+	report = NewHealth(WithHealthCheck("postgres", ok), WithHealthCheck("redis", unreachable)).Readiness(ctx, time.Now())
+	assert.False(t, report.Ready)
+	assert.NoError(t, findCheck(t, report, "postgres").Err)
+	assert.EqualError(t, findCheck(t, report, "redis").Err, "connection refused")
+}
+
+func TestHealth_Readiness_CheckTimeout(t *testing.T) {
+	hanging := func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	health := NewHealth(WithHealthCheck("postgres", hanging), WithHealthCheckTimeout(10*time.Millisecond))
+
+	report := health.Readiness(context.Background(), time.Now())
+	assert.False(t, report.Ready)
+	assert.ErrorIs(t, findCheck(t, report, "postgres").Err, context.DeadlineExceeded)
+}
+
+func TestHealth_Readiness_SchedulingLoop(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	monitor := NewLoopMonitor()
+	health := NewHealth(WithLoopMonitor(monitor, 10*time.Second))
+
+	// Not ready until the loop completes its first tick
+	report := health.Readiness(ctx, now)
+	assert.False(t, report.Ready)
+	assert.Error(t, findCheck(t, report, "scheduler_loop").Err)
+	assert.True(t, report.LastTickAt.IsZero())
+
+	monitor.Tick(now.Add(-5 * time.Second))
+	report = health.Readiness(ctx, now)
+	assert.True(t, report.Ready)
+	assert.True(t, now.Add(-5*time.Second).Equal(report.LastTickAt))
+
+	// The loop is stuck
+	report = health.Readiness(ctx, now.Add(10*time.Second))
+	assert.False(t, report.Ready)
+	assert.ErrorContains(t, findCheck(t, report, "scheduler_loop").Err, "threshold 10s")
+}
+
+func TestHealth_Readiness_LeaderAndWorkers(t *testing.T) {
+	ctx := context.Background()
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := memory.NewInMemoryJobRepository()
+	task := setupTask(t, taskRepo, server)
+	enqueuePendingJob(t, jobRepo, task.ID)
+
+	pool := NewWorkerPool(NewExecutor(taskRepo, jobRepo, server.Client()), 3, 5*time.Millisecond)
+	elector := NewLeaderElector(memory.NewInMemoryLeaderLock(memory.NewLeaderLockStore(), "node", time.Minute), time.Second)
+	health := NewHealth(WithLeaderElector(elector), WithWorkerPool(pool))
+
+	report := health.Readiness(ctx, time.Now())
+	assert.True(t, report.Ready, "leadership and busy workers do not affect readiness")
+	assert.False(t, report.Leader)
+	assert.Equal(t, 0, report.BusyWorkers)
+	assert.Equal(t, 3, report.Workers)
+
+	elector.Elect(ctx)
+	stop := runWorkerPool(pool)
+	defer stop()
+	assert.Eventually(t, func() bool {
+		return health.Readiness(ctx, time.Now()).BusyWorkers == 1
+	}, time.Second, 5*time.Millisecond)
+	assert.True(t, health.Readiness(ctx, time.Now()).Leader)
+
+	close(release)
+	assert.Eventually(t, func() bool {
+		return health.Readiness(ctx, time.Now()).BusyWorkers == 0
+	}, time.Second, 5*time.Millisecond)
+}
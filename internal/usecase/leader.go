@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+)
+
+// LeaderElector は、LeaderLock を定期的に獲得・更新し、このインスタンスがリーダーであるかを管理します。
+// 複数のスケジューラーインスタンスを起動した場合でも、リーダーのみがジョブをエンキューするために使用します。
+type LeaderElector struct {
+	lock     domain.LeaderLock
+	interval time.Duration
+	isLeader atomic.Bool
+	logger   *slog.Logger
+}
+
+// LeaderElectorOption は、LeaderElector の設定を変更するオプションです。
+type LeaderElectorOption func(*LeaderElector)
+
+// WithLeaderElectorLogger は、LeaderElector がログを出力するロガーを設定します。
+func WithLeaderElectorLogger(logger *slog.Logger) LeaderElectorOption {
+	return func(e *LeaderElector) {
+		e.logger = logger
+	}
+}
+
+// NewLeaderElector は新しいLeaderElectorインスタンスを生成します。
+// interval は、リーダーシップの獲得・更新を試みる間隔です。ログはデフォルトで slog.Default() に出力します。
+func NewLeaderElector(lock domain.LeaderLock, interval time.Duration, opts ...LeaderElectorOption) *LeaderElector {
+	e := &LeaderElector{
+		lock:     lock,
+		interval: interval,
+		logger:   slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// IsLeader は、このインスタンスが現在リーダーであるかを返します。
+func (e *LeaderElector) IsLeader() bool {
+	return e.isLeader.Load()
+}
+
+// Run は、ctx がキャンセルされるまで interval ごとにリーダーシップの獲得・更新を試みます。
+// 終了時には、保持しているリーダーシップを手放します。
+func (e *LeaderElector) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	e.Elect(ctx)
+	for {
+		select {
+		case <-ticker.C:
+			e.Elect(ctx)
+		case <-ctx.Done():
+			e.resign()
+			return
+		}
+	}
+}
+
+// Elect は、リーダーシップの獲得・更新を1回試み、リーダーシップが変化した場合はログに出力します。
+// ロックの操作に失敗した場合は、安全のためリーダーではないものとして扱います。
+func (e *LeaderElector) Elect(ctx context.Context) {
+	acquired, err := e.lock.TryAcquire(ctx)
+	if err != nil {
+		e.logger.Error("failed to acquire leader lock", slog.Any("error", err))
+		acquired = false
+	}
+	e.setLeader(acquired)
+}
+
+// resign は、リーダーシップを手放します。
+// 呼び出し元の ctx はキャンセル済みのため、解放には新しいコンテキストを使用します。
+func (e *LeaderElector) resign() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := e.lock.Release(ctx); err != nil {
+		e.logger.Error("failed to release leader lock", slog.Any("error", err))
+	}
+	e.setLeader(false)
+}
+
+// setLeader は、リーダーであるかを更新し、変化した場合はログに出力します。
+func (e *LeaderElector) setLeader(isLeader bool) {
+	if e.isLeader.Swap(isLeader) == isLeader {
+		return
+	}
+	if isLeader {
+		e.logger.Info("acquired leadership; this instance is now the leader")
+	} else {
+		e.logger.Info("lost leadership; this instance is now a follower")
+	}
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/memory"
+)
+
+func TestLeaderElector_OnlyOneLeader(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewLeaderLockStore()
+	first := NewLeaderElector(memory.NewInMemoryLeaderLock(store, "first", time.Minute), time.Second)
+	second := NewLeaderElector(memory.NewInMemoryLeaderLock(store, "second", time.Minute), time.Second)
+
+	first.Elect(ctx)
+	second.Elect(ctx)
+	assert.True(t, first.IsLeader())
+	assert.False(t, second.IsLeader())
+
+	// Renewing keeps the current leader
+	first.Elect(ctx)
+	second.Elect(ctx)
+	assert.True(t, first.IsLeader())
+	assert.False(t, second.IsLeader())
+}
+
+func TestLeaderElector_FailoverWhenLeaderDies(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewLeaderLockStore()
+	ttl := 20 * time.Millisecond
+	first := NewLeaderElector(memory.NewInMemoryLeaderLock(store, "first", ttl), time.Second)
+	second := NewLeaderElector(memory.NewInMemoryLeaderLock(store, "second", ttl), time.Second)
+
+	first.Elect(ctx)
+	assert.True(t, first.IsLeader())
+
+	// The leader stops renewing its lease, as a crashed process would
+	time.Sleep(2 * ttl)
+
+	second.Elect(ctx)
+	assert.True(t, second.IsLeader())
+
+	// The old leader notices that it lost leadership on its next attempt
+	first.Elect(ctx)
+	assert.False(t, first.IsLeader())
+}
+
+func TestLeaderElector_RunReleasesLeadershipOnStop(t *testing.T) {
+	store := memory.NewLeaderLockStore()
+	first := NewLeaderElector(memory.NewInMemoryLeaderLock(store, "first", time.Minute), 5*time.Millisecond)
+	second := NewLeaderElector(memory.NewInMemoryLeaderLock(store, "second", time.Minute), time.Second)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		first.Run(ctx)
+		close(done)
+	}()
+
+	assert.Eventually(t, first.IsLeader, time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-done
+	assert.False(t, first.IsLeader())
+
+	// Leadership is released immediately instead of waiting for the lease to expire
+	second.Elect(context.Background())
+	assert.True(t, second.IsLeader())
+}
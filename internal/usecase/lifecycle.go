@@ -0,0 +1,196 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// DefaultGracePeriod は、シャットダウン時に実行中のジョブの完了を待つ時間のデフォルトです。
+const DefaultGracePeriod = 30 * time.Second
+
+// Lifecycle は、スケジューリングループ・ワーカープール・リーダー選出を起動し、
+// シャットダウン時には実行中のジョブを失わないよう順序立てて停止する責務を担当します。
+type Lifecycle struct {
+	scheduler  *Scheduler
+	reaper     *Reaper
+	elector    *LeaderElector
+	workerPool *WorkerPool
+	// interval は、スケジューリングループのティックの間隔です。
+	interval time.Duration
+	// gracePeriod は、シャットダウン時に実行中のジョブの完了を待つ時間です。
+	gracePeriod time.Duration
+	loopMonitor *LoopMonitor
+	// pruner は、終了済みジョブの履歴を pruneInterval ごとに削除する Pruner です。nil の場合は削除しません。
+	pruner        *Pruner
+	pruneInterval time.Duration
+	logger        *slog.Logger
+}
+
+// LifecycleOption は、Lifecycle の設定を変更するオプションです。
+type LifecycleOption func(*Lifecycle)
+
+// WithGracePeriod は、シャットダウン時に実行中のジョブの完了を待つ時間を設定します。
+func WithGracePeriod(d time.Duration) LifecycleOption {
+	return func(l *Lifecycle) {
+		l.gracePeriod = d
+	}
+}
+
+// WithLifecycleLoopMonitor は、スケジューリングループがティックを完了するたびに記録する LoopMonitor を設定します。
+func WithLifecycleLoopMonitor(m *LoopMonitor) LifecycleOption {
+	return func(l *Lifecycle) {
+		l.loopMonitor = m
+	}
+}
+
+// WithPruner は、リーダーが interval ごとに終了済みジョブの履歴を削除する Pruner を設定します。
+func WithPruner(pruner *Pruner, interval time.Duration) LifecycleOption {
+	return func(l *Lifecycle) {
+		l.pruner = pruner
+		l.pruneInterval = interval
+	}
+}
+
+// WithLifecycleLogger は、Lifecycle がログを出力するロガーを設定します。
+func WithLifecycleLogger(logger *slog.Logger) LifecycleOption {
+	return func(l *Lifecycle) {
+		l.logger = logger
+	}
+}
+
+// NewLifecycle は新しいLifecycleインスタンスを生成します。
+// interval は、リーダーがタスクをチェックしてジョブをエンキューし、期限切れのリースを回収する間隔です。
+// 猶予期間のデフォルトは DefaultGracePeriod で、ログはデフォルトで slog.Default() に出力します。
+func NewLifecycle(scheduler *Scheduler, reaper *Reaper, elector *LeaderElector, workerPool *WorkerPool, interval time.Duration, opts ...LifecycleOption) *Lifecycle {
+	l := &Lifecycle{
+		scheduler:   scheduler,
+		reaper:      reaper,
+		elector:     elector,
+		workerPool:  workerPool,
+		interval:    interval,
+		gracePeriod: DefaultGracePeriod,
+		logger:      slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// Run は、ctx がキャンセルされるまで各コンポーネントを実行し、キャンセルされると次の順序でシャットダウンします。
+//
+//  1. スケジューリングのティッカーと履歴の削除を停止する
+//  2. リーダーシップを手放し、他のインスタンスにスケジューリングを引き継ぐ
+//  3. 新しいジョブの取り出しを停止する
+//  4. 実行中のジョブの完了を猶予期間まで待つ
+//  5. 猶予期間内に終わらなかったジョブを中断し、リポジトリを通じて Pending に戻す
+//
+// Run は、実行中のすべてのジョブが完了するか Pending に戻されてから戻ります。
+func (l *Lifecycle) Run(ctx context.Context) {
+	// 各コンポーネントは順序立てて停止させるため、ctx のキャンセルを引き継がないコンテキストで実行する
+	electorCtx, stopElector := context.WithCancel(context.WithoutCancel(ctx))
+	defer stopElector()
+	electorDone := goDone(func() { l.elector.Run(electorCtx) })
+
+	poolCtx, stopPool := context.WithCancel(context.WithoutCancel(ctx))
+	defer stopPool()
+	poolDone := goDone(func() { l.workerPool.Run(poolCtx) })
+
+	pruneDone := goDone(func() { l.pruneLoop(ctx) })
+
+	l.logger.Info("scheduler loop started", slog.Duration("interval", l.interval))
+	l.loop(ctx)
+	<-pruneDone
+	l.logger.Info("scheduler loop stopped; draining in-flight jobs", slog.Duration("grace_period", l.gracePeriod))
+
+	stopElector()
+	stopPool()
+	l.drain(poolDone)
+	<-electorDone
+	l.logger.Info("shutdown complete")
+}
+
+// loop は、ctx がキャンセルされるまで interval ごとにティックを実行します。
+func (l *Lifecycle) loop(ctx context.Context) {
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			l.tick(ctx, time.Now())
+		}
+	}
+}
+
+// tick は、スケジューリングループの1回分の処理です。
+// リーダーのみがタスクをチェックしてジョブをエンキューし、リースの期限が切れた実行中ジョブを回収します。
+func (l *Lifecycle) tick(ctx context.Context, now time.Time) {
+	if l.elector.IsLeader() {
+		// シャットダウンで中断されたチェックはエラーとして記録しない
+		if err := l.scheduler.CheckAndEnqueue(ctx, now); err != nil && ctx.Err() == nil {
+			l.logger.Error("error in CheckAndEnqueue", slog.Any("error", err))
+		}
+		if _, err := l.reaper.ReapExpired(ctx, now); err != nil {
+			l.logger.Error("error in ReapExpired", slog.Any("error", err))
+		}
+	}
+	if l.loopMonitor != nil {
+		l.loopMonitor.Tick(time.Now())
+	}
+}
+
+// pruneLoop は、Pruner が設定されている場合、ctx がキャンセルされるまで pruneInterval ごとに
+// リーダーのみが終了済みジョブの履歴を削除します。削除には時間がかかることがあるため、
+// スケジューリングのティックを遅らせないよう別のgoroutineで実行します。
+func (l *Lifecycle) pruneLoop(ctx context.Context) {
+	if l.pruner == nil {
+		return
+	}
+
+	ticker := time.NewTicker(l.pruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !l.elector.IsLeader() {
+				continue
+			}
+			if _, err := l.pruner.Prune(ctx, time.Now()); err != nil && ctx.Err() == nil {
+				l.logger.Error("error in Prune", slog.Any("error", err))
+			}
+		}
+	}
+}
+
+// drain は、ワーカープールが停止するのを猶予期間まで待ち、過ぎた場合は実行中のジョブを中断して Pending に戻させます。
+func (l *Lifecycle) drain(poolDone <-chan struct{}) {
+	timer := time.NewTimer(l.gracePeriod)
+	defer timer.Stop()
+
+	select {
+	case <-poolDone:
+		return
+	case <-timer.C:
+	}
+
+	l.logger.Warn("grace period expired; returning unfinished jobs to pending", slog.Int("running", l.workerPool.Busy()))
+	l.workerPool.Interrupt()
+	<-poolDone
+}
+
+// goDone は、f を新しいgoroutineで実行し、f が戻ると閉じられるチャネルを返します。
+func goDone(f func()) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f()
+	}()
+	return done
+}
@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/memory"
+)
+
+// newTestLifecycle は、インメモリのリポジトリを使用する Lifecycle を生成します。
+func newTestLifecycle(taskRepo domain.TaskRepository, jobRepo domain.JobRepository, client HTTPClient, opts ...LifecycleOption) (*Lifecycle, *LeaderElector) {
+	elector := NewLeaderElector(memory.NewInMemoryLeaderLock(memory.NewLeaderLockStore(), "node", time.Minute), time.Second)
+	pool := NewWorkerPool(NewExecutor(taskRepo, jobRepo, client), 2, 5*time.Millisecond)
+	lifecycle := NewLifecycle(NewScheduler(taskRepo, jobRepo), NewReaper(taskRepo, jobRepo), elector, pool, 5*time.Millisecond, opts...)
+	return lifecycle, elector
+}
+
+// runLifecycle は、Lifecycle をバックグラウンドで実行し、シャットダウンを開始する関数と、Run が戻ると閉じられるチャネルを返します。
+func runLifecycle(lifecycle *Lifecycle) (shutdown func(), done <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	return cancel, goDone(func() { lifecycle.Run(ctx) })
+}
+
+func TestLifecycle_Run_TicksUntilShutdown(t *testing.T) {
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := memory.NewInMemoryJobRepository()
+	monitor := NewLoopMonitor()
+	lifecycle, elector := newTestLifecycle(taskRepo, jobRepo, http.DefaultClient, WithLifecycleLoopMonitor(monitor))
+
+	shutdown, done := runLifecycle(lifecycle)
+	assert.Eventually(t, func() bool { return elector.IsLeader() && !monitor.LastTick().IsZero() }, time.Second, 5*time.Millisecond)
+
+	shutdown()
+	<-done
+	assert.False(t, elector.IsLeader(), "leadership should be released on shutdown")
+
+	// The scheduling ticker is stopped
+	lastTick := monitor.LastTick()
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, lastTick, monitor.LastTick())
+}
+
+func TestLifecycle_Run_WaitsForInFlightJobs(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := memory.NewInMemoryJobRepository()
+	task := setupTask(t, taskRepo, server)
+	job := enqueuePendingJob(t, jobRepo, task.ID)
+	lifecycle, _ := newTestLifecycle(taskRepo, jobRepo, server.Client(), WithGracePeriod(time.Minute))
+
+	shutdown, done := runLifecycle(lifecycle)
+	<-started
+	shutdown()
+
+	// Run must not return while the job is still in flight
+	select {
+	case <-done:
+		t.Fatal("lifecycle stopped before the in-flight job finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	<-done
+	stored, err := jobRepo.FindByID(context.Background(), job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusSuccess, stored.Status)
+}
+
+func TestLifecycle_Run_ReturnsUnfinishedJobsToPending(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := memory.NewInMemoryJobRepository()
+	task := setupTask(t, taskRepo, server)
+	job := enqueuePendingJob(t, jobRepo, task.ID)
+	lifecycle, _ := newTestLifecycle(taskRepo, jobRepo, server.Client(), WithGracePeriod(20*time.Millisecond))
+
+	shutdown, done := runLifecycle(lifecycle)
+	<-started
+	shutdown()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("lifecycle did not stop after the grace period")
+	}
+
+	ctx := context.Background()
+	stored, err := jobRepo.FindByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusPending, stored.Status)
+	assert.Equal(t, 0, stored.RetryCount, "an interrupted run must not consume a retry")
+
+	results, err := jobRepo.FindResultsByJobID(ctx, job.ID)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, domain.ErrJobInterrupted.Error(), results[0].Error)
+
+	// Another node can pick the job up
+	dequeued, err := jobRepo.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, dequeued)
+	assert.Equal(t, job.ID, dequeued.ID)
+}
+
+func TestLifecycle_Run_PrunesWhileLeader(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := memory.NewInMemoryJobRepository()
+	// The task is paused so that only pruning changes its jobs
+	task := setupTask(t, taskRepo, server)
+	task.Status = domain.TaskStatusPaused
+	require.NoError(t, taskRepo.Save(ctx, task))
+
+	// Finish two jobs through the queue so that the worker pool does not pick them up
+	var finished []*domain.Job
+	for _, age := range []time.Duration{2 * time.Hour, time.Hour} {
+		job := &domain.Job{ID: uuid.NewString(), TaskID: task.ID, ScheduledAt: time.Now().Add(-age)}
+		require.NoError(t, jobRepo.Enqueue(ctx, job))
+		dequeued, err := jobRepo.Dequeue(ctx)
+		require.NoError(t, err)
+		require.NoError(t, jobRepo.UpdateStatus(ctx, dequeued.ID, domain.JobStatusSuccess))
+		finished = append(finished, job)
+	}
+
+	pruner := NewPruner(taskRepo, jobRepo, domain.RetentionPolicy{Completed: domain.RetentionRule{MaxJobs: 1}})
+	lifecycle, _ := newTestLifecycle(taskRepo, jobRepo, server.Client(), WithPruner(pruner, 5*time.Millisecond))
+
+	shutdown, done := runLifecycle(lifecycle)
+	defer func() {
+		shutdown()
+		<-done
+	}()
+
+	assert.Eventually(t, func() bool { return !jobExists(t, jobRepo, finished[0].ID) }, time.Second, 5*time.Millisecond)
+}
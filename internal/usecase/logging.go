@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"log/slog"
+
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+)
+
+// ログの属性のキーです。ログ基盤でジョブやタスクごとに検索できるよう、すべてのユースケースで共通のキーを使用します。
+const (
+	logKeyTaskID      = "task_id"
+	logKeyJobID       = "job_id"
+	logKeyScheduledAt = "scheduled_at"
+	logKeyAttempt     = "attempt"
+)
+
+// taskLogger は、タスクを識別する属性を付与したロガーを返します。
+func taskLogger(logger *slog.Logger, task *domain.Task) *slog.Logger {
+	return logger.With(slog.String(logKeyTaskID, task.ID))
+}
+
+// jobLogger は、ジョブを識別する属性（task_id・job_id・scheduled_at・attempt）を付与したロガーを返します。
+func jobLogger(logger *slog.Logger, job *domain.Job) *slog.Logger {
+	return logger.With(
+		slog.String(logKeyTaskID, job.TaskID),
+		slog.String(logKeyJobID, job.ID),
+		slog.Time(logKeyScheduledAt, job.ScheduledAt),
+		slog.Int(logKeyAttempt, job.Attempt()),
+	)
+}
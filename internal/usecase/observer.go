@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+)
+
+// SchedulerObserver は、Scheduler の処理を観測するフックです。メトリクスの収集などに使用します。
+type SchedulerObserver interface {
+	// CheckCompleted は、CheckAndEnqueue の1回の実行が終わるたびに、所要時間とその戻り値とともに呼び出されます。
+	CheckCompleted(duration time.Duration, err error)
+}
+
+// ExecutorObserver は、Executor の処理を観測するフックです。メトリクスの収集などに使用します。
+// メソッドは、ワーカーのgoroutineから並行して呼び出されます。
+type ExecutorObserver interface {
+	// JobStarted は、ジョブの試行を開始したときに呼び出されます。
+	JobStarted(job *domain.Job, startedAt time.Time)
+	// JobFinished は、ジョブの試行が終わったときに、保存した実行結果と試行のエラーとともに呼び出されます。
+	// キャンセルされた試行の err は domain.ErrJobCancelled です。
+	JobFinished(job *domain.Job, result *domain.JobResult, err error)
+}
+
+// PrunerObserver は、Pruner の処理を観測するフックです。メトリクスの収集などに使用します。
+type PrunerObserver interface {
+	// JobsPruned は、Prune の1回の実行が終わるたびに、削除したジョブの数とともに呼び出されます。
+	JobsPruned(count int)
+}
+
+// nopObserver は、何もしない SchedulerObserver・ExecutorObserver・PrunerObserver です。
+type nopObserver struct{}
+
+func (nopObserver) CheckCompleted(time.Duration, error)               {}
+func (nopObserver) JobStarted(*domain.Job, time.Time)                 {}
+func (nopObserver) JobFinished(*domain.Job, *domain.JobResult, error) {}
+func (nopObserver) JobsPruned(int)                                    {}
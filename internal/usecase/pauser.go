@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+)
+
+// Pauser は、タスクを一時停止・再開するユースケースを担当します。
+// 誰が・なぜステータスを変更したかをタスクのステータス変更履歴に記録します。
+// 同時に保存されたタスクの更新と競合した場合は、タスクを読み直してやり直します（ModifyTask を参照）。
+type Pauser struct {
+	taskRepo domain.TaskRepository
+}
+
+// NewPauser は新しいPauserインスタンスを生成します。
+func NewPauser(taskRepo domain.TaskRepository) *Pauser {
+	return &Pauser{
+		taskRepo: taskRepo,
+	}
+}
+
+// PauseTask は、タスクを一時停止し、actor と reason を履歴に記録します。
+// 一時停止中のタスクには新しいジョブがエンキューされません。既に一時停止中の場合は何もせずにタスクを返します。
+// actor が空の場合は domain.ErrInvalidArgument を、タスクが存在しない場合は domain.ErrNotFound をラップしたエラーを返します。
+func (p *Pauser) PauseTask(ctx context.Context, taskID, actor, reason string, now time.Time) (*domain.Task, error) {
+	if err := validateActor(actor); err != nil {
+		return nil, err
+	}
+	return ModifyTask(ctx, p.taskRepo, taskID, func(task *domain.Task) (bool, error) {
+		if task.Status == domain.TaskStatusPaused {
+			return false, nil
+		}
+		task.Pause(actor, reason, now)
+		return true, nil
+	})
+}
+
+// ResumeTask は、一時停止中のタスクを再開し、actor と reason を履歴に記録します。
+// 一時停止中に到来した実行時刻は、mode に従って読み飛ばすか、次回のチェックでエンキューします（domain.ResumeMode を参照）。
+// 既に有効な場合は何もせずにタスクを返します。
+// actor が空の場合や mode が無効な場合は domain.ErrInvalidArgument を、
+// タスクが存在しない場合は domain.ErrNotFound をラップしたエラーを返します。
+func (p *Pauser) ResumeTask(ctx context.Context, taskID string, mode domain.ResumeMode, actor, reason string, now time.Time) (*domain.Task, error) {
+	if err := mode.Validate(); err != nil {
+		return nil, err
+	}
+	if err := validateActor(actor); err != nil {
+		return nil, err
+	}
+	return ModifyTask(ctx, p.taskRepo, taskID, func(task *domain.Task) (bool, error) {
+		if task.Status == domain.TaskStatusActive {
+			return false, nil
+		}
+		task.Resume(mode, actor, reason, now)
+		return true, nil
+	})
+}
+
+// validateActor は、ステータスを変更した人やシステムの識別子が指定されているかを検証します。
+func validateActor(actor string) error {
+	if strings.TrimSpace(actor) == "" {
+		return fmt.Errorf("%w: actor is required", domain.ErrInvalidArgument)
+	}
+	return nil
+}
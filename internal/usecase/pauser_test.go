@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+)
+
+func TestPauser_PauseAndResumeTask(t *testing.T) {
+	ctx := context.Background()
+	lastChecked := time.Date(2023, 10, 28, 9, 0, 5, 0, time.UTC)
+	pausedAt := time.Date(2023, 10, 28, 9, 0, 10, 0, time.UTC)
+	resumedAt := time.Date(2023, 10, 28, 9, 3, 10, 0, time.UTC)
+
+	// pauseAndResume は、毎分実行のタスクを3分間一時停止してから mode で再開し、再開直後のチェックでエンキューされたジョブを返します。
+	pauseAndResume := func(t *testing.T, mode domain.ResumeMode) (*domain.Task, []*domain.Job) {
+		t.Helper()
+		task := &domain.Task{
+			ID:             "task1",
+			CronExpression: "* * * * *",
+			Status:         domain.TaskStatusActive,
+			CreatedAt:      lastChecked.Add(-time.Hour),
+			LastCheckedAt:  lastChecked,
+		}
+		taskRepo := &mockTaskRepository{tasks: map[string]*domain.Task{task.ID: task}}
+		jobRepo := &mockJobRepository{}
+		pauser := NewPauser(taskRepo)
+		scheduler := NewScheduler(taskRepo, jobRepo)
+
+		paused, err := pauser.PauseTask(ctx, task.ID, "alice", "incident", pausedAt)
+		require.NoError(t, err)
+		assert.Equal(t, domain.TaskStatusPaused, paused.Status)
+
+		// No job is enqueued while the task is paused
+		require.NoError(t, scheduler.CheckAndEnqueue(ctx, resumedAt.Add(-time.Second)))
+		assert.Empty(t, jobRepo.enqueued)
+
+		resumed, err := pauser.ResumeTask(ctx, task.ID, mode, "bob", "recovered", resumedAt)
+		require.NoError(t, err)
+		assert.Equal(t, domain.TaskStatusActive, resumed.Status)
+		assert.Equal(t, []domain.TaskStatusChange{
+			{Status: domain.TaskStatusPaused, Actor: "alice", Reason: "incident", ChangedAt: pausedAt},
+			{Status: domain.TaskStatusActive, Actor: "bob", Reason: "recovered", ChangedAt: resumedAt},
+		}, resumed.StatusHistory)
+
+		require.NoError(t, scheduler.CheckAndEnqueue(ctx, resumedAt.Add(time.Second)))
+		return resumed, jobRepo.enqueued
+	}
+
+	t.Run("from now skips the runs missed while paused", func(t *testing.T) {
+		_, jobs := pauseAndResume(t, domain.ResumeFromNow)
+		assert.Empty(t, jobs)
+	})
+
+	t.Run("backfill enqueues the runs missed while paused", func(t *testing.T) {
+		_, jobs := pauseAndResume(t, domain.ResumeBackfill)
+		var scheduledAt []time.Time
+		for _, job := range jobs {
+			scheduledAt = append(scheduledAt, job.ScheduledAt)
+		}
+		assert.Equal(t, []time.Time{
+			time.Date(2023, 10, 28, 9, 1, 0, 0, time.UTC),
+			time.Date(2023, 10, 28, 9, 2, 0, 0, time.UTC),
+			time.Date(2023, 10, 28, 9, 3, 0, 0, time.UTC),
+		}, scheduledAt)
+	})
+}
+
+func TestPauser_PauseTask_AlreadyPaused(t *testing.T) {
+	task := &domain.Task{ID: "task1", Status: domain.TaskStatusPaused}
+	taskRepo := &mockTaskRepository{tasks: map[string]*domain.Task{task.ID: task}}
+
+	paused, err := NewPauser(taskRepo).PauseTask(context.Background(), task.ID, "alice", "", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, domain.TaskStatusPaused, paused.Status)
+	assert.Empty(t, paused.StatusHistory, "pausing a paused task must not be recorded")
+}
+
+func TestPauser_InvalidInput(t *testing.T) {
+	ctx := context.Background()
+	task := &domain.Task{ID: "task1", Status: domain.TaskStatusPaused}
+	taskRepo := &mockTaskRepository{tasks: map[string]*domain.Task{task.ID: task}}
+	pauser := NewPauser(taskRepo)
+
+	_, err := pauser.PauseTask(ctx, task.ID, " ", "", time.Now())
+	assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+
+	_, err = pauser.ResumeTask(ctx, task.ID, domain.ResumeMode(0), "alice", "", time.Now())
+	assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+
+	_, err = pauser.ResumeTask(ctx, task.ID, domain.ResumeFromNow, "", "", time.Now())
+	assert.ErrorIs(t, err, domain.ErrInvalidArgument)
+	assert.Equal(t, domain.TaskStatusPaused, task.Status)
+
+	_, err = pauser.PauseTask(ctx, "nonexistent", "alice", "", time.Now())
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = pauser.ResumeTask(ctx, "nonexistent", domain.ResumeBackfill, "alice", "", time.Now())
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+)
+
+// DefaultPruneBatchSize は、1回の削除で削除するジョブの数のデフォルトです。
+const DefaultPruneBatchSize = 1000
+
+// Pruner は、保持ポリシーを超えた終了済みジョブの履歴を、その実行結果とともに削除する責務を担当します。
+// 保持ポリシーを持つタスクにはそのポリシーを、それ以外のタスク（削除済みのタスクを含む）には
+// スケジューラー全体のポリシーを適用します。
+type Pruner struct {
+	taskRepo    domain.TaskRepository
+	historyRepo domain.JobHistoryRepository
+	// policy は、保持ポリシーを持たないタスクに適用する、スケジューラー全体の保持ポリシーです。
+	policy domain.RetentionPolicy
+	// batchSize は、1回の削除で削除するジョブの最大数です。大量の履歴を一度に削除してデータベースを長時間ロックしないよう、分割して削除します。
+	batchSize int
+	observer  PrunerObserver
+	logger    *slog.Logger
+}
+
+// PrunerOption は、Pruner の設定を変更するオプションです。
+type PrunerOption func(*Pruner)
+
+// WithPruneBatchSize は、1回の削除で削除するジョブの最大数を設定します。
+// 1未満の値は無視し、それまでの値（デフォルトは DefaultPruneBatchSize）を使用します。
+func WithPruneBatchSize(n int) PrunerOption {
+	return func(p *Pruner) {
+		if n > 0 {
+			p.batchSize = n
+		}
+	}
+}
+
+// WithPrunerObserver は、Pruner の処理を観測する PrunerObserver を設定します。
+func WithPrunerObserver(o PrunerObserver) PrunerOption {
+	return func(p *Pruner) {
+		p.observer = o
+	}
+}
+
+// WithPrunerLogger は、Pruner がログを出力するロガーを設定します。
+func WithPrunerLogger(logger *slog.Logger) PrunerOption {
+	return func(p *Pruner) {
+		p.logger = logger
+	}
+}
+
+// NewPruner は新しいPrunerインスタンスを生成します。
+// policy は、保持ポリシーを持たないタスクに適用するスケジューラー全体の保持ポリシーです。
+// バッチサイズのデフォルトは DefaultPruneBatchSize で、ログはデフォルトで slog.Default() に出力します。
+func NewPruner(taskRepo domain.TaskRepository, historyRepo domain.JobHistoryRepository, policy domain.RetentionPolicy, opts ...PrunerOption) *Pruner {
+	p := &Pruner{
+		taskRepo:    taskRepo,
+		historyRepo: historyRepo,
+		policy:      policy,
+		batchSize:   DefaultPruneBatchSize,
+		observer:    nopObserver{},
+		logger:      slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Prune は、now の時点で保持ポリシーを超えている終了済みジョブを削除し、削除したジョブの数を返します。
+// 途中でエラーになった場合も、それまでに削除したジョブの数を返します。
+func (p *Pruner) Prune(ctx context.Context, now time.Time) (int, error) {
+	tasks, err := p.taskRepo.FindAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	defer func() {
+		if deleted > 0 {
+			p.logger.Info("pruned finished jobs", slog.Int("deleted", deleted))
+		}
+		p.observer.JobsPruned(deleted)
+	}()
+
+	// 保持ポリシーを持つタスクには、そのポリシーを適用する
+	var customized []string
+	for _, task := range tasks {
+		if task.RetentionPolicy.IsZero() {
+			continue
+		}
+		customized = append(customized, task.ID)
+		n, err := p.prune(ctx, task.RetentionPolicy, domain.JobPruneFilter{TaskID: task.ID}, now)
+		deleted += n
+		if n > 0 {
+			taskLogger(p.logger, task).Debug("pruned finished jobs of task", slog.Int("deleted", n))
+		}
+		if err != nil {
+			return deleted, err
+		}
+	}
+
+	// それ以外のタスクには、スケジューラー全体のポリシーを適用する
+	n, err := p.prune(ctx, p.policy, domain.JobPruneFilter{ExcludeTaskIDs: customized}, now)
+	deleted += n
+	return deleted, err
+}
+
+// prune は、保持ポリシーの各ルールを target で指定したタスクのジョブに適用し、削除したジョブの数を返します。
+// 各ルールについて、削除対象がなくなるまで batchSize 件ずつ削除します。
+func (p *Pruner) prune(ctx context.Context, policy domain.RetentionPolicy, target domain.JobPruneFilter, now time.Time) (int, error) {
+	rules := []struct {
+		rule     domain.RetentionRule
+		statuses []domain.JobStatus
+	}{
+		{policy.Completed, domain.CompletedJobStatuses},
+		{policy.Failed, domain.FailedJobStatuses},
+	}
+
+	deleted := 0
+	for _, r := range rules {
+		if r.rule.IsUnlimited() {
+			continue
+		}
+		filter := domain.NewJobPruneFilter(r.rule, r.statuses, now)
+		filter.TaskID = target.TaskID
+		filter.ExcludeTaskIDs = target.ExcludeTaskIDs
+
+		for {
+			n, err := p.historyRepo.DeleteFinished(ctx, filter, p.batchSize)
+			deleted += n
+			if err != nil {
+				return deleted, err
+			}
+			if n < p.batchSize {
+				break
+			}
+		}
+	}
+	return deleted, nil
+}
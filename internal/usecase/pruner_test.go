@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/memory"
+)
+
+// enqueueFinishedJob は、指定したタスクの、age だけ前にスケジュールされ終了したジョブを保存して返します。
+func enqueueFinishedJob(t *testing.T, jobRepo domain.JobRepository, taskID string, now time.Time, age time.Duration, status domain.JobStatus) *domain.Job {
+	t.Helper()
+
+	job := &domain.Job{
+		ID:          uuid.NewString(),
+		TaskID:      taskID,
+		ScheduledAt: now.Add(-age),
+		FinishedAt:  now.Add(-age),
+		Status:      status,
+	}
+	require.NoError(t, jobRepo.Enqueue(context.Background(), job))
+	return job
+}
+
+// jobExists は、ジョブが削除されずに残っているかを返します。
+func jobExists(t *testing.T, jobRepo domain.JobRepository, jobID string) bool {
+	t.Helper()
+
+	job, err := jobRepo.FindByID(context.Background(), jobID)
+	require.NoError(t, err)
+	return job != nil
+}
+
+func TestPruner_Prune(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := memory.NewInMemoryJobRepository()
+	now := time.Date(2024, time.January, 8, 12, 0, 0, 0, time.UTC)
+
+	// The global policy keeps completed jobs for a day and failed jobs for a week
+	global := domain.RetentionPolicy{
+		Completed: domain.RetentionRule{MaxAge: 24 * time.Hour},
+		Failed:    domain.RetentionRule{MaxAge: 7 * 24 * time.Hour},
+	}
+	globalTask := setupTask(t, taskRepo, server)
+	globalOld := enqueueFinishedJob(t, jobRepo, globalTask.ID, now, 48*time.Hour, domain.JobStatusSuccess)
+	globalRecent := enqueueFinishedJob(t, jobRepo, globalTask.ID, now, time.Hour, domain.JobStatusSuccess)
+	globalFailed := enqueueFinishedJob(t, jobRepo, globalTask.ID, now, 47*time.Hour, domain.JobStatusFailed)
+
+	// A task with its own policy keeps only its latest completed job, and failed jobs forever
+	customTask := setupTask(t, taskRepo, server)
+	customTask.RetentionPolicy = domain.RetentionPolicy{Completed: domain.RetentionRule{MaxJobs: 1}}
+	require.NoError(t, taskRepo.Save(ctx, customTask))
+	customOld := enqueueFinishedJob(t, jobRepo, customTask.ID, now, 2*time.Hour, domain.JobStatusSuccess)
+	customLatest := enqueueFinishedJob(t, jobRepo, customTask.ID, now, time.Hour, domain.JobStatusSuccess)
+	customFailed := enqueueFinishedJob(t, jobRepo, customTask.ID, now, 30*24*time.Hour, domain.JobStatusTimedOut)
+
+	// Jobs of a deleted task follow the global policy
+	orphan := enqueueFinishedJob(t, jobRepo, uuid.NewString(), now, 48*time.Hour, domain.JobStatusCancelled)
+
+	observer := &recordingObserver{}
+	pruner := NewPruner(taskRepo, jobRepo, global, WithPrunerObserver(observer))
+
+	deleted, err := pruner.Prune(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 3, deleted)
+	assert.Equal(t, []int{3}, observer.pruned)
+
+	for _, job := range []*domain.Job{globalOld, customOld, orphan} {
+		assert.False(t, jobExists(t, jobRepo, job.ID), "job %s should be pruned", job.ID)
+	}
+	for _, job := range []*domain.Job{globalRecent, globalFailed, customLatest, customFailed} {
+		assert.True(t, jobExists(t, jobRepo, job.ID), "job %s should be kept", job.ID)
+	}
+}
+
+func TestPruner_Prune_InBatches(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := memory.NewInMemoryJobRepository()
+	now := time.Date(2024, time.January, 8, 12, 0, 0, 0, time.UTC)
+
+	task := setupTask(t, taskRepo, server)
+	for i := range 5 {
+		enqueueFinishedJob(t, jobRepo, task.ID, now, time.Duration(i+1)*time.Hour, domain.JobStatusFailed)
+	}
+	pending := enqueuePendingJob(t, jobRepo, task.ID)
+
+	global := domain.RetentionPolicy{Failed: domain.RetentionRule{MaxJobs: 1}}
+	pruner := NewPruner(taskRepo, jobRepo, global, WithPruneBatchSize(2))
+
+	deleted, err := pruner.Prune(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 4, deleted)
+	assert.True(t, jobExists(t, jobRepo, pending.ID), "unfinished jobs are never pruned")
+
+	jobs, err := jobRepo.FindByTaskID(ctx, task.ID, 10)
+	require.NoError(t, err)
+	assert.Len(t, jobs, 2)
+}
+
+func TestPruner_Prune_NonPositiveBatchSize(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := memory.NewInMemoryJobRepository()
+	now := time.Date(2024, time.January, 8, 12, 0, 0, 0, time.UTC)
+
+	task := setupTask(t, taskRepo, server)
+	for i := range 3 {
+		enqueueFinishedJob(t, jobRepo, task.ID, now, time.Duration(i+1)*time.Hour, domain.JobStatusFailed)
+	}
+
+	// A non-positive batch size falls back to the default instead of never finishing
+	global := domain.RetentionPolicy{Failed: domain.RetentionRule{MaxJobs: 1}}
+	for _, batchSize := range []int{0, -1} {
+		pruner := NewPruner(taskRepo, jobRepo, global, WithPruneBatchSize(batchSize))
+		_, err := pruner.Prune(ctx, now)
+		require.NoError(t, err)
+	}
+
+	jobs, err := jobRepo.FindByTaskID(ctx, task.ID, 10)
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1)
+}
+
+func TestPruner_Prune_UnlimitedByDefault(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := memory.NewInMemoryJobRepository()
+	now := time.Date(2024, time.January, 8, 12, 0, 0, 0, time.UTC)
+
+	task := setupTask(t, taskRepo, server)
+	job := enqueueFinishedJob(t, jobRepo, task.ID, now, 365*24*time.Hour, domain.JobStatusSuccess)
+
+	deleted, err := NewPruner(taskRepo, jobRepo, domain.RetentionPolicy{}).Prune(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 0, deleted)
+	assert.True(t, jobExists(t, jobRepo, job.ID))
+}
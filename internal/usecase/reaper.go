@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+)
+
+// reapBatchSize は、1回の ReapExpired で回収するジョブの最大数です。
+const reapBatchSize = 100
+
+// Reaper は、リースの期限が切れたまま実行中となっているジョブを回収する責務を担当します。
+// Executor がクラッシュした場合など、ハートビートが途絶えたジョブをリトライポリシーに従って
+// Pending に戻すか、Failed として確定させます。
+type Reaper struct {
+	taskRepo domain.TaskRepository
+	jobRepo  domain.JobRepository
+	// random は、リトライ間隔のジッター計算に使用する [0.0, 1.0) の乱数を返します。
+	random func() float64
+	logger *slog.Logger
+}
+
+// ReaperOption は、Reaper の設定を変更するオプションです。
+type ReaperOption func(*Reaper)
+
+// WithReaperLogger は、Reaper がログを出力するロガーを設定します。
+func WithReaperLogger(logger *slog.Logger) ReaperOption {
+	return func(r *Reaper) {
+		r.logger = logger
+	}
+}
+
+// NewReaper は新しいReaperインスタンスを生成します。
+// ログはデフォルトで slog.Default() に出力します。
+func NewReaper(taskRepo domain.TaskRepository, jobRepo domain.JobRepository, opts ...ReaperOption) *Reaper {
+	r := &Reaper{
+		taskRepo: taskRepo,
+		jobRepo:  jobRepo,
+		random:   rand.Float64,
+		logger:   slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// ReapExpired は、now の時点でリースの期限が切れているジョブを回収し、回収したジョブの数を返します。
+// タスクのリトライポリシーで再試行が許可されていればバックオフ後に再実行されるようキューへ戻し、
+// そうでなければ（タスクが存在しない場合を含む）Failed にします。
+// 期限切れのジョブを取得した後に終了したジョブやリースが延長されたジョブは回収しません。
+func (r *Reaper) ReapExpired(ctx context.Context, now time.Time) (int, error) {
+	jobs, err := r.jobRepo.FindExpiredLeases(ctx, now, reapBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	reaped := 0
+	for _, job := range jobs {
+		logger := jobLogger(r.logger, job)
+		ok, err := r.reap(ctx, logger, job, now)
+		if err != nil {
+			logger.Error("failed to reap job", slog.Any("error", err))
+			continue
+		}
+		if ok {
+			reaped++
+		}
+	}
+
+	return reaped, nil
+}
+
+// reap は、リースの期限が切れた1つのジョブを回収し、回収したかを返します。
+// ジョブの状態の更新は ReclaimExpired で行い、取得した後に終了したジョブを上書きしないようにします。
+func (r *Reaper) reap(ctx context.Context, logger *slog.Logger, job *domain.Job, now time.Time) (bool, error) {
+	task, err := r.taskRepo.FindByID(ctx, job.TaskID)
+	if err != nil {
+		return false, err
+	}
+
+	retry := task != nil && task.RetryPolicy.ShouldRetry(job.RetryCount)
+	var delay time.Duration
+	if retry {
+		delay = task.RetryPolicy.NextDelay(job.RetryCount, r.random)
+		job.ScheduleRetry(now.Add(delay))
+	} else {
+		job.MarkAsFailed()
+		job.LeaseExpiresAt = time.Time{}
+	}
+
+	reclaimed, err := r.jobRepo.ReclaimExpired(ctx, job, now)
+	if err != nil || !reclaimed {
+		if err == nil {
+			logger.Debug("job finished or renewed its lease before it was reaped")
+		}
+		return false, err
+	}
+
+	if retry {
+		logger.Warn("job lease expired; job will be retried",
+			slog.Duration("retry_in", delay), slog.Int("retry", job.RetryCount), slog.Int("max_retries", task.RetryPolicy.MaxRetries))
+	} else {
+		logger.Warn("job lease expired; marked as failed")
+	}
+	return true, nil
+}
@@ -0,0 +1,210 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/memory"
+)
+
+// dequeueAbandonedJob は、ジョブをデキューして Running にしたまま放置し、
+// Executor がクラッシュした状況を再現します。
+func dequeueAbandonedJob(t *testing.T, jobRepo domain.JobRepository) *domain.Job {
+	t.Helper()
+
+	ctx := context.Background()
+	job, err := jobRepo.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	require.NoError(t, jobRepo.UpdateStatus(ctx, job.ID, domain.JobStatusRunning))
+	return job
+}
+
+func TestReaper_ReapExpired_Retry(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := memory.NewInMemoryJobRepository(memory.WithVisibilityTimeout(time.Minute))
+	reaper := NewReaper(taskRepo, jobRepo)
+
+	task := setupTask(t, taskRepo, server)
+	task.RetryPolicy = domain.RetryPolicy{MaxRetries: 1, Backoff: domain.BackoffFixed}
+	require.NoError(t, taskRepo.Save(ctx, task))
+	enqueuePendingJob(t, jobRepo, task.ID)
+	dequeueAbandonedJob(t, jobRepo)
+
+	// The lease is still valid
+	reaped, err := reaper.ReapExpired(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 0, reaped)
+
+	// After the lease expires, the job is returned to Pending
+	expiredAt := time.Now().Add(2 * time.Minute)
+	reaped, err = reaper.ReapExpired(ctx, expiredAt)
+	require.NoError(t, err)
+	assert.Equal(t, 1, reaped)
+
+	expired, err := jobRepo.FindExpiredLeases(ctx, expiredAt, 10)
+	require.NoError(t, err)
+	assert.Empty(t, expired)
+
+	// The retry is scheduled relative to the time the lease was reaped
+	notYet, err := jobRepo.Dequeue(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, notYet)
+}
+
+func TestReaper_ReapExpired_RetryDequeuedAgain(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := memory.NewInMemoryJobRepository(memory.WithVisibilityTimeout(time.Millisecond))
+	reaper := NewReaper(taskRepo, jobRepo)
+
+	task := setupTask(t, taskRepo, server)
+	task.RetryPolicy = domain.RetryPolicy{MaxRetries: 1, Backoff: domain.BackoffFixed}
+	require.NoError(t, taskRepo.Save(ctx, task))
+	job := enqueuePendingJob(t, jobRepo, task.ID)
+	dequeueAbandonedJob(t, jobRepo)
+
+	time.Sleep(5 * time.Millisecond)
+	reaped, err := reaper.ReapExpired(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, reaped)
+
+	retried, err := jobRepo.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, retried)
+	assert.Equal(t, job.ID, retried.ID)
+	assert.Equal(t, domain.JobStatusRunning, retried.Status)
+	assert.Equal(t, 1, retried.RetryCount)
+}
+
+func TestReaper_ReapExpired_Failed(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := memory.NewInMemoryJobRepository()
+	reaper := NewReaper(taskRepo, jobRepo)
+
+	// Without a retry policy the job is terminally Failed
+	task := setupTask(t, taskRepo, server)
+	enqueuePendingJob(t, jobRepo, task.ID)
+	withoutRetry := dequeueAbandonedJob(t, jobRepo)
+
+	// A job whose task no longer exists is Failed as well
+	enqueuePendingJob(t, jobRepo, "nonexistent-task")
+	withoutTask := dequeueAbandonedJob(t, jobRepo)
+
+	reaped, err := reaper.ReapExpired(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2, reaped)
+	for _, job := range []*domain.Job{withoutRetry, withoutTask} {
+		stored, err := jobRepo.FindByID(ctx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.JobStatusFailed, stored.Status)
+		assert.False(t, stored.FinishedAt.IsZero())
+		assert.True(t, stored.LeaseExpiresAt.IsZero())
+	}
+
+	expired, err := jobRepo.FindExpiredLeases(ctx, time.Now().Add(time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, expired)
+}
+
+func TestReaper_ReapExpired_Logging(t *testing.T) {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := memory.NewInMemoryJobRepository()
+	reaper := NewReaper(taskRepo, jobRepo, WithReaperLogger(newTestLogger(&buf)))
+
+	task := setupTask(t, taskRepo, server)
+	task.RetryPolicy = domain.RetryPolicy{MaxRetries: 1, Backoff: domain.BackoffFixed}
+	require.NoError(t, taskRepo.Save(ctx, task))
+	job := enqueuePendingJob(t, jobRepo, task.ID)
+	dequeueAbandonedJob(t, jobRepo)
+
+	reaped, err := reaper.ReapExpired(ctx, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, reaped)
+
+	lines := parseLogLines(t, &buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "job lease expired; job will be retried", lines[0]["msg"])
+	assert.Equal(t, task.ID, lines[0]["task_id"])
+	assert.Equal(t, job.ID, lines[0]["job_id"])
+	assert.Equal(t, float64(1), lines[0]["retry"])
+	assert.Equal(t, float64(1), lines[0]["max_retries"])
+}
+
+// finishingJobRepository は、FindExpiredLeases で返したジョブを直後に status で終了させ、
+// Reaper が取得した後にジョブの実行が終わった状況を再現します。
+type finishingJobRepository struct {
+	*memory.InMemoryJobRepository
+	status domain.JobStatus
+}
+
+func (r *finishingJobRepository) FindExpiredLeases(ctx context.Context, now time.Time, limit int) ([]*domain.Job, error) {
+	jobs, err := r.InMemoryJobRepository.FindExpiredLeases(ctx, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, job := range jobs {
+		if err := r.UpdateStatus(ctx, job.ID, r.status); err != nil {
+			return nil, err
+		}
+	}
+	return jobs, nil
+}
+
+func TestReaper_ReapExpired_FinishedAfterLookup(t *testing.T) {
+	for _, status := range []domain.JobStatus{domain.JobStatusSuccess, domain.JobStatusFailed} {
+		for _, retry := range []bool{true, false} {
+			ctx := context.Background()
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			defer server.Close()
+
+			taskRepo := memory.NewInMemoryTaskRepository()
+			jobRepo := &finishingJobRepository{InMemoryJobRepository: memory.NewInMemoryJobRepository(), status: status}
+			reaper := NewReaper(taskRepo, jobRepo)
+
+			task := setupTask(t, taskRepo, server)
+			if retry {
+				task.RetryPolicy = domain.RetryPolicy{MaxRetries: 1, Backoff: domain.BackoffFixed}
+				require.NoError(t, taskRepo.Save(ctx, task))
+			}
+			job := enqueuePendingJob(t, jobRepo, task.ID)
+			dequeueAbandonedJob(t, jobRepo)
+
+			reaped, err := reaper.ReapExpired(ctx, time.Now().Add(time.Hour))
+			require.NoError(t, err)
+			assert.Equal(t, 0, reaped)
+
+			// The job keeps the status it finished with and is not queued again
+			stored, err := jobRepo.FindByID(ctx, job.ID)
+			require.NoError(t, err)
+			assert.Equal(t, status, stored.Status, "retry: %v", retry)
+			assert.Equal(t, 0, stored.RetryCount, "retry: %v", retry)
+			dequeued, err := jobRepo.Dequeue(ctx)
+			require.NoError(t, err)
+			assert.Nil(t, dequeued, "retry: %v", retry)
+		}
+	}
+}
@@ -2,69 +2,202 @@ package usecase
 
 import (
 	"context"
-	"log"
+	"errors"
+	"log/slog"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/yourname/go-dist-scheduler/internal/domain"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Scheduler は、タスクをチェックしてジョブをエンキューするユースケースを担当します。
 type Scheduler struct {
-	taskRepo domain.TaskRepository
-	jobRepo  domain.JobRepository
+	taskRepo         domain.TaskRepository
+	jobRepo          domain.JobRepository
+	misfireThreshold time.Duration
+	observer         SchedulerObserver
+	logger           *slog.Logger
+	tracer           trace.Tracer
+}
+
+// SchedulerOption は、Scheduler の設定を変更するオプションです。
+type SchedulerOption func(*Scheduler)
+
+// WithMisfireThreshold は、実行時刻を過ぎてからどれだけ経過した実行時刻をミスファイアとみなすかを設定します。
+// スケジューラーのチェック間隔より十分長い値を指定してください。
+func WithMisfireThreshold(d time.Duration) SchedulerOption {
+	return func(s *Scheduler) {
+		s.misfireThreshold = d
+	}
+}
+
+// WithSchedulerObserver は、Scheduler の処理を観測するフックを設定します。
+func WithSchedulerObserver(o SchedulerObserver) SchedulerOption {
+	return func(s *Scheduler) {
+		s.observer = o
+	}
+}
+
+// WithSchedulerLogger は、Scheduler がログを出力するロガーを設定します。
+func WithSchedulerLogger(l *slog.Logger) SchedulerOption {
+	return func(s *Scheduler) {
+		s.logger = l
+	}
+}
+
+// WithSchedulerTracerProvider は、ジョブのトレースを記録する TracerProvider を設定します。
+func WithSchedulerTracerProvider(tp trace.TracerProvider) SchedulerOption {
+	return func(s *Scheduler) {
+		s.tracer = tp.Tracer(tracerName)
+	}
 }
 
 // NewScheduler は新しいSchedulerインスタンスを生成します。
-func NewScheduler(taskRepo domain.TaskRepository, jobRepo domain.JobRepository) *Scheduler {
-	return &Scheduler{
-		taskRepo: taskRepo,
-		jobRepo:  jobRepo,
+// ミスファイアとみなすまでの時間のデフォルトは domain.DefaultMisfireThreshold で、
+// ログはデフォルトで slog.Default() に、トレースはデフォルトでグローバルな TracerProvider（otel.GetTracerProvider）に記録します。
+func NewScheduler(taskRepo domain.TaskRepository, jobRepo domain.JobRepository, opts ...SchedulerOption) *Scheduler {
+	s := &Scheduler{
+		taskRepo:         taskRepo,
+		jobRepo:          jobRepo,
+		misfireThreshold: domain.DefaultMisfireThreshold,
+		observer:         nopObserver{},
+		logger:           slog.Default(),
+		tracer:           otel.GetTracerProvider().Tracer(tracerName),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // CheckAndEnqueue は、実行時刻が到来したタスクを元にジョブを作成し、キューに追加します。
-// スケジューラのダウンタイムなどで実行されなかったジョブも、遅れてエンキューされます。
-func (s *Scheduler) CheckAndEnqueue(ctx context.Context, now time.Time) error {
+// スケジューラのダウンタイムなどで実行されなかった実行時刻（ミスファイア）は、タスクの MisfirePolicy に従って
+// 遅れてエンキューされるか、読み飛ばされます。読み飛ばした実行時刻が後から再びチェックされることはありません。
+// ジョブごとに新しいトレースを開始し、そのトレースコンテキストをジョブに保存します。
+// ジョブIDはタスクIDと実行時刻から決定的に生成されるため、同じ実行時刻のジョブが重複してエンキューされることはありません。
+// タスクの前回のジョブが終わっていない場合は、タスクの ConcurrencyPolicy に従って新しいジョブを読み飛ばすか、
+// 前回のジョブをキャンセルして置き換えます。
+// ctx がキャンセルされた場合は、残りのタスクをチェックせずに ctx のエラーを返します。
+func (s *Scheduler) CheckAndEnqueue(ctx context.Context, now time.Time) (err error) {
+	startedAt := time.Now()
+	defer func() {
+		s.observer.CheckCompleted(time.Since(startedAt), err)
+	}()
+
 	tasks, err := s.taskRepo.FindAllActive(ctx)
 	if err != nil {
 		return err
 	}
 
 	for _, task := range tasks {
+		// シャットダウン中は残りのタスクをチェックしない。チェックしなかったタスクは次回のチェックで処理される
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		logger := taskLogger(s.logger, task)
 		lastChecked := task.LastCheckedAt
 		if lastChecked.IsZero() {
 			lastChecked = task.CreatedAt
 		}
+		// 作成日時も分からないタスクは、ゼロ時刻から数千年分の実行時刻を列挙しないよう、now からチェックを始める
+		if lastChecked.IsZero() {
+			lastChecked = now
+		}
 
 		dueRunTimes, err := task.GetDueRunTimes(lastChecked, now)
 		if err != nil {
-			log.Printf("failed to get due run times for task %s: %v", task.ID, err)
+			logger.Error("failed to get due run times", slog.Any("error", err))
+			continue
+		}
+
+		runTimes := task.MisfirePolicy.Apply(dueRunTimes, now, s.misfireThreshold)
+		if skipped := len(dueRunTimes) - len(runTimes); skipped > 0 {
+			logger.Warn("skipped misfired runs", slog.Int("skipped", skipped))
+		}
+
+		runTimes, err = s.applyConcurrencyPolicy(ctx, logger, task, runTimes)
+		if err != nil {
+			// LastCheckedAt を更新せず、次回のチェックで再試行する
+			logger.Error("failed to apply concurrency policy", slog.Any("error", err))
 			continue
 		}
 
-		for _, runTime := range dueRunTimes {
+		for _, runTime := range runTimes {
 			newJob := &domain.Job{
-				ID:          uuid.New().String(),
-				TaskID:      task.ID,
-				ScheduledAt: runTime,
-				Status:      domain.JobStatusPending,
-				CreatedAt:   now,
-				UpdatedAt:   now,
+				ID:            domain.NewJobID(task.ID, runTime),
+				TaskID:        task.ID,
+				ScheduledAt:   runTime,
+				Status:        domain.JobStatusPending,
+				TriggerSource: domain.TriggerSourceCron,
+				CreatedAt:     now,
+				UpdatedAt:     now,
 			}
 
-			if err := s.jobRepo.Enqueue(ctx, newJob); err != nil {
-				log.Printf("failed to enqueue job for task %s: %v", task.ID, err)
+			jobLog := jobLogger(s.logger, newJob)
+			jobCtx, span := startJobTrace(ctx, s.tracer, "schedule job", newJob)
+			err := enqueueTraced(jobCtx, s.tracer, s.jobRepo, newJob)
+			span.End()
+			if err != nil {
+				// 前回のチェックで LastCheckedAt の保存に失敗した場合や、他のスケジューラーと競合した場合、
+				// 同じ実行時刻のジョブが既にエンキューされている。その場合はエンキュー済みとして扱う。
+				if errors.Is(err, domain.ErrConstraintViolation) {
+					jobLog.Info("job is already enqueued")
+					continue
+				}
+				jobLog.Error("failed to enqueue job", slog.Any("error", err))
 				goto nextTask
 			}
+			jobLog.Info("enqueued job")
 		}
 
-		task.LastCheckedAt = now
-		if err := s.taskRepo.Save(ctx, task); err != nil {
-			log.Printf("failed to update last checked time for task %s: %v", task.ID, err)
+		// task はチェックを始める前に読み込んだものなので、保存すると、その間に行われた一時停止や
+		// タスクの更新を上書きしてしまう。LastCheckedAt だけを更新する。
+		if err := s.taskRepo.UpdateLastCheckedAt(ctx, task.ID, now); err != nil {
+			logger.Error("failed to update last checked time", slog.Any("error", err))
 		}
 	nextTask:
 	}
 
 	return nil
 }
+
+// applyConcurrencyPolicy は、タスクの ConcurrencyPolicy に従って、エンキューする実行時刻を絞り込みます。
+// ConcurrencyReplace の場合は、タスクの未終了のジョブをキャンセルします。
+// 未終了のジョブはリポジトリから取得するため、複数のプロセスでジョブを実行していても判定は一貫します。
+func (s *Scheduler) applyConcurrencyPolicy(ctx context.Context, logger *slog.Logger, task *domain.Task, runTimes []time.Time) ([]time.Time, error) {
+	if task.ConcurrencyPolicy == domain.ConcurrencyAllow || len(runTimes) == 0 {
+		return runTimes, nil
+	}
+
+	// 同時に実行できるのは1つだけのため、同じチェックで複数の実行時刻が到来した場合は最新のもののみ残す
+	latest := runTimes[len(runTimes)-1:]
+	latestJobID := domain.NewJobID(task.ID, latest[0])
+
+	active, err := s.jobRepo.FindActiveByTaskID(ctx, task.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(active) == 0 {
+		return latest, nil
+	}
+
+	if task.ConcurrencyPolicy == domain.ConcurrencyForbid {
+		logger.Warn("skipped runs because the previous job has not finished",
+			slog.Int("skipped", len(runTimes)), slog.String("running_job_id", active[0].ID))
+		return nil, nil
+	}
+
+	for _, job := range active {
+		// 前回のチェックで既にエンキューされた最新の実行時刻のジョブは置き換えない
+		if job.ID == latestJobID {
+			continue
+		}
+		if err := s.jobRepo.Cancel(ctx, job.ID); err != nil {
+			return nil, err
+		}
+		jobLogger(s.logger, job).Info("cancelled job to replace it with a new run")
+	}
+	return latest, nil
+}
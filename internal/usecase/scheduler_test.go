@@ -1,13 +1,17 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/yourname/go-dist-scheduler/internal/domain"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/memory"
 )
 
 // mockTaskRepository は TaskRepository のモック実装です。
@@ -25,6 +29,15 @@ func (m *mockTaskRepository) Save(ctx context.Context, task *domain.Task) error
 	return nil
 }
 
+func (m *mockTaskRepository) UpdateLastCheckedAt(ctx context.Context, taskID string, checkedAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if task, ok := m.tasks[taskID]; ok && checkedAt.After(task.LastCheckedAt) {
+		task.LastCheckedAt = checkedAt
+	}
+	return nil
+}
+
 func (m *mockTaskRepository) FindByID(ctx context.Context, id string) (*domain.Task, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -46,6 +59,23 @@ func (m *mockTaskRepository) FindAllActive(ctx context.Context) ([]*domain.Task,
 	return activeTasks, nil
 }
 
+func (m *mockTaskRepository) FindAll(ctx context.Context) ([]*domain.Task, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var tasks []*domain.Task
+	for _, task := range m.tasks {
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+func (m *mockTaskRepository) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.tasks, id)
+	return nil
+}
+
 // mockJobRepository は JobRepository のモック実装です。
 type mockJobRepository struct {
 	mu         sync.Mutex
@@ -71,6 +101,54 @@ func (m *mockJobRepository) UpdateStatus(ctx context.Context, jobID string, stat
 	return nil
 }
 
+func (m *mockJobRepository) Requeue(ctx context.Context, job *domain.Job) error {
+	return nil
+}
+
+func (m *mockJobRepository) ExtendLease(ctx context.Context, jobID string) error {
+	return nil
+}
+
+func (m *mockJobRepository) FindExpiredLeases(ctx context.Context, now time.Time, limit int) ([]*domain.Job, error) {
+	return nil, nil
+}
+
+func (m *mockJobRepository) ReclaimExpired(ctx context.Context, job *domain.Job, now time.Time) (bool, error) {
+	return false, nil
+}
+
+func (m *mockJobRepository) FindByID(ctx context.Context, jobID string) (*domain.Job, error) {
+	return nil, nil
+}
+
+func (m *mockJobRepository) FindByTaskID(ctx context.Context, taskID string, limit int) ([]*domain.Job, error) {
+	return nil, nil
+}
+
+func (m *mockJobRepository) FindActiveByTaskID(ctx context.Context, taskID string) ([]*domain.Job, error) {
+	return nil, nil
+}
+
+func (m *mockJobRepository) CountPending(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func (m *mockJobRepository) Cancel(ctx context.Context, jobID string) error {
+	return nil
+}
+
+func (m *mockJobRepository) SaveResult(ctx context.Context, result *domain.JobResult) error {
+	return nil
+}
+
+func (m *mockJobRepository) FindResultsByJobID(ctx context.Context, jobID string) ([]*domain.JobResult, error) {
+	return nil, nil
+}
+
+func (m *mockJobRepository) FindRecentResultsByTaskID(ctx context.Context, taskID string, limit int) ([]*domain.JobResult, error) {
+	return nil, nil
+}
+
 func TestScheduler_CheckAndEnqueue(t *testing.T) {
 	jst := time.FixedZone("JST", 9*60*60)
 	now := time.Date(2023, 10, 28, 10, 0, 0, 0, jst)
@@ -163,6 +241,22 @@ func TestScheduler_CheckAndEnqueue(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	t.Run("should treat duplicate enqueue as already enqueued", func(t *testing.T) {
+		tasks := map[string]*domain.Task{
+			"task1": {ID: "task1", CronExpression: "* * * * *", Status: domain.TaskStatusActive, CreatedAt: now.Add(-2 * time.Minute)},
+		}
+		taskRepo := &mockTaskRepository{tasks: tasks}
+		jobRepo := &mockJobRepository{enqueueErr: domain.ErrConstraintViolation}
+		scheduler := NewScheduler(taskRepo, jobRepo)
+
+		err := scheduler.CheckAndEnqueue(context.Background(), now)
+		assert.NoError(t, err)
+
+		task, err := taskRepo.FindByID(context.Background(), "task1")
+		assert.NoError(t, err)
+		assert.Equal(t, now, task.LastCheckedAt, "LastCheckedAt should be updated when jobs are already enqueued")
+	})
+
 	t.Run("should not return error when Enqueue fails", func(t *testing.T) {
 		tasks := map[string]*domain.Task{
 			"task1": {ID: "task1", CronExpression: "* * * * *", Status: domain.TaskStatusActive, CreatedAt: now.Add(-2 * time.Minute)},
@@ -176,8 +270,6 @@ func TestScheduler_CheckAndEnqueue(t *testing.T) {
 	})
 }
 
-
-
 func TestScheduler_CheckAndEnqueue_SaveFailsLastCheckedAtUnchanged(t *testing.T) {
 	jst := time.FixedZone("JST", 9*60*60)
 	now := time.Date(2023, 10, 28, 10, 0, 0, 0, jst)
@@ -211,3 +303,362 @@ func TestScheduler_CheckAndEnqueue_SaveFailsLastCheckedAtUnchanged(t *testing.T)
 	// Verify that no jobs were enqueued
 	assert.Len(t, jobRepo.enqueued, 0, "No jobs should be enqueued when Enqueue fails")
 }
+
+// lastCheckedErrorTaskRepository は、UpdateLastCheckedAt が常に失敗する TaskRepository です。
+type lastCheckedErrorTaskRepository struct {
+	*memory.InMemoryTaskRepository
+}
+
+func (r *lastCheckedErrorTaskRepository) UpdateLastCheckedAt(ctx context.Context, taskID string, checkedAt time.Time) error {
+	return assert.AnError
+}
+
+func TestScheduler_CheckAndEnqueue_ExactlyOncePerScheduledTime(t *testing.T) {
+	ctx := context.Background()
+	jst := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2023, 10, 28, 10, 0, 0, 0, jst)
+
+	task := &domain.Task{
+		ID:             "task1",
+		CronExpression: "* * * * *",
+		Status:         domain.TaskStatusActive,
+		CreatedAt:      now.Add(-2 * time.Minute),
+	}
+	memTaskRepo := memory.NewInMemoryTaskRepository()
+	assert.NoError(t, memTaskRepo.Save(ctx, task))
+
+	// LastCheckedAt is never persisted, so every check sees the same due run times
+	taskRepo := &lastCheckedErrorTaskRepository{InMemoryTaskRepository: memTaskRepo}
+	jobRepo := memory.NewInMemoryJobRepository()
+
+	// Two schedulers racing on the same repositories
+	first := NewScheduler(taskRepo, jobRepo)
+	second := NewScheduler(taskRepo, jobRepo)
+	assert.NoError(t, first.CheckAndEnqueue(ctx, now))
+	assert.NoError(t, second.CheckAndEnqueue(ctx, now))
+	assert.NoError(t, first.CheckAndEnqueue(ctx, now))
+
+	var dequeued []*domain.Job
+	for {
+		job, err := jobRepo.Dequeue(ctx)
+		assert.NoError(t, err)
+		if job == nil {
+			break
+		}
+		dequeued = append(dequeued, job)
+	}
+	assert.Len(t, dequeued, 2, "each scheduled time should be enqueued exactly once")
+}
+
+// pausingTaskRepository は、FindAllActive でタスクを返した直後にそれらを一時停止する TaskRepository です。
+// スケジューラーのチェック中に API からタスクが一時停止された状況を再現します。
+type pausingTaskRepository struct {
+	*memory.InMemoryTaskRepository
+	pausedAt time.Time
+}
+
+func (r *pausingTaskRepository) FindAllActive(ctx context.Context) ([]*domain.Task, error) {
+	tasks, err := r.InMemoryTaskRepository.FindAllActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pauser := NewPauser(r.InMemoryTaskRepository)
+	for _, task := range tasks {
+		if _, err := pauser.PauseTask(ctx, task.ID, "alice", "incident", r.pausedAt); err != nil {
+			return nil, err
+		}
+	}
+	return tasks, nil
+}
+
+func TestScheduler_CheckAndEnqueue_KeepsConcurrentPause(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 10, 28, 10, 0, 30, 0, time.UTC)
+
+	memTaskRepo := memory.NewInMemoryTaskRepository()
+	require.NoError(t, memTaskRepo.Save(ctx, &domain.Task{
+		ID:             "task1",
+		CronExpression: "* * * * *",
+		Status:         domain.TaskStatusActive,
+		CreatedAt:      now.Add(-time.Minute),
+	}))
+	taskRepo := &pausingTaskRepository{InMemoryTaskRepository: memTaskRepo, pausedAt: now}
+	scheduler := NewScheduler(taskRepo, memory.NewInMemoryJobRepository())
+
+	require.NoError(t, scheduler.CheckAndEnqueue(ctx, now))
+
+	// The pause saved during the check is not overwritten by the scheduler's stale copy of the task
+	stored, err := memTaskRepo.FindByID(ctx, "task1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.TaskStatusPaused, stored.Status)
+	assert.Len(t, stored.StatusHistory, 1)
+	assert.Equal(t, now, stored.LastCheckedAt)
+}
+
+func TestScheduler_CheckAndEnqueue_WithoutCreatedAt(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 10, 28, 10, 0, 30, 0, time.UTC)
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := memory.NewInMemoryJobRepository()
+	require.NoError(t, taskRepo.Save(ctx, &domain.Task{ID: "task1", CronExpression: "* * * * *", Status: domain.TaskStatusActive}))
+	scheduler := NewScheduler(taskRepo, jobRepo)
+
+	// Without CreatedAt the check starts from now instead of enumerating run times since year 1
+	require.NoError(t, scheduler.CheckAndEnqueue(ctx, now))
+	jobs, err := jobRepo.FindByTaskID(ctx, "task1", 10)
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+	stored, err := taskRepo.FindByID(ctx, "task1")
+	require.NoError(t, err)
+	assert.Equal(t, now, stored.LastCheckedAt)
+}
+
+func TestScheduler_CheckAndEnqueue_StopsWhenCancelled(t *testing.T) {
+	now := time.Date(2023, 10, 28, 10, 0, 30, 0, time.UTC)
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := memory.NewInMemoryJobRepository()
+	require.NoError(t, taskRepo.Save(context.Background(), &domain.Task{
+		ID:             "task1",
+		CronExpression: "* * * * *",
+		Status:         domain.TaskStatusActive,
+		CreatedAt:      now.Add(-2 * time.Minute),
+	}))
+	scheduler := NewScheduler(taskRepo, jobRepo)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := scheduler.CheckAndEnqueue(ctx, now)
+	require.ErrorIs(t, err, context.Canceled)
+
+	// The task is left for the next check
+	stored, err := taskRepo.FindByID(context.Background(), "task1")
+	require.NoError(t, err)
+	assert.True(t, stored.LastCheckedAt.IsZero())
+}
+
+func TestScheduler_CheckAndEnqueue_MisfirePolicy(t *testing.T) {
+	now := time.Date(2023, 10, 28, 10, 0, 30, 0, time.UTC)
+	// A per-minute task that was last checked a week ago
+	lastChecked := now.Add(-7 * 24 * time.Hour)
+
+	testCases := []struct {
+		name          string
+		policy        domain.MisfirePolicy
+		expectedTimes []time.Time
+	}{
+		{
+			name:          "fire latest enqueues only the current run",
+			policy:        domain.MisfirePolicy{Strategy: domain.MisfireFireLatest},
+			expectedTimes: []time.Time{time.Date(2023, 10, 28, 10, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:          "skip enqueues only the current run",
+			policy:        domain.MisfirePolicy{Strategy: domain.MisfireSkip},
+			expectedTimes: []time.Time{time.Date(2023, 10, 28, 10, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:   "limited enqueues the most recent misfires",
+			policy: domain.MisfirePolicy{Strategy: domain.MisfireFireLimited, MaxRuns: 2},
+			expectedTimes: []time.Time{
+				time.Date(2023, 10, 28, 9, 58, 0, 0, time.UTC),
+				time.Date(2023, 10, 28, 9, 59, 0, 0, time.UTC),
+				time.Date(2023, 10, 28, 10, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			task := &domain.Task{
+				ID:             "task1",
+				CronExpression: "* * * * *",
+				Status:         domain.TaskStatusActive,
+				MisfirePolicy:  tc.policy,
+				CreatedAt:      lastChecked,
+				LastCheckedAt:  lastChecked,
+			}
+			taskRepo := &mockTaskRepository{tasks: map[string]*domain.Task{task.ID: task}}
+			jobRepo := &mockJobRepository{}
+			scheduler := NewScheduler(taskRepo, jobRepo)
+
+			err := scheduler.CheckAndEnqueue(context.Background(), now)
+			assert.NoError(t, err)
+
+			var scheduledTimes []time.Time
+			for _, job := range jobRepo.enqueued {
+				scheduledTimes = append(scheduledTimes, job.ScheduledAt)
+			}
+			assert.Equal(t, tc.expectedTimes, scheduledTimes)
+
+			// Skipped misfires are not checked again
+			saved, err := taskRepo.FindByID(context.Background(), task.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, now, saved.LastCheckedAt)
+		})
+	}
+
+	t.Run("fire all enqueues every missed run", func(t *testing.T) {
+		task := &domain.Task{ID: "task1", CronExpression: "0 * * * *", Status: domain.TaskStatusActive, CreatedAt: lastChecked, LastCheckedAt: lastChecked}
+		taskRepo := &mockTaskRepository{tasks: map[string]*domain.Task{task.ID: task}}
+		jobRepo := &mockJobRepository{}
+		scheduler := NewScheduler(taskRepo, jobRepo)
+
+		err := scheduler.CheckAndEnqueue(context.Background(), now)
+		assert.NoError(t, err)
+		assert.Len(t, jobRepo.enqueued, 7*24)
+	})
+
+	t.Run("misfire threshold is configurable", func(t *testing.T) {
+		task := &domain.Task{ID: "task1", CronExpression: "* * * * *", Status: domain.TaskStatusActive, MisfirePolicy: domain.MisfirePolicy{Strategy: domain.MisfireSkip}, CreatedAt: lastChecked, LastCheckedAt: now.Add(-5 * time.Minute)}
+		taskRepo := &mockTaskRepository{tasks: map[string]*domain.Task{task.ID: task}}
+		jobRepo := &mockJobRepository{}
+		scheduler := NewScheduler(taskRepo, jobRepo, WithMisfireThreshold(10*time.Minute))
+
+		err := scheduler.CheckAndEnqueue(context.Background(), now)
+		assert.NoError(t, err)
+		assert.Len(t, jobRepo.enqueued, 5, "runs within the threshold are not misfires")
+	})
+}
+
+func TestScheduler_CheckAndEnqueue_ConcurrencyPolicy(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 10, 28, 10, 0, 30, 0, time.UTC)
+	previousRun := time.Date(2023, 10, 28, 9, 59, 0, 0, time.UTC)
+	currentRun := time.Date(2023, 10, 28, 10, 0, 0, 0, time.UTC)
+
+	// setup は、前回の実行時刻のジョブが実行中のタスクを用意します。
+	setup := func(t *testing.T, policy domain.ConcurrencyPolicy) (*Scheduler, *memory.InMemoryJobRepository, *domain.Job) {
+		t.Helper()
+		task := &domain.Task{
+			ID:                "task1",
+			CronExpression:    "* * * * *",
+			Status:            domain.TaskStatusActive,
+			ConcurrencyPolicy: policy,
+			CreatedAt:         previousRun.Add(-time.Minute),
+			LastCheckedAt:     previousRun.Add(time.Second),
+		}
+		taskRepo := &mockTaskRepository{tasks: map[string]*domain.Task{task.ID: task}}
+		jobRepo := memory.NewInMemoryJobRepository()
+
+		previous := &domain.Job{ID: domain.NewJobID(task.ID, previousRun), TaskID: task.ID, ScheduledAt: previousRun, Status: domain.JobStatusPending}
+		assert.NoError(t, jobRepo.Enqueue(ctx, previous))
+		_, err := jobRepo.Dequeue(ctx)
+		assert.NoError(t, err)
+		assert.NoError(t, jobRepo.UpdateStatus(ctx, previous.ID, domain.JobStatusRunning))
+
+		return NewScheduler(taskRepo, jobRepo), jobRepo, previous
+	}
+
+	t.Run("allow starts a new run while the previous one is running", func(t *testing.T) {
+		scheduler, jobRepo, previous := setup(t, domain.ConcurrencyAllow)
+
+		assert.NoError(t, scheduler.CheckAndEnqueue(ctx, now))
+
+		active, err := jobRepo.FindActiveByTaskID(ctx, "task1")
+		assert.NoError(t, err)
+		if assert.Len(t, active, 2) {
+			assert.Equal(t, previous.ID, active[0].ID)
+			assert.True(t, currentRun.Equal(active[1].ScheduledAt))
+		}
+	})
+
+	t.Run("forbid skips a new run while the previous one is running", func(t *testing.T) {
+		scheduler, jobRepo, previous := setup(t, domain.ConcurrencyForbid)
+
+		assert.NoError(t, scheduler.CheckAndEnqueue(ctx, now))
+
+		active, err := jobRepo.FindActiveByTaskID(ctx, "task1")
+		assert.NoError(t, err)
+		if assert.Len(t, active, 1) {
+			assert.Equal(t, previous.ID, active[0].ID)
+		}
+		job, err := jobRepo.FindByID(ctx, domain.NewJobID("task1", currentRun))
+		assert.NoError(t, err)
+		assert.Nil(t, job, "the skipped run must not be enqueued later")
+
+		// Once the previous run has finished, the next run is enqueued
+		assert.NoError(t, jobRepo.UpdateStatus(ctx, previous.ID, domain.JobStatusSuccess))
+		assert.NoError(t, scheduler.CheckAndEnqueue(ctx, now.Add(time.Minute)))
+		active, err = jobRepo.FindActiveByTaskID(ctx, "task1")
+		assert.NoError(t, err)
+		if assert.Len(t, active, 1) {
+			assert.True(t, currentRun.Add(time.Minute).Equal(active[0].ScheduledAt))
+		}
+	})
+
+	t.Run("replace cancels the previous run", func(t *testing.T) {
+		scheduler, jobRepo, previous := setup(t, domain.ConcurrencyReplace)
+
+		assert.NoError(t, scheduler.CheckAndEnqueue(ctx, now))
+
+		job, err := jobRepo.FindByID(ctx, previous.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.JobStatusCancelled, job.Status)
+
+		active, err := jobRepo.FindActiveByTaskID(ctx, "task1")
+		assert.NoError(t, err)
+		if assert.Len(t, active, 1) {
+			assert.True(t, currentRun.Equal(active[0].ScheduledAt))
+		}
+
+		// Checking again does not cancel the run that was just enqueued
+		assert.NoError(t, scheduler.CheckAndEnqueue(ctx, now.Add(time.Second)))
+		active, err = jobRepo.FindActiveByTaskID(ctx, "task1")
+		assert.NoError(t, err)
+		assert.Len(t, active, 1)
+	})
+
+	t.Run("only the latest of several due runs is enqueued", func(t *testing.T) {
+		task := &domain.Task{ID: "task1", CronExpression: "* * * * *", Status: domain.TaskStatusActive, ConcurrencyPolicy: domain.ConcurrencyForbid, CreatedAt: now.Add(-5 * time.Minute)}
+		taskRepo := &mockTaskRepository{tasks: map[string]*domain.Task{task.ID: task}}
+		jobRepo := &mockJobRepository{}
+		scheduler := NewScheduler(taskRepo, jobRepo)
+
+		assert.NoError(t, scheduler.CheckAndEnqueue(ctx, now))
+		if assert.Len(t, jobRepo.enqueued, 1) {
+			assert.Equal(t, currentRun, jobRepo.enqueued[0].ScheduledAt)
+		}
+	})
+}
+
+func TestScheduler_CheckAndEnqueue_Observer(t *testing.T) {
+	ctx := context.Background()
+	observer := &recordingObserver{}
+
+	taskRepo := &mockTaskRepository{tasks: map[string]*domain.Task{}}
+	scheduler := NewScheduler(taskRepo, &mockJobRepository{}, WithSchedulerObserver(observer))
+	assert.NoError(t, scheduler.CheckAndEnqueue(ctx, time.Now()))
+
+	taskRepo.findAllActiveErr = errors.New("connection refused")
+	assert.Error(t, scheduler.CheckAndEnqueue(ctx, time.Now()))
+
+	if assert.Len(t, observer.checks, 2) {
+		assert.NoError(t, observer.checks[0])
+		assert.Error(t, observer.checks[1])
+	}
+}
+
+func TestScheduler_CheckAndEnqueue_Logging(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 10, 0, 30, 0, time.UTC)
+	task := &domain.Task{
+		ID:             "task-1",
+		CronExpression: "* * * * *",
+		Status:         domain.TaskStatusActive,
+		LastCheckedAt:  now.Add(-time.Minute),
+	}
+
+	var buf bytes.Buffer
+	taskRepo := &mockTaskRepository{tasks: map[string]*domain.Task{task.ID: task}}
+	scheduler := NewScheduler(taskRepo, &mockJobRepository{}, WithSchedulerLogger(newTestLogger(&buf)))
+	require.NoError(t, scheduler.CheckAndEnqueue(ctx, now))
+
+	lines := parseLogLines(t, &buf)
+	require.Len(t, lines, 1)
+	assert.Equal(t, "enqueued job", lines[0]["msg"])
+	assert.Equal(t, task.ID, lines[0]["task_id"])
+	assert.Equal(t, domain.NewJobID(task.ID, now.Truncate(time.Minute)), lines[0]["job_id"])
+	assert.Equal(t, "2026-01-01T10:00:00Z", lines[0]["scheduled_at"])
+	assert.Equal(t, float64(1), lines[0]["attempt"])
+}
@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+)
+
+// maxModifyAttempts は、ModifyTask が保存の競合時にタスクを読み直して変更をやり直す最大回数です。
+const maxModifyAttempts = 3
+
+// ModifyTask は、タスクを読み込んで modify で変更し、保存したタスクを返します。
+// modify が false を返した場合は保存せずに読み込んだタスクを返し、エラーを返した場合はそのエラーを返します。
+// 読み込んでから保存するまでに他の更新が保存された場合（domain.ErrConflict）は、タスクを読み直して
+// 最大 maxModifyAttempts 回まで変更をやり直します。それでも競合する場合は domain.ErrConflict をラップしたエラーを返します。
+// タスクが存在しない場合は domain.ErrNotFound をラップしたエラーを返します。
+func ModifyTask(ctx context.Context, taskRepo domain.TaskRepository, taskID string, modify func(task *domain.Task) (bool, error)) (*domain.Task, error) {
+	var saveErr error
+	for range maxModifyAttempts {
+		task, err := taskRepo.FindByID(ctx, taskID)
+		if err != nil {
+			return nil, err
+		}
+		if task == nil {
+			return nil, fmt.Errorf("%w: task %s", domain.ErrNotFound, taskID)
+		}
+
+		changed, err := modify(task)
+		if err != nil {
+			return nil, err
+		}
+		if !changed {
+			return task, nil
+		}
+
+		saveErr = taskRepo.Save(ctx, task)
+		if !errors.Is(saveErr, domain.ErrConflict) {
+			if saveErr != nil {
+				return nil, saveErr
+			}
+			return task, nil
+		}
+	}
+	return nil, saveErr
+}
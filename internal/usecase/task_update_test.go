@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/memory"
+)
+
+// racingTaskRepository は、FindByID でタスクを返した直後に別の更新を保存して、保存の競合を再現する TaskRepository です。
+type racingTaskRepository struct {
+	domain.TaskRepository
+	races int
+}
+
+func (r *racingTaskRepository) FindByID(ctx context.Context, id string) (*domain.Task, error) {
+	task, err := r.TaskRepository.FindByID(ctx, id)
+	if err != nil || task == nil || r.races == 0 {
+		return task, err
+	}
+	r.races--
+
+	other, err := r.TaskRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	other.Name += "!"
+	if err := r.TaskRepository.Save(ctx, other); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func TestModifyTask_RetriesOnConflict(t *testing.T) {
+	ctx := context.Background()
+	base := memory.NewInMemoryTaskRepository()
+	require.NoError(t, base.Save(ctx, &domain.Task{ID: "task1", Name: "task", Status: domain.TaskStatusActive}))
+	taskRepo := &racingTaskRepository{TaskRepository: base, races: maxModifyAttempts - 1}
+
+	pausedAt := time.Date(2024, time.January, 1, 10, 0, 0, 0, time.UTC)
+	paused, err := NewPauser(taskRepo).PauseTask(ctx, "task1", "alice", "incident", pausedAt)
+	require.NoError(t, err)
+	assert.Equal(t, domain.TaskStatusPaused, paused.Status)
+
+	// Both the concurrent updates and the pause are kept
+	stored, err := base.FindByID(ctx, "task1")
+	require.NoError(t, err)
+	assert.Equal(t, "task!!", stored.Name)
+	assert.Equal(t, domain.TaskStatusPaused, stored.Status)
+	assert.Len(t, stored.StatusHistory, 1)
+}
+
+func TestModifyTask_GivesUpAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	base := memory.NewInMemoryTaskRepository()
+	require.NoError(t, base.Save(ctx, &domain.Task{ID: "task1", Name: "task", Status: domain.TaskStatusActive}))
+	taskRepo := &racingTaskRepository{TaskRepository: base, races: maxModifyAttempts}
+
+	_, err := ModifyTask(ctx, taskRepo, "task1", func(task *domain.Task) (bool, error) {
+		task.Status = domain.TaskStatusPaused
+		return true, nil
+	})
+	assert.ErrorIs(t, err, domain.ErrConflict)
+
+	stored, err := base.FindByID(ctx, "task1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.TaskStatusActive, stored.Status)
+
+	_, err = ModifyTask(ctx, taskRepo, "nonexistent", func(task *domain.Task) (bool, error) {
+		return true, nil
+	})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName は、このパッケージが記録するスパンの計装スコープ名です。
+const tracerName = "github.com/yourname/go-dist-scheduler/internal/usecase"
+
+// traceContextPropagator は、ジョブの TraceContext と送信するHTTPリクエストのヘッダーに、
+// W3C Trace Context 形式（traceparent・tracestate）でトレースコンテキストを読み書きします。
+var traceContextPropagator = propagation.TraceContext{}
+
+// startJobTrace は、ジョブ1つにつき1つのトレースのルートスパンを開始し、そのトレースコンテキストをジョブに保存します。
+// ctx にスパンが含まれている場合（APIリクエストなど）は、新しいトレースからそのスパンへリンクします。
+func startJobTrace(ctx context.Context, tracer trace.Tracer, name string, job *domain.Job) (context.Context, trace.Span) {
+	opts := []trace.SpanStartOption{
+		trace.WithNewRoot(),
+		trace.WithAttributes(jobAttributes(job)...),
+	}
+	if parent := trace.SpanContextFromContext(ctx); parent.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: parent}))
+	}
+	ctx, span := tracer.Start(ctx, name, opts...)
+
+	carrier := propagation.MapCarrier{}
+	traceContextPropagator.Inject(ctx, carrier)
+	if len(carrier) > 0 {
+		job.TraceContext = carrier
+	}
+	return ctx, span
+}
+
+// jobTraceContext は、ジョブに保存されたトレースコンテキストを ctx に設定します。
+// 以降に開始したスパンは、ジョブをスケジュールしたトレースに属します。
+func jobTraceContext(ctx context.Context, job *domain.Job) context.Context {
+	if len(job.TraceContext) == 0 {
+		return ctx
+	}
+	return traceContextPropagator.Extract(ctx, propagation.MapCarrier(job.TraceContext))
+}
+
+// enqueueTraced は、ジョブのエンキューをスパンとして記録します。
+func enqueueTraced(ctx context.Context, tracer trace.Tracer, jobRepo domain.JobRepository, job *domain.Job) error {
+	ctx, span := tracer.Start(ctx, "enqueue job", trace.WithAttributes(jobAttributes(job)...))
+	defer span.End()
+
+	err := jobRepo.Enqueue(ctx, job)
+	recordSpanError(span, err)
+	return err
+}
+
+// jobAttributes は、ジョブを識別するスパンの属性を返します。
+func jobAttributes(job *domain.Job) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("scheduler.job_id", job.ID),
+		attribute.String("scheduler.task_id", job.TaskID),
+		attribute.String("scheduler.scheduled_at", job.ScheduledAt.UTC().Format(time.RFC3339Nano)),
+		attribute.Int("scheduler.attempt", job.Attempt()),
+	}
+}
+
+// recordSpanError は、err が nil でなければスパンにエラーとして記録します。
+func recordSpanError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
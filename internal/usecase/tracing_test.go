@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/memory"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newTestTracerProvider は、終了したスパンをメモリに記録する TracerProvider を返します。
+func newTestTracerProvider(t *testing.T) (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+	})
+	return tp, exporter
+}
+
+// spansByName は、記録されたスパンを名前で引けるようにします。同じ名前のスパンは最後のものを返します。
+func spansByName(spans tracetest.SpanStubs) map[string]tracetest.SpanStub {
+	m := make(map[string]tracetest.SpanStub, len(spans))
+	for _, span := range spans {
+		m[span.Name] = span
+	}
+	return m
+}
+
+func TestTracing_OneTracePerJob(t *testing.T) {
+	ctx := context.Background()
+	tp, exporter := newTestTracerProvider(t)
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := memory.NewInMemoryJobRepository()
+	task := setupTask(t, taskRepo, server)
+	// Exactly one run time (10:00) is due at now
+	now := time.Date(2024, time.January, 1, 10, 0, 30, 0, time.UTC)
+	task.CreatedAt = now.Add(-time.Minute)
+	require.NoError(t, taskRepo.Save(ctx, task))
+
+	scheduler := NewScheduler(taskRepo, jobRepo, WithSchedulerTracerProvider(tp))
+	executor := NewExecutor(taskRepo, jobRepo, server.Client(), WithExecutorTracerProvider(tp))
+
+	require.NoError(t, scheduler.CheckAndEnqueue(ctx, now))
+	jobs, err := jobRepo.FindByTaskID(ctx, task.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.NotEmpty(t, jobs[0].TraceContext["traceparent"], "trace context must be stored with the job")
+
+	ran, err := executor.RunNext(ctx)
+	require.NoError(t, err)
+	require.True(t, ran)
+
+	spans := spansByName(exporter.GetSpans())
+	require.Contains(t, spans, "schedule job")
+	require.Contains(t, spans, "enqueue job")
+	require.Contains(t, spans, "dequeue job")
+	require.Contains(t, spans, "execute job")
+	require.Contains(t, spans, "HTTP request")
+
+	root := spans["schedule job"]
+	assert.False(t, root.Parent.IsValid(), "each job starts its own trace")
+	for _, name := range []string{"enqueue job", "dequeue job", "execute job", "HTTP request"} {
+		assert.Equal(t, root.SpanContext.TraceID(), spans[name].SpanContext.TraceID(), "%s must belong to the job's trace", name)
+	}
+	assert.Equal(t, root.SpanContext.SpanID(), spans["enqueue job"].Parent.SpanID())
+	assert.Equal(t, root.SpanContext.SpanID(), spans["dequeue job"].Parent.SpanID())
+	assert.Equal(t, root.SpanContext.SpanID(), spans["execute job"].Parent.SpanID())
+	assert.Equal(t, spans["execute job"].SpanContext.SpanID(), spans["HTTP request"].Parent.SpanID())
+	assert.Equal(t, trace.SpanKindClient, spans["HTTP request"].SpanKind)
+
+	// The downstream service joins the trace as a child of the HTTP request span
+	httpSpan := spans["HTTP request"].SpanContext
+	assert.Equal(t, "00-"+httpSpan.TraceID().String()+"-"+httpSpan.SpanID().String()+"-01", traceparent)
+}
+
+func TestTracing_TraceContextSurvivesAcrossProcesses(t *testing.T) {
+	ctx := context.Background()
+	schedulerTP, _ := newTestTracerProvider(t)
+	executorTP, executorSpans := newTestTracerProvider(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := memory.NewInMemoryJobRepository()
+	task := setupTask(t, taskRepo, server)
+
+	// The job is triggered in one process and executed with a separate tracer provider in another
+	job, err := NewTrigger(taskRepo, jobRepo, WithTriggerTracerProvider(schedulerTP)).RunNow(ctx, task.ID, time.Now())
+	require.NoError(t, err)
+	_, err = NewExecutor(taskRepo, jobRepo, server.Client(), WithExecutorTracerProvider(executorTP)).RunNext(ctx)
+	require.NoError(t, err)
+
+	spans := spansByName(executorSpans.GetSpans())
+	require.Contains(t, spans, "execute job")
+	parent := spans["execute job"].Parent
+	assert.True(t, parent.IsRemote())
+	assert.Equal(t, job.TraceContext["traceparent"], "00-"+parent.TraceID().String()+"-"+parent.SpanID().String()+"-01")
+}
+
+func TestTracing_RecordsFailedAttempt(t *testing.T) {
+	ctx := context.Background()
+	tp, exporter := newTestTracerProvider(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := memory.NewInMemoryJobRepository()
+	task := setupTask(t, taskRepo, server)
+	enqueuePendingJob(t, jobRepo, task.ID)
+
+	require.NoError(t, NewExecutor(taskRepo, jobRepo, server.Client(), WithExecutorTracerProvider(tp)).RunPendingJob(ctx))
+
+	spans := spansByName(exporter.GetSpans())
+	require.Contains(t, spans, "HTTP request")
+	assert.Equal(t, "Error", spans["HTTP request"].Status.Code.String())
+	assert.Equal(t, "Error", spans["execute job"].Status.Code.String())
+	assert.Contains(t, spans["HTTP request"].Attributes, attribute.Int("http.response.status_code", http.StatusInternalServerError))
+
+	job, err := jobRepo.FindByTaskID(ctx, task.ID, 1)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusFailed, job[0].Status)
+}
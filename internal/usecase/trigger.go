@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Trigger は、タスクをスケジュール外で即時実行するユースケースを担当します。
+type Trigger struct {
+	taskRepo domain.TaskRepository
+	jobRepo  domain.JobRepository
+	tracer   trace.Tracer
+}
+
+// TriggerOption は、Trigger の設定を変更するオプションです。
+type TriggerOption func(*Trigger)
+
+// WithTriggerTracerProvider は、ジョブのトレースを記録する TracerProvider を設定します。
+func WithTriggerTracerProvider(tp trace.TracerProvider) TriggerOption {
+	return func(t *Trigger) {
+		t.tracer = tp.Tracer(tracerName)
+	}
+}
+
+// NewTrigger は新しいTriggerインスタンスを生成します。
+// トレースはデフォルトでグローバルな TracerProvider（otel.GetTracerProvider）に記録します。
+func NewTrigger(taskRepo domain.TaskRepository, jobRepo domain.JobRepository, opts ...TriggerOption) *Trigger {
+	t := &Trigger{
+		taskRepo: taskRepo,
+		jobRepo:  jobRepo,
+		tracer:   otel.GetTracerProvider().Tracer(tracerName),
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// RunNow は、タスクのジョブを now をスケジュール時刻として作成し、キューに追加します。
+// 作成したジョブの TriggerSource は domain.TriggerSourceManual になります。
+// タスクの LastCheckedAt は変更しないため、cron式による通常のスケジュールには影響しません。
+// 動作確認や障害からの復旧に使えるよう、一時停止中のタスクも実行でき、ConcurrencyPolicy も適用しません。
+// タスクが存在しない場合は domain.ErrNotFound をラップしたエラーを、同じタスクの同じスケジュール時刻の
+// ジョブが既に存在する場合は domain.ErrConstraintViolation を返します。
+func (t *Trigger) RunNow(ctx context.Context, taskID string, now time.Time) (*domain.Job, error) {
+	task, err := t.taskRepo.FindByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, fmt.Errorf("%w: task %s", domain.ErrNotFound, taskID)
+	}
+
+	job := &domain.Job{
+		ID:            uuid.NewString(),
+		TaskID:        task.ID,
+		ScheduledAt:   now,
+		Status:        domain.JobStatusPending,
+		TriggerSource: domain.TriggerSourceManual,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	ctx, span := startJobTrace(ctx, t.tracer, "trigger job", job)
+	defer span.End()
+	if err := enqueueTraced(ctx, t.tracer, t.jobRepo, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
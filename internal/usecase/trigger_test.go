@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/memory"
+)
+
+func TestTrigger_RunNow(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 10, 28, 10, 0, 30, 0, time.UTC)
+	lastChecked := time.Date(2023, 10, 28, 10, 0, 5, 0, time.UTC)
+
+	task := &domain.Task{
+		ID:             "task1",
+		CronExpression: "* * * * *",
+		Status:         domain.TaskStatusActive,
+		CreatedAt:      now.Add(-time.Hour),
+		LastCheckedAt:  lastChecked,
+	}
+	taskRepo := &mockTaskRepository{tasks: map[string]*domain.Task{task.ID: task}}
+	jobRepo := memory.NewInMemoryJobRepository()
+	trigger := NewTrigger(taskRepo, jobRepo)
+
+	job, err := trigger.RunNow(ctx, task.ID, now)
+	require.NoError(t, err)
+	assert.Equal(t, task.ID, job.TaskID)
+	assert.Equal(t, now, job.ScheduledAt)
+	assert.Equal(t, domain.JobStatusPending, job.Status)
+	assert.Equal(t, domain.TriggerSourceManual, job.TriggerSource)
+
+	dequeued, err := jobRepo.Dequeue(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, dequeued)
+	assert.Equal(t, job.ID, dequeued.ID)
+	assert.Equal(t, domain.TriggerSourceManual, dequeued.TriggerSource)
+
+	// A task runs at most once per scheduled time, even when triggered manually
+	_, err = trigger.RunNow(ctx, task.ID, now)
+	assert.ErrorIs(t, err, domain.ErrConstraintViolation)
+	again, err := trigger.RunNow(ctx, task.ID, now.Add(time.Millisecond))
+	require.NoError(t, err)
+	assert.NotEqual(t, job.ID, again.ID)
+
+	// The regular schedule is not disturbed
+	assert.Equal(t, lastChecked, task.LastCheckedAt)
+	scheduler := NewScheduler(taskRepo, jobRepo)
+	require.NoError(t, scheduler.CheckAndEnqueue(ctx, now.Add(time.Minute)))
+	scheduled, err := jobRepo.FindByID(ctx, domain.NewJobID(task.ID, time.Date(2023, 10, 28, 10, 1, 0, 0, time.UTC)))
+	require.NoError(t, err)
+	require.NotNil(t, scheduled, "the next scheduled run must still be enqueued")
+	assert.Equal(t, domain.TriggerSourceCron, scheduled.TriggerSource)
+}
+
+func TestTrigger_RunNow_PausedTask(t *testing.T) {
+	ctx := context.Background()
+	task := &domain.Task{ID: "task1", CronExpression: "* * * * *", Status: domain.TaskStatusPaused}
+	taskRepo := &mockTaskRepository{tasks: map[string]*domain.Task{task.ID: task}}
+	jobRepo := &mockJobRepository{}
+
+	_, err := NewTrigger(taskRepo, jobRepo).RunNow(ctx, task.ID, time.Now())
+	require.NoError(t, err)
+	assert.Len(t, jobRepo.enqueued, 1)
+	assert.Equal(t, domain.TaskStatusPaused, task.Status)
+}
+
+func TestTrigger_RunNow_TaskNotFound(t *testing.T) {
+	taskRepo := &mockTaskRepository{tasks: map[string]*domain.Task{}}
+	jobRepo := &mockJobRepository{}
+
+	_, err := NewTrigger(taskRepo, jobRepo).RunNow(context.Background(), "nonexistent", time.Now())
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.Empty(t, jobRepo.enqueued)
+}
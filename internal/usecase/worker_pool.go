@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+)
+
+// WorkerPool は、複数のgoroutineで並行してジョブを実行するワーカープールです。
+// 各ワーカーはキューが空になるまでジョブを取り出し続け、空になると pollInterval だけ待機してから再度取り出します。
+type WorkerPool struct {
+	executor     *Executor
+	workers      int
+	pollInterval time.Duration
+	// interrupted は、Interrupt が呼び出されるとキャンセルされ、実行中のジョブを中断させます。
+	interrupted context.Context
+	interrupt   context.CancelCauseFunc
+	logger      *slog.Logger
+}
+
+// WorkerPoolOption は、WorkerPool の設定を変更するオプションです。
+type WorkerPoolOption func(*WorkerPool)
+
+// WithWorkerPoolLogger は、WorkerPool がログを出力するロガーを設定します。
+func WithWorkerPoolLogger(logger *slog.Logger) WorkerPoolOption {
+	return func(p *WorkerPool) {
+		p.logger = logger
+	}
+}
+
+// NewWorkerPool は新しいWorkerPoolインスタンスを生成します。
+// workers が1未満の場合は1として扱います。ログはデフォルトで slog.Default() に出力します。
+func NewWorkerPool(executor *Executor, workers int, pollInterval time.Duration, opts ...WorkerPoolOption) *WorkerPool {
+	if workers < 1 {
+		workers = 1
+	}
+	interrupted, interrupt := context.WithCancelCause(context.Background())
+	p := &WorkerPool{
+		executor:     executor,
+		workers:      workers,
+		pollInterval: pollInterval,
+		interrupted:  interrupted,
+		interrupt:    interrupt,
+		logger:       slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Size は、ワーカーの数を返します。
+func (p *WorkerPool) Size() int {
+	return p.workers
+}
+
+// Busy は、ジョブを実行中のワーカーの数を返します。
+func (p *WorkerPool) Busy() int {
+	return p.executor.Running()
+}
+
+// Run は、ctx がキャンセルされるまでワーカーを実行します。
+// ctx がキャンセルされると新しいジョブの取り出しを停止し、実行中のジョブがすべて完了してから戻ります。
+func (p *WorkerPool) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < p.workers; i++ {
+		wg.Add(1)
+		go func(workerID int) {
+			defer wg.Done()
+			p.work(ctx, workerID)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// Interrupt は、実行中のすべてのジョブを中断し、リトライ回数を加算せずに Pending へ戻させます。
+// シャットダウンの猶予期間内にジョブが終わらなかった場合に、Run の ctx をキャンセルした後で呼び出します。
+// 呼び出し後に取り出されたジョブも直ちに中断されます。
+func (p *WorkerPool) Interrupt() {
+	p.interrupt(domain.ErrJobInterrupted)
+}
+
+// work は、1つのワーカーのメインループです。
+func (p *WorkerPool) work(ctx context.Context, workerID int) {
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		processed, err := p.runNext(ctx, workerID)
+		if err != nil {
+			p.logger.Error("error in RunNext", slog.Int("worker", workerID), slog.Any("error", err))
+		}
+		if processed && err == nil {
+			// キューが空になるまで続けて取り出す
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(p.pollInterval):
+		}
+	}
+}
+
+// runNext は、1つのジョブを実行します。ジョブの実行中にpanicが発生した場合は回復し、エラーとして返します。
+// シャットダウン時にも実行中のジョブを最後まで完了させるため、ジョブは ctx のキャンセルを引き継がず、
+// Interrupt が呼び出されたときにのみキャンセルされるコンテキストで実行します。
+func (p *WorkerPool) runNext(ctx context.Context, workerID int) (processed bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			p.logger.Error("recovered from panic while running job",
+				slog.Int("worker", workerID), slog.Any("panic", r), slog.String("stack", string(debug.Stack())))
+			processed = true
+			err = fmt.Errorf("panic while running job: %v", r)
+		}
+	}()
+
+	jobCtx, cancel := context.WithCancelCause(context.WithoutCancel(ctx))
+	defer cancel(nil)
+	stop := context.AfterFunc(p.interrupted, func() {
+		cancel(context.Cause(p.interrupted))
+	})
+	defer stop()
+
+	return p.executor.RunNext(jobCtx)
+}
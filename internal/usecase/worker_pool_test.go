@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yourname/go-dist-scheduler/internal/domain"
+	"github.com/yourname/go-dist-scheduler/internal/infrastructure/memory"
+)
+
+// runWorkerPool は、WorkerPool をバックグラウンドで実行し、停止用の関数を返します。
+// 停止用の関数は、Run が戻るまでブロックします。
+func runWorkerPool(pool *WorkerPool) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		pool.Run(ctx)
+		close(done)
+	}()
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+func TestWorkerPool_ProcessesAllJobsConcurrently(t *testing.T) {
+	var (
+		requests    atomic.Int32
+		inFlight    atomic.Int32
+		maxInFlight atomic.Int32
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			highest := maxInFlight.Load()
+			if current <= highest || maxInFlight.CompareAndSwap(highest, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		requests.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := memory.NewInMemoryJobRepository()
+	task := setupTask(t, taskRepo, server)
+
+	const numJobs = 12
+	for i := 0; i < numJobs; i++ {
+		enqueuePendingJob(t, jobRepo, task.ID)
+	}
+
+	pool := NewWorkerPool(NewExecutor(taskRepo, jobRepo, server.Client()), 4, time.Hour)
+	stop := runWorkerPool(pool)
+	defer stop()
+
+	// The poll interval is long, so all jobs must be drained without waiting for it
+	assert.Eventually(t, func() bool { return requests.Load() == numJobs }, 2*time.Second, 5*time.Millisecond)
+	assert.Greater(t, maxInFlight.Load(), int32(1), "jobs should be executed concurrently")
+}
+
+// panicOnceHTTPClient は、最初の呼び出しでのみpanicする HTTPClient です。
+type panicOnceHTTPClient struct {
+	client   HTTPClient
+	panicked atomic.Bool
+}
+
+func (c *panicOnceHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if c.panicked.CompareAndSwap(false, true) {
+		panic("unexpected panic")
+	}
+	return c.client.Do(req)
+}
+
+func TestWorkerPool_RecoversFromPanic(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := memory.NewInMemoryJobRepository()
+	task := setupTask(t, taskRepo, server)
+	enqueuePendingJob(t, jobRepo, task.ID)
+	enqueuePendingJob(t, jobRepo, task.ID)
+
+	client := &panicOnceHTTPClient{client: server.Client()}
+	pool := NewWorkerPool(NewExecutor(taskRepo, jobRepo, client), 1, 5*time.Millisecond)
+	stop := runWorkerPool(pool)
+	defer stop()
+
+	// The single worker survives the panic and processes the next job
+	assert.Eventually(t, func() bool { return requests.Load() == 1 }, time.Second, 5*time.Millisecond)
+	assert.True(t, client.panicked.Load())
+}
+
+// statusRecordingJobRepository は、ジョブの最終ステータスを記録する goroutine-safe な JobRepository です。
+type statusRecordingJobRepository struct {
+	*memory.InMemoryJobRepository
+	finished chan domain.JobStatus
+}
+
+func (r *statusRecordingJobRepository) UpdateStatus(ctx context.Context, jobID string, status domain.JobStatus) error {
+	if status == domain.JobStatusSuccess || status == domain.JobStatusFailed {
+		r.finished <- status
+	}
+	return r.InMemoryJobRepository.UpdateStatus(ctx, jobID, status)
+}
+
+func TestWorkerPool_StopWaitsForInFlightJobs(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	taskRepo := memory.NewInMemoryTaskRepository()
+	jobRepo := &statusRecordingJobRepository{
+		InMemoryJobRepository: memory.NewInMemoryJobRepository(),
+		finished:              make(chan domain.JobStatus, 1),
+	}
+	task := setupTask(t, taskRepo, server)
+	enqueuePendingJob(t, jobRepo, task.ID)
+
+	pool := NewWorkerPool(NewExecutor(taskRepo, jobRepo, server.Client()), 2, 5*time.Millisecond)
+	stop := runWorkerPool(pool)
+	<-started
+
+	stopped := make(chan struct{})
+	go func() {
+		stop()
+		close(stopped)
+	}()
+
+	// Run must not return while the job is still in flight
+	select {
+	case <-stopped:
+		t.Fatal("worker pool stopped before the in-flight job finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	<-stopped
+	assert.Equal(t, domain.JobStatusSuccess, <-jobRepo.finished, "the in-flight job should complete successfully")
+}
@@ -0,0 +1,156 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultTolerance is how far the signing time of a request may be from the
+// current time before the request is rejected as a possible replay.
+const DefaultTolerance = 5 * time.Minute
+
+// DefaultMaxBodySize is the largest request body Middleware reads.
+const DefaultMaxBodySize = 1 << 20
+
+var (
+	// ErrMissingSignature is returned when the signature or timestamp header is absent.
+	ErrMissingSignature = errors.New("webhook: missing signature")
+	// ErrInvalidTimestamp is returned when the timestamp header is not a Unix time.
+	ErrInvalidTimestamp = errors.New("webhook: invalid timestamp")
+	// ErrTimestampOutOfTolerance is returned when the request was signed too long ago or in the future.
+	ErrTimestampOutOfTolerance = errors.New("webhook: timestamp out of tolerance")
+	// ErrInvalidSignature is returned when no signature matches any of the secrets.
+	ErrInvalidSignature = errors.New("webhook: invalid signature")
+)
+
+// Verifier verifies the signatures of requests sent by the scheduler.
+// It is safe for concurrent use.
+type Verifier struct {
+	secrets         [][]byte
+	signatureHeader string
+	timestampHeader string
+	tolerance       time.Duration
+	maxBodySize     int64
+}
+
+// VerifierOption configures a Verifier.
+type VerifierOption func(*Verifier)
+
+// WithSignatureHeader sets the header that carries the signatures.
+// It must match the signature header configured for the task.
+func WithSignatureHeader(name string) VerifierOption {
+	return func(v *Verifier) {
+		v.signatureHeader = name
+	}
+}
+
+// WithTimestampHeader sets the header that carries the signing time.
+// It must match the timestamp header configured for the task.
+func WithTimestampHeader(name string) VerifierOption {
+	return func(v *Verifier) {
+		v.timestampHeader = name
+	}
+}
+
+// WithTolerance sets how far the signing time may be from the current time.
+// A zero tolerance disables the check.
+func WithTolerance(d time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		v.tolerance = d
+	}
+}
+
+// WithMaxBodySize sets the largest request body Middleware reads.
+func WithMaxBodySize(n int64) VerifierOption {
+	return func(v *Verifier) {
+		v.maxBodySize = n
+	}
+}
+
+// NewVerifier creates a Verifier that accepts requests signed with any of secrets.
+// Pass both the old and the new secret while rotating.
+func NewVerifier(secrets []string, opts ...VerifierOption) *Verifier {
+	v := &Verifier{
+		signatureHeader: DefaultSignatureHeader,
+		timestampHeader: DefaultTimestampHeader,
+		tolerance:       DefaultTolerance,
+		maxBodySize:     DefaultMaxBodySize,
+	}
+	for _, secret := range secrets {
+		v.secrets = append(v.secrets, []byte(secret))
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Verify checks that header carries a valid signature of body made at most
+// the tolerance away from now.
+func (v *Verifier) Verify(header http.Header, body []byte, now time.Time) error {
+	timestamp := header.Get(v.timestampHeader)
+	signatures := header.Get(v.signatureHeader)
+	if timestamp == "" || signatures == "" {
+		return ErrMissingSignature
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+	if v.tolerance > 0 {
+		skew := now.Sub(time.Unix(unix, 0))
+		if skew > v.tolerance || skew < -v.tolerance {
+			return ErrTimestampOutOfTolerance
+		}
+	}
+
+	for _, secret := range v.secrets {
+		expected := mac(secret, timestamp, body)
+		for _, entry := range strings.Split(signatures, ",") {
+			scheme, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+			if !ok || scheme != signatureScheme {
+				continue
+			}
+			signature, err := hex.DecodeString(value)
+			if err != nil {
+				continue
+			}
+			if hmac.Equal(signature, expected) {
+				return nil
+			}
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// Middleware returns a handler that verifies each request before passing it
+// to next, and responds with 401 Unauthorized when verification fails.
+// The request body is restored so that next can read it.
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, v.maxBodySize))
+		if err != nil {
+			status := http.StatusBadRequest
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			http.Error(w, "failed to read request body", status)
+			return
+		}
+		if err := v.Verify(r.Header, body, time.Now()); err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,131 @@
+package webhook_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourname/go-dist-scheduler/pkg/webhook"
+)
+
+// signedHeader returns the headers of a request signed with secrets at timestamp.
+func signedHeader(secrets []string, timestamp time.Time, body []byte) http.Header {
+	header := http.Header{}
+	header.Set(webhook.DefaultTimestampHeader, webhook.TimestampHeaderValue(timestamp))
+	header.Set(webhook.DefaultSignatureHeader, webhook.SignatureHeaderValue(secrets, timestamp, body))
+	return header
+}
+
+func TestSign(t *testing.T) {
+	timestamp := time.Unix(1767261600, 0)
+
+	// HMAC-SHA256("secret", "1767261600.{}")
+	assert.Equal(t, "e6c7a383f71f6b6c3ba1b928f64a1dcacdd4e4018e779bf8e3f762439b54193b", webhook.Sign("secret", timestamp, []byte("{}")))
+	assert.NotEqual(t, webhook.Sign("secret", timestamp, []byte("{}")), webhook.Sign("other", timestamp, []byte("{}")))
+	assert.NotEqual(t, webhook.Sign("secret", timestamp, []byte("{}")), webhook.Sign("secret", timestamp.Add(time.Second), []byte("{}")))
+
+	value := webhook.SignatureHeaderValue([]string{"old", "new"}, timestamp, []byte("{}"))
+	assert.Equal(t, "v1="+webhook.Sign("old", timestamp, []byte("{}"))+",v1="+webhook.Sign("new", timestamp, []byte("{}")), value)
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	now := time.Unix(1767261600, 0)
+	body := []byte(`{"message":"hello"}`)
+	verifier := webhook.NewVerifier([]string{"secret"})
+
+	assert.NoError(t, verifier.Verify(signedHeader([]string{"secret"}, now, body), body, now))
+	// Clock skew within the tolerance is accepted
+	assert.NoError(t, verifier.Verify(signedHeader([]string{"secret"}, now.Add(-4*time.Minute), body), body, now))
+
+	tests := []struct {
+		name   string
+		header http.Header
+		body   []byte
+		want   error
+	}{
+		{"missing headers", http.Header{}, body, webhook.ErrMissingSignature},
+		{"tampered body", signedHeader([]string{"secret"}, now, body), []byte(`{"message":"bye"}`), webhook.ErrInvalidSignature},
+		{"unknown secret", signedHeader([]string{"other"}, now, body), body, webhook.ErrInvalidSignature},
+		{"too old", signedHeader([]string{"secret"}, now.Add(-6*time.Minute), body), body, webhook.ErrTimestampOutOfTolerance},
+		{"in the future", signedHeader([]string{"secret"}, now.Add(6*time.Minute), body), body, webhook.ErrTimestampOutOfTolerance},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, verifier.Verify(tt.header, tt.body, now), tt.want)
+		})
+	}
+
+	t.Run("invalid timestamp", func(t *testing.T) {
+		header := signedHeader([]string{"secret"}, now, body)
+		header.Set(webhook.DefaultTimestampHeader, "yesterday")
+		assert.ErrorIs(t, verifier.Verify(header, body, now), webhook.ErrInvalidTimestamp)
+	})
+
+	t.Run("replayed with a new timestamp", func(t *testing.T) {
+		header := signedHeader([]string{"secret"}, now.Add(-time.Hour), body)
+		header.Set(webhook.DefaultTimestampHeader, webhook.TimestampHeaderValue(now))
+		assert.ErrorIs(t, verifier.Verify(header, body, now), webhook.ErrInvalidSignature)
+	})
+}
+
+func TestVerifier_Verify_Rotation(t *testing.T) {
+	now := time.Unix(1767261600, 0)
+	body := []byte("{}")
+
+	// The scheduler signs with both secrets while rotating, so receivers that know either one accept the request
+	header := signedHeader([]string{"old", "new"}, now, body)
+	assert.NoError(t, webhook.NewVerifier([]string{"old"}).Verify(header, body, now))
+	assert.NoError(t, webhook.NewVerifier([]string{"new"}).Verify(header, body, now))
+
+	// A receiver that already knows the new secret keeps accepting requests signed with the old one
+	assert.NoError(t, webhook.NewVerifier([]string{"new", "old"}).Verify(signedHeader([]string{"old"}, now, body), body, now))
+}
+
+func TestVerifier_Options(t *testing.T) {
+	now := time.Unix(1767261600, 0)
+	body := []byte("{}")
+	verifier := webhook.NewVerifier([]string{"secret"},
+		webhook.WithSignatureHeader("X-Signature"),
+		webhook.WithTimestampHeader("X-Timestamp"),
+		webhook.WithTolerance(0),
+	)
+
+	header := http.Header{}
+	signedAt := now.Add(-24 * time.Hour)
+	header.Set("X-Timestamp", webhook.TimestampHeaderValue(signedAt))
+	header.Set("X-Signature", webhook.SignatureHeaderValue([]string{"secret"}, signedAt, body))
+	assert.NoError(t, verifier.Verify(header, body, now))
+	assert.ErrorIs(t, verifier.Verify(signedHeader([]string{"secret"}, now, body), body, now), webhook.ErrMissingSignature)
+}
+
+func TestVerifier_Middleware(t *testing.T) {
+	body := `{"message":"hello"}`
+	var received string
+	handler := webhook.NewVerifier([]string{"secret"}).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		received = string(b)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	for key, values := range signedHeader([]string{"secret"}, time.Now(), []byte(body)) {
+		req.Header[key] = values
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, body, received)
+
+	received = ""
+	req = httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Empty(t, received)
+}
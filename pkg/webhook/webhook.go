@@ -0,0 +1,72 @@
+// Package webhook signs the HTTP requests sent by the scheduler and verifies
+// them on the receiving side.
+//
+// A signed request carries two headers:
+//
+//	X-Scheduler-Timestamp: 1767261600
+//	X-Scheduler-Signature: v1=5257a869...,v1=0f1e2d3c...
+//
+// The timestamp is the Unix time in seconds at which the request was signed.
+// Each v1 entry is the hex-encoded HMAC-SHA256 of the timestamp, a ".", and
+// the raw request body, computed with one of the task's secrets. While a
+// secret is being rotated the scheduler signs with every active secret, so a
+// receiver accepts the request as long as it knows any one of them.
+//
+// Receiving services verify requests with a Verifier:
+//
+//	verifier := webhook.NewVerifier([]string{os.Getenv("WEBHOOK_SECRET")})
+//	http.Handle("/webhook", verifier.Middleware(handler))
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultSignatureHeader is the header that carries the signatures.
+	DefaultSignatureHeader = "X-Scheduler-Signature"
+	// DefaultTimestampHeader is the header that carries the signing time.
+	DefaultTimestampHeader = "X-Scheduler-Timestamp"
+
+	// signatureScheme prefixes each signature in the signature header.
+	signatureScheme = "v1"
+)
+
+// Sign returns the hex-encoded HMAC-SHA256 of the timestamp and body computed with secret.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	return hex.EncodeToString(mac([]byte(secret), formatTimestamp(timestamp), body))
+}
+
+// SignatureHeaderValue returns the value of the signature header for a request
+// signed with every secret in secrets.
+func SignatureHeaderValue(secrets []string, timestamp time.Time, body []byte) string {
+	signatures := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		signatures = append(signatures, signatureScheme+"="+Sign(secret, timestamp, body))
+	}
+	return strings.Join(signatures, ",")
+}
+
+// TimestampHeaderValue returns the value of the timestamp header for a request signed at timestamp.
+func TimestampHeaderValue(timestamp time.Time) string {
+	return formatTimestamp(timestamp)
+}
+
+// formatTimestamp formats t as Unix seconds.
+func formatTimestamp(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
+
+// mac computes the HMAC-SHA256 of the timestamp, a ".", and the body.
+func mac(secret []byte, timestamp string, body []byte) []byte {
+	h := hmac.New(sha256.New, secret)
+	h.Write([]byte(timestamp))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}